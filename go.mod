@@ -4,6 +4,7 @@ go 1.25.4
 
 require (
 	github.com/google/uuid v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
 	modernc.org/sqlite v1.40.1
 )
 
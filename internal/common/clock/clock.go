@@ -0,0 +1,15 @@
+package clock
+
+import "time"
+
+// Clock gives the current time, so that time driven code can be run at any date
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the clock of the machine, in UTC
+type System struct{}
+
+func (System) Now() time.Time {
+	return time.Now().UTC()
+}
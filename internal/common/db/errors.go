@@ -0,0 +1,48 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// IsUniqueViolation reports whether err comes from a UNIQUE or PRIMARY KEY constraint.
+func IsUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE ||
+		sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// sessionFinalized is raised by the triggers of schema.sql guarding finalized sessions
+const sessionFinalized = "vote session is finalized"
+
+// IsSessionFinalized reports whether err comes from a write to a finalized vote session.
+func IsSessionFinalized(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_TRIGGER &&
+		strings.Contains(sqliteErr.Error(), sessionFinalized)
+}
+
+// sessionClosed is raised by the triggers of schema.sql refusing ballots once a session is closed
+const sessionClosed = "vote session is closed"
+
+// IsSessionClosed reports whether err comes from a ballot cast in a closed vote session.
+func IsSessionClosed(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_TRIGGER &&
+		strings.Contains(sqliteErr.Error(), sessionClosed)
+}
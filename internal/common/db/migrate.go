@@ -0,0 +1,238 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SchemaVersion is the version of schema.sql, kept in PRAGMA user_version of the database.
+// Bump it with every change of schema.sql : InitializeSchemas upgrades the older databases.
+const SchemaVersion = 2
+
+var ErrNewerSchema = errors.New("database schema is newer than this binary")
+
+// upgrades are the data changes of each version, run on the rebuilt tables before the
+// triggers of schema.sql are created again
+var upgrades = map[int][]string{
+	// revote is no tie-break rule anymore, its tie stayed like with none
+	2: {`UPDATE question SET tie_break = 'none' WHERE tie_break = 'revote'`},
+}
+
+// migrate upgrades a database older than SchemaVersion. Tables, indexes and triggers of
+// schema.sql are IF NOT EXISTS, which keeps an existing table in its old shape : every table
+// whose definition changed is rebuilt with the definition of schema.sql and the columns it
+// shares with the old one, the missing tables are created, then the upgrades of each version
+// change the data and schema.sql creates the rest. Indexes and triggers hold no data, they are
+// all dropped and created again.
+// conn runs without foreign keys, dropping the old table would cascade to the tables referencing
+// it, and with legacy_alter_table, renaming it aside would move their references along.
+func migrate(ctx context.Context, conn *sql.Conn, schema string, from int) error {
+	target, err := tableDefinitions(ctx, schema)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := dropDerived(ctx, tx); err != nil {
+		return err
+	}
+
+	current, err := definitions(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for name, definition := range target {
+		old, ok := current[name]
+		switch {
+		case !ok:
+			// created now for the upgrades, schema.sql leaves it
+			if _, err := tx.ExecContext(ctx, definition); err != nil {
+				return fmt.Errorf("failed to create table %s: %w", name, err)
+			}
+		case old != definition:
+			if err := rebuildTable(ctx, tx, name, definition); err != nil {
+				return err
+			}
+		}
+	}
+
+	for v := from + 1; v <= SchemaVersion; v++ {
+		for _, upgrade := range upgrades[v] {
+			if _, err := tx.ExecContext(ctx, upgrade); err != nil {
+				return fmt.Errorf("failed to upgrade data to version %d: %w", v, err)
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("failed to execute schema: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, SchemaVersion)); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+
+	return nil
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// tableDefinitions returns the tables of schema as sqlite stores them, by name
+func tableDefinitions(ctx context.Context, schema string) (map[string]string, error) {
+	scratch, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer scratch.Close()
+
+	// one connection : each one opens its own in-memory database
+	scratch.SetMaxOpenConns(1)
+
+	if _, err := scratch.ExecContext(ctx, schema); err != nil {
+		return nil, fmt.Errorf("failed to execute schema: %w", err)
+	}
+
+	return definitions(ctx, scratch)
+}
+
+func definitions(ctx context.Context, q querier) (map[string]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT name, sql
+		FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make(map[string]string)
+	for rows.Next() {
+		var name, definition string
+		if err := rows.Scan(&name, &definition); err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		tables[name] = definition
+	}
+
+	return tables, rows.Err()
+}
+
+func dropDerived(ctx context.Context, tx *sql.Tx) error {
+	// automatic indexes, of UNIQUE and PRIMARY KEY, have no sql
+	rows, err := tx.QueryContext(ctx, `
+		SELECT type, name
+		FROM sqlite_master
+		WHERE type IN ('index', 'trigger') AND sql IS NOT NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to list indexes and triggers: %w", err)
+	}
+
+	var drops []string
+	for rows.Next() {
+		var kind, name string
+		if err := rows.Scan(&kind, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan index or trigger: %w", err)
+		}
+		drops = append(drops, fmt.Sprintf(`DROP %s %s`, strings.ToUpper(kind), quote(name)))
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, drop := range drops {
+		if _, err := tx.ExecContext(ctx, drop); err != nil {
+			return fmt.Errorf("failed to %s: %w", strings.ToLower(drop), err)
+		}
+	}
+
+	return nil
+}
+
+// rebuildTable creates name with definition and copies the rows of the old table into it,
+// a new NOT NULL column takes its default
+func rebuildTable(ctx context.Context, tx *sql.Tx, name, definition string) error {
+	old := name + "_before_migration"
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, quote(name), quote(old))); err != nil {
+		return fmt.Errorf("failed to rename table %s: %w", name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, definition); err != nil {
+		return fmt.Errorf("failed to create table %s: %w", name, err)
+	}
+
+	oldColumns, err := columns(ctx, tx, old)
+	if err != nil {
+		return err
+	}
+
+	newColumns, err := columns(ctx, tx, name)
+	if err != nil {
+		return err
+	}
+
+	var shared []string
+	for _, c := range newColumns {
+		for _, o := range oldColumns {
+			if c == o {
+				shared = append(shared, quote(c))
+				break
+			}
+		}
+	}
+
+	list := strings.Join(shared, ", ")
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s`, quote(name), list, list, quote(old)))
+	if err != nil {
+		return fmt.Errorf("failed to copy table %s: %w", name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s`, quote(old))); err != nil {
+		return fmt.Errorf("failed to drop table %s: %w", old, err)
+	}
+
+	return nil
+}
+
+func columns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan column of %s: %w", table, err)
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
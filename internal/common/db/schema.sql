@@ -1,3 +1,5 @@
+-- Bump db.SchemaVersion with every change of this file, InitializeSchemas upgrades older databases
+
 
 -- Users 
 CREATE TABLE IF NOT EXISTS "user" (
@@ -23,28 +25,61 @@ CREATE TABLE IF NOT EXISTS vote_session (
     title TEXT NOT NULL,
     description TEXT,
     created_at TEXT NOT NULL DEFAULT (datetime('now')),
-    ends_at TEXT
+    starts_at TEXT, -- a scheduled session is opened at starts_at
+    ends_at TEXT,
+    quorum_kind TEXT NOT NULL DEFAULT 'none', -- none | absolute | percent
+    quorum_value INTEGER NOT NULL DEFAULT 0, -- voters, or percentage of participants
+    secret_ballot INTEGER NOT NULL DEFAULT 0, -- votes are stored without voter
+    live_results INTEGER NOT NULL DEFAULT 0, -- running tallies pushed to the live stream
+    results_visibility TEXT NOT NULL DEFAULT 'public', -- public | after_close | organizers | voters
+    state TEXT NOT NULL DEFAULT 'draft', -- draft | scheduled | open | closed | archived
+    template INTEGER NOT NULL DEFAULT 0 -- a reusable draft, cloned into sessions but never opened
 );
 
 CREATE TABLE IF NOT EXISTS session_and_participant (
     user_id TEXT NOT NULL,
     session_id TEXT NOT NULL,
     invited_at TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'participant', -- participant | organizer
+    weight INTEGER NOT NULL DEFAULT 1, -- shares: every ballot of the participant counts weight times
     PRIMARY KEY (user_id, session_id)
 );
 
 CREATE INDEX IF NOT EXISTS idx_participant_user ON session_and_participant(user_id);
 CREATE INDEX IF NOT EXISTS idx_participant_session ON session_and_participant(session_id);
 
+-- Invitations to join a session, the token is given once: only its sha256 is stored.
+-- A pending invitation past expires_at is expired, the status is never written as such.
+CREATE TABLE IF NOT EXISTS session_invitation (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    email TEXT NOT NULL,
+    weight INTEGER NOT NULL DEFAULT 1, -- weight of the participant once accepted
+    token_hash TEXT NOT NULL UNIQUE, -- sha256 hex of the token
+    status TEXT NOT NULL DEFAULT 'pending', -- pending | accepted | declined
+    user_id TEXT, -- the user who accepted
+    created_at TEXT NOT NULL,
+    expires_at TEXT NOT NULL,
+    answered_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitation_session ON session_invitation(session_id);
+
 -- Questions and choices
 -- TODO unique id ? indepotent
 CREATE TABLE IF NOT EXISTS question (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     session_id TEXT NOT NULL,
     text TEXT NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'choice', -- choice | ranked | approval | score | cumulative
+    tally_method TEXT NOT NULL DEFAULT 'plurality', -- plurality | irv | schulze | ranked_pairs | stv | sum
     order_num INTEGER NOT NULL,
     allow_multiple INTEGER NOT NULL DEFAULT 0,
-    max_choices INTEGER NOT NULL DEFAULT 1,
+    max_choices INTEGER NOT NULL DEFAULT 1, -- 0 for an approval question without limit
+    max_points INTEGER NOT NULL DEFAULT 0, -- score: per choice, cumulative: to distribute
+    seats INTEGER NOT NULL DEFAULT 1, -- more than 1 with stv only
+    threshold TEXT NOT NULL DEFAULT 'none', -- none | simple | absolute | supermajority
+    tie_break TEXT NOT NULL DEFAULT 'none', -- none | organizer | order | random
     created_at TEXT NOT NULL DEFAULT (datetime('now')),
     UNIQUE (session_id, order_num)
 );
@@ -66,12 +101,13 @@ CREATE INDEX IF NOT EXISTS idx_choice_question ON choice(question_id);
 -- Votes
 CREATE TABLE IF NOT EXISTS vote (
     id TEXT PRIMARY KEY,
-    user_id TEXT NOT NULL,
+    user_id TEXT, -- NULL for a secret ballot
     session_id TEXT NOT NULL,
     question_id INTEGER NOT NULL,
-    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    weight INTEGER NOT NULL DEFAULT 1, -- weight of the voter when the vote was cast
+    created_at TEXT, -- NULL for a secret ballot
     UNIQUE (user_id, question_id)
-);
+) WITHOUT ROWID; -- stored by id: the order of secret votes must not match the receipts dates
 
 CREATE INDEX IF NOT EXISTS idx_vote_user ON vote(user_id);
 CREATE INDEX IF NOT EXISTS idx_vote_session ON vote(session_id);
@@ -80,15 +116,73 @@ CREATE INDEX IF NOT EXISTS idx_vote_question ON vote(question_id);
 CREATE TABLE IF NOT EXISTS vote_and_choice (
     vote_id TEXT NOT NULL,
     choice_id INTEGER NOT NULL,
+    rank INTEGER, -- preference position (1 = first) for ranked questions
+    points INTEGER, -- score and cumulative questions
     PRIMARY KEY (vote_id, choice_id),
     FOREIGN KEY (vote_id) REFERENCES vote(id) ON DELETE CASCADE,
     FOREIGN KEY (choice_id) REFERENCES choice(id) ON DELETE CASCADE
-);
+) WITHOUT ROWID;
 
 CREATE INDEX IF NOT EXISTS idx_vote_choice_vote ON vote_and_choice(vote_id);
 CREATE INDEX IF NOT EXISTS idx_vote_choice_choice ON vote_and_choice(choice_id);
 
+-- Who voted in a secret ballot session, the votes themselves carry no user id.
+-- No timestamp and no rowid: rows are kept by key, not in the order the votes were cast.
+CREATE TABLE IF NOT EXISTS vote_participation (
+    question_id INTEGER NOT NULL,
+    user_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    weight INTEGER NOT NULL DEFAULT 1, -- always 1: the participants of a secret ballot are not weighted
+    PRIMARY KEY (question_id, user_id)
+) WITHOUT ROWID;
+
+CREATE INDEX IF NOT EXISTS idx_vote_participation_session ON vote_participation(session_id);
+
+-- Votes changed or revoked by their voter, "vote" only keeps the latest one
+CREATE TABLE IF NOT EXISTS vote_history (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    question_id INTEGER NOT NULL,
+    choice_ids TEXT NOT NULL, -- JSON array, in preference order when ranked
+    ranked INTEGER NOT NULL DEFAULT 0,
+    points TEXT, -- JSON array matching choice_ids, score and cumulative questions
+    weight INTEGER NOT NULL DEFAULT 1,
+    reason TEXT NOT NULL, -- changed | revoked
+    replaced_by TEXT,
+    created_at TEXT NOT NULL,
+    superseded_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_vote_history_user_session ON vote_history(user_id, session_id);
+
+-- Tie-breaks
+-- The seed of a question with the random rule is committed (sha256 hex of the seed) before
+-- its first ballot and revealed with the results once the session is closed.
+CREATE TABLE IF NOT EXISTS tie_break_seed (
+    question_id INTEGER PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    seed TEXT NOT NULL, -- hex
+    commitment TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tie_break_seed_session ON tie_break_seed(session_id);
+
+-- Choice of an organizer for a tied question with the organizer rule
+CREATE TABLE IF NOT EXISTS tie_break_decision (
+    question_id INTEGER PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    choice_id INTEGER NOT NULL,
+    decided_by TEXT NOT NULL,
+    decided_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tie_break_decision_session ON tie_break_decision(session_id);
+
 -- User history and results
+-- user_history holds the signed receipt of the last ballot of a voter, without entries once revoked:
+-- receipt_data = payload || ed25519 signature, string_size = payload size, checksum = sha256 of the payload
 CREATE TABLE IF NOT EXISTS user_history (
     id TEXT PRIMARY KEY,
     user_id TEXT NOT NULL,
@@ -120,4 +214,176 @@ CREATE TABLE IF NOT EXISTS result_history (
 );
 
 CREATE INDEX IF NOT EXISTS idx_result_history_session ON result_history(session_id);
-CREATE INDEX IF NOT EXISTS idx_result_history_checksum ON result_history(checksum);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_result_history_checksum ON result_history(checksum);
+
+-- Finalized sessions
+-- A session is finalized once its result snapshot is in result_history:
+-- its questions, choices and votes can no longer change, nor the snapshot itself.
+-- The message is matched by db.IsSessionFinalized.
+CREATE TRIGGER IF NOT EXISTS result_history_no_update BEFORE UPDATE ON result_history
+BEGIN
+    SELECT RAISE(ABORT, 'result snapshot is immutable');
+END;
+
+CREATE TRIGGER IF NOT EXISTS result_history_no_delete BEFORE DELETE ON result_history
+BEGIN
+    SELECT RAISE(ABORT, 'result snapshot is immutable');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_session_finalized_update BEFORE UPDATE OF ends_at, quorum_kind, quorum_value, secret_ballot ON vote_session
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = OLD.id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_session_finalized_delete BEFORE DELETE ON vote_session
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = OLD.id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS question_finalized_insert BEFORE INSERT ON question
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = NEW.session_id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS question_finalized_update BEFORE UPDATE ON question
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id IN (OLD.session_id, NEW.session_id))
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS question_finalized_delete BEFORE DELETE ON question
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = OLD.session_id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS choice_finalized_insert BEFORE INSERT ON choice
+WHEN EXISTS (
+    SELECT 1 FROM question q JOIN result_history r ON r.session_id = q.session_id
+    WHERE q.id = NEW.question_id
+)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS choice_finalized_update BEFORE UPDATE ON choice
+WHEN EXISTS (
+    SELECT 1 FROM question q JOIN result_history r ON r.session_id = q.session_id
+    WHERE q.id IN (OLD.question_id, NEW.question_id)
+)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS choice_finalized_delete BEFORE DELETE ON choice
+WHEN EXISTS (
+    SELECT 1 FROM question q JOIN result_history r ON r.session_id = q.session_id
+    WHERE q.id = OLD.question_id
+)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_finalized_insert BEFORE INSERT ON vote
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = NEW.session_id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_finalized_update BEFORE UPDATE ON vote
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id IN (OLD.session_id, NEW.session_id))
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_finalized_delete BEFORE DELETE ON vote
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = OLD.session_id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_and_choice_finalized_insert BEFORE INSERT ON vote_and_choice
+WHEN EXISTS (
+    SELECT 1 FROM vote v JOIN result_history r ON r.session_id = v.session_id
+    WHERE v.id = NEW.vote_id
+)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_and_choice_finalized_update BEFORE UPDATE ON vote_and_choice
+WHEN EXISTS (
+    SELECT 1 FROM vote v JOIN result_history r ON r.session_id = v.session_id
+    WHERE v.id IN (OLD.vote_id, NEW.vote_id)
+)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_and_choice_finalized_delete BEFORE DELETE ON vote_and_choice
+WHEN EXISTS (
+    SELECT 1 FROM vote v JOIN result_history r ON r.session_id = v.session_id
+    WHERE v.id = OLD.vote_id
+)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_participation_finalized_insert BEFORE INSERT ON vote_participation
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = NEW.session_id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+-- a ballot checked while the session was open must not land once it is closed: it would be
+-- missing from the tally of the finalization
+CREATE TRIGGER IF NOT EXISTS vote_closed_insert BEFORE INSERT ON vote
+WHEN EXISTS (SELECT 1 FROM vote_session WHERE id = NEW.session_id AND state IN ('closed', 'archived'))
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is closed');
+END;
+
+CREATE TRIGGER IF NOT EXISTS vote_participation_closed_insert BEFORE INSERT ON vote_participation
+WHEN EXISTS (SELECT 1 FROM vote_session WHERE id = NEW.session_id AND state IN ('closed', 'archived'))
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is closed');
+END;
+
+CREATE TRIGGER IF NOT EXISTS tie_break_seed_finalized_insert BEFORE INSERT ON tie_break_seed
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = NEW.session_id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS tie_break_seed_finalized_update BEFORE UPDATE ON tie_break_seed
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id IN (OLD.session_id, NEW.session_id))
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS tie_break_seed_finalized_delete BEFORE DELETE ON tie_break_seed
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = OLD.session_id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS tie_break_decision_finalized_insert BEFORE INSERT ON tie_break_decision
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = NEW.session_id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS tie_break_decision_finalized_update BEFORE UPDATE ON tie_break_decision
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id IN (OLD.session_id, NEW.session_id))
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
+
+CREATE TRIGGER IF NOT EXISTS tie_break_decision_finalized_delete BEFORE DELETE ON tie_break_decision
+WHEN EXISTS (SELECT 1 FROM result_history WHERE session_id = OLD.session_id)
+BEGIN
+    SELECT RAISE(ABORT, 'vote session is finalized');
+END;
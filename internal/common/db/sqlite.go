@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"fmt"
@@ -21,16 +22,51 @@ func OpenSQLite(dsn string) (*sql.DB, func(), error) {
 	return db, cleanup, nil
 }
 
+// InitializeSchemas creates the schema in an empty database and upgrades an older one,
+// it refuses a database written by a newer binary
 func InitializeSchemas(db *sql.DB) error {
 	sqlBytes, err := fs.ReadFile(schemaFS, "schema.sql")
 	if err != nil {
 		return fmt.Errorf("failed to read schema.sql: %w", err)
 	}
 
-	_, err = db.Exec(string(sqlBytes))
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var version int
+	if err := conn.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	switch {
+	case version > SchemaVersion:
+		return fmt.Errorf("%w: version %d, this binary knows %d", ErrNewerSchema, version, SchemaVersion)
+	case version == SchemaVersion:
+		return nil
+	}
+
+	var foreignKeys int
+	if err := conn.QueryRowContext(ctx, `PRAGMA foreign_keys`).Scan(&foreignKeys); err != nil {
+		return fmt.Errorf("failed to read foreign keys: %w", err)
+	}
+
+	// both only change outside a transaction
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF; PRAGMA legacy_alter_table = ON`); err != nil {
+		return fmt.Errorf("failed to prepare migration: %w", err)
+	}
+
+	err = migrate(ctx, conn, string(sqlBytes), version)
+
+	_, restoreErr := conn.ExecContext(ctx, fmt.Sprintf(`PRAGMA foreign_keys = %d; PRAGMA legacy_alter_table = OFF`, foreignKeys))
+
 	if err != nil {
-		return fmt.Errorf("failed to execute schema: %w", err)
+		return fmt.Errorf("failed to migrate schema from version %d: %w", version, err)
 	}
 
-	return nil
+	return restoreErr
 }
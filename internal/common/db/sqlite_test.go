@@ -3,6 +3,8 @@ package db_test
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/73NN0/voting-app/internal/common/db"
@@ -47,6 +49,7 @@ func TestInitializeDatabaseSchemas(t *testing.T) {
 		"choice",
 		"vote",
 		"vote_and_choice",
+		"vote_history",
 		"user_history",
 		"result_history",
 	}
@@ -56,6 +59,119 @@ func TestInitializeDatabaseSchemas(t *testing.T) {
 	}
 }
 
+// schema de vote_session et vote avant le versionnement du schéma
+const legacySchema = `
+CREATE TABLE vote_session (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    description TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    ends_at TEXT
+);
+
+CREATE TABLE vote (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    question_id INTEGER NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (user_id, question_id)
+);
+
+INSERT INTO vote_session (id, title) VALUES ('s1', 'AG');
+INSERT INTO vote (id, user_id, session_id, question_id) VALUES ('v1', 'u1', 's1', 1);
+`
+
+func TestInitializeSchemas_UpgradesOlderDatabase(t *testing.T) {
+	// GIVEN une base créée avant le versionnement du schéma
+	database, cleanup, err := db.OpenSQLite(filepath.Join(t.TempDir(), "voting.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if _, err := database.Exec(legacySchema); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on initialise le schéma
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN les tables ont les colonnes du schéma, les lignes sont gardées
+	var state string
+	var weight, version int
+	if err := database.QueryRow(`SELECT state FROM vote_session WHERE id = 's1'`).Scan(&state); err != nil || state != "draft" {
+		t.Errorf("session state = %q, %v, want draft", state, err)
+	}
+	if err := database.QueryRow(`SELECT weight FROM vote WHERE id = 'v1'`).Scan(&weight); err != nil || weight != 1 {
+		t.Errorf("vote weight = %d, %v, want 1", weight, err)
+	}
+	if err := database.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil || version != db.SchemaVersion {
+		t.Errorf("user_version = %d, %v, want %d", version, err, db.SchemaVersion)
+	}
+
+	// AND un vote secret, sans votant, est accepté
+	if _, err := database.Exec(`INSERT INTO vote (id, session_id, question_id) VALUES ('v2', 's1', 1)`); err != nil {
+		t.Errorf("secret vote refused: %v", err)
+	}
+
+	// AND les tables ajoutées depuis existent
+	assertTableExists(t, database, "session_invitation")
+}
+
+func TestInitializeSchemas_DropsRevoteTieBreak(t *testing.T) {
+	// GIVEN une base en version 1 avec une question départagée par revote
+	database, cleanup, err := db.OpenSQLite(filepath.Join(t.TempDir(), "voting.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.Exec(`
+		INSERT INTO question (session_id, text, order_num, tie_break) VALUES ('s1', 'Oui ?', 1, 'revote');
+		PRAGMA user_version = 1;
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on initialise le schéma
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN l'égalité reste, sans revote
+	var tieBreak string
+	if err := database.QueryRow(`SELECT tie_break FROM question`).Scan(&tieBreak); err != nil || tieBreak != "none" {
+		t.Errorf("tie_break = %q, %v, want none", tieBreak, err)
+	}
+}
+
+func TestInitializeSchemas_RefusesNewerDatabase(t *testing.T) {
+	// GIVEN une base écrite par un binaire plus récent
+	database, cleanup, err := db.OpenSQLite(filepath.Join(t.TempDir(), "voting.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if _, err := database.Exec(`PRAGMA user_version = 1000`); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on initialise le schéma
+	err = db.InitializeSchemas(database)
+
+	// THEN il est refusé
+	if !errors.Is(err, db.ErrNewerSchema) {
+		t.Errorf("InitializeSchemas() err = %v, want %v", err, db.ErrNewerSchema)
+	}
+}
+
 func TestOpenDb(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -19,10 +19,30 @@ func BadRequest(w http.ResponseWriter, msg string) {
 	Error(w, msg, http.StatusBadRequest)
 }
 
+func Unauthorized(w http.ResponseWriter, msg string) {
+	Error(w, msg, http.StatusUnauthorized)
+}
+
+func Forbidden(w http.ResponseWriter, msg string) {
+	Error(w, msg, http.StatusForbidden)
+}
+
 func NotFound(w http.ResponseWriter, msg string) {
 	Error(w, msg, http.StatusNotFound)
 }
 
+func Conflict(w http.ResponseWriter, msg string) {
+	Error(w, msg, http.StatusConflict)
+}
+
+func Gone(w http.ResponseWriter, msg string) {
+	Error(w, msg, http.StatusGone)
+}
+
+func UnprocessableEntity(w http.ResponseWriter, msg string) {
+	Error(w, msg, http.StatusUnprocessableEntity)
+}
+
 func InternalServerError(w http.ResponseWriter, msg string) {
 	Error(w, msg, http.StatusInternalServerError)
 }
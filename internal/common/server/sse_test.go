@@ -0,0 +1,61 @@
+package server_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/common/server"
+)
+
+func TestBroker_CoalescesSignals(t *testing.T) {
+	b := server.NewBroker()
+
+	// GIVEN deux abonnés sur deux sujets
+	changes, unsubscribe := b.Subscribe("a")
+	other, _ := b.Subscribe("b")
+
+	// WHEN le sujet a est publié plusieurs fois sans lecture
+	b.Publish("a")
+	b.Publish("a")
+	b.Publish("a")
+
+	// THEN un seul signal attend, et seulement sur a
+	if len(changes) != 1 {
+		t.Errorf("expected 1 pending signal, got %d", len(changes))
+	}
+
+	if len(other) != 0 {
+		t.Error("subscriber of b got a signal of a")
+	}
+
+	// AND un abonné parti ne reçoit plus rien
+	<-changes
+	unsubscribe()
+	b.Publish("a")
+
+	if len(changes) != 0 {
+		t.Error("unsubscribed channel got a signal")
+	}
+}
+
+func TestEventStream_Send(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	stream := server.NewEventStream(w)
+	if err := stream.Send("update", map[string]int{"voters": 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("content type: got %q", got)
+	}
+
+	if !strings.Contains(w.Body.String(), "event: update\ndata: {\"voters\":2}\n\n") {
+		t.Errorf("unexpected body %q", w.Body.String())
+	}
+
+	if !w.Flushed {
+		t.Error("event was not flushed")
+	}
+}
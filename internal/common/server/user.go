@@ -0,0 +1,41 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// UserIDHeader carries the id of the calling user. Any caller can set it: it is only read
+// once TrustUserIDHeader is called, by the binaries started with -dev-user-header.
+// TODO : replace by a real authentication (session cookie / token)
+const UserIDHeader = "X-User-ID"
+
+var (
+	ErrMissingUserID    = errors.New("missing or invalid " + UserIDHeader + " header")
+	ErrNoAuthentication = errors.New("no authentication: " + UserIDHeader + " is only read in development (-dev-user-header)")
+)
+
+var trustUserIDHeader atomic.Bool
+
+// TrustUserIDHeader makes UserID read the forgeable UserIDHeader, for development only
+func TrustUserIDHeader(trust bool) {
+	trustUserIDHeader.Store(trust)
+}
+
+// UserID : without a trusted identity every caller is anonymous, so the checks built on it
+// (organizer, participant, results visibility) refuse instead of trusting the caller
+func UserID(r *http.Request) (uuid.UUID, error) {
+	if !trustUserIDHeader.Load() {
+		return uuid.Nil, ErrNoAuthentication
+	}
+
+	id, err := uuid.Parse(r.Header.Get(UserIDHeader))
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, ErrMissingUserID
+	}
+
+	return id, nil
+}
@@ -0,0 +1,48 @@
+package server_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/common/server"
+	"github.com/google/uuid"
+)
+
+func TestUserID(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		trust   bool
+		header  string
+		wantErr error
+	}{
+		{"en-tête ignoré hors développement", false, id.String(), server.ErrNoAuthentication},
+		{"en-tête lu en développement", true, id.String(), nil},
+		{"en-tête invalide", true, "alice", server.ErrMissingUserID},
+		{"en-tête absent", true, "", server.ErrMissingUserID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN une requête portant l'en-tête
+			server.TrustUserIDHeader(tt.trust)
+			t.Cleanup(func() { server.TrustUserIDHeader(false) })
+
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set(server.UserIDHeader, tt.header)
+
+			// WHEN on lit l'appelant
+			got, err := server.UserID(r)
+
+			// THEN
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UserID() err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != id {
+				t.Errorf("UserID() = %s, want %s", got, id)
+			}
+		})
+	}
+}
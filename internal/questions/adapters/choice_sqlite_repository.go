@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/questions/app"
 	"github.com/73NN0/voting-app/internal/questions/domain/choice"
 )
 
@@ -60,14 +61,26 @@ func NewSqliteChoicesRepositoy(db *sql.DB) *SqliteChoicesRepository {
 }
 
 func (r *SqliteChoicesRepository) CreateChoice(ctx context.Context, c choice.Choice) (int, error) {
+	return insertChoice(ctx, r.db, c)
+}
+
+// CreateChoiceTx creates the choice in the transaction of another context, which commits it
+func (r *SqliteChoicesRepository) CreateChoiceTx(ctx context.Context, tx *sql.Tx, c choice.Choice) (int, error) {
+	return insertChoice(ctx, tx, c)
+}
+
+func insertChoice(ctx context.Context, exec execer, c choice.Choice) (int, error) {
 	dto := toChoiceDTO(&c)
 
-	result, err := r.db.ExecContext(ctx, `
+	result, err := exec.ExecContext(ctx, `
 		INSERT INTO choice (question_id, text, order_num)
 		VALUES (?, ?, ?)
 	`, dto.QuestionID, dto.Text, dto.OrderNum)
 
 	if err != nil {
+		if db.IsSessionFinalized(err) {
+			return 0, app.ErrSessionFinalized
+		}
 		return 0, fmt.Errorf("failed to insert choice: %w", err)
 	}
 
@@ -151,6 +164,10 @@ func (q *SqliteChoicesRepository) DeleteChoice(ctx context.Context, choiceID int
 		DELETE FROM choice WHERE id = ?
 	`, choiceID)
 
+	if db.IsSessionFinalized(err) {
+		return app.ErrSessionFinalized
+	}
+
 	return err
 }
 
@@ -163,6 +180,9 @@ func (r *SqliteChoicesRepository) UpdateChoice(ctx context.Context, q choice.Cho
 		SET text = ?, order_num = ?, question_id = ?
 		WHERE id = ?
 	`, q.Text(), q.OrderNum(), q.QuestionID(), id); err != nil {
+		if db.IsSessionFinalized(err) {
+			return app.ErrSessionFinalized
+		}
 		return fmt.Errorf("failed to update choice %d : %w", id, err)
 	}
 
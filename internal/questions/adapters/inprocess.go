@@ -4,23 +4,30 @@ import (
 	"context"
 	"errors"
 
+	"github.com/73NN0/voting-app/internal/common/clock"
 	"github.com/73NN0/voting-app/internal/questions/app"
 	"github.com/73NN0/voting-app/internal/sessions/domain/session"
 	"github.com/google/uuid"
 )
 
 type SessionCheckerInProcess struct {
-	repo session.Repository
+	repo  session.Repository
+	clock clock.Clock
 }
 
 var _ app.SessionChecker = (*SessionCheckerInProcess)(nil)
 
-func NewSessionCheckerInProcess(repo session.Repository) *SessionCheckerInProcess {
+// NewSessionCheckerInProcess : clk decides the state of the sessions, it is the clock of the scheduler
+func NewSessionCheckerInProcess(repo session.Repository, clk clock.Clock) *SessionCheckerInProcess {
 	if repo == nil {
 		panic(" missing session repository")
 	}
 
-	return &SessionCheckerInProcess{repo: repo}
+	if clk == nil {
+		panic(" missing clock")
+	}
+
+	return &SessionCheckerInProcess{repo: repo, clock: clk}
 }
 
 func (c *SessionCheckerInProcess) Exists(ctx context.Context, sessionID uuid.UUID) (bool, error) {
@@ -35,3 +42,15 @@ func (c *SessionCheckerInProcess) Exists(ctx context.Context, sessionID uuid.UUI
 
 	return false, err
 }
+
+func (c *SessionCheckerInProcess) IsEditable(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	s, err := c.repo.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return false, app.ErrVoteSessionNotFound
+		}
+		return false, err
+	}
+
+	return s.StateAt(c.clock.Now()).Editable(), nil
+}
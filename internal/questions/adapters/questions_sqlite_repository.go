@@ -8,6 +8,7 @@ import (
 	"github.com/73NN0/voting-app/internal/common/db"
 	"github.com/google/uuid"
 
+	"github.com/73NN0/voting-app/internal/questions/app"
 	"github.com/73NN0/voting-app/internal/questions/domain/question"
 )
 
@@ -17,9 +18,15 @@ type questionDTO struct {
 	ID            int          // INTEGER AUTOINCREMENT
 	SessionID     string       // TEXT (uuid en string)
 	Text          string       // TEXT
+	Kind          string       // TEXT (choice | ranked | approval | score | cumulative)
+	TallyMethod   string       // TEXT (plurality | irv | schulze | ranked_pairs | stv | sum)
 	OrderNum      int          // INTEGER
 	AllowMultiple int          // INTEGER (0 or 1, SQLite doesn't have a natif boolean)
 	MaxChoices    int          // INTEGER
+	MaxPoints     int          // INTEGER
+	Seats         int          // INTEGER
+	Threshold     string       // TEXT (none | simple | absolute | supermajority)
+	TieBreak      string       // TEXT (none | organizer | order | random)
 	CreatedAt     db.Timestamp // TEXT (format ISO)
 }
 
@@ -33,9 +40,15 @@ func toQuestionDTO(s *question.Question) questionDTO {
 		ID:            s.ID(), // 0 pour un INSERT
 		SessionID:     s.SessionID().String(),
 		Text:          s.Text(),
+		Kind:          string(s.Kind()),
+		TallyMethod:   string(s.Method()),
 		OrderNum:      s.OrderNum(),
 		AllowMultiple: allowMultiple,
 		MaxChoices:    s.MaxChoices(),
+		MaxPoints:     s.MaxPoints(),
+		Seats:         s.Seats(),
+		Threshold:     string(s.Threshold()),
+		TieBreak:      string(s.TieBreak()),
 		CreatedAt:     db.Timestamp{Time: s.CreatedAt()},
 	}
 }
@@ -52,13 +65,23 @@ func (dto questionDTO) toQuestion() (question.Question, error) {
 		dto.ID,
 		sessionID,
 		dto.Text,
+		question.Kind(dto.Kind),
+		question.Method(dto.TallyMethod),
 		dto.OrderNum,
 		allowMultiple,
 		dto.MaxChoices,
+		dto.MaxPoints,
+		dto.Seats,
+		question.Threshold(dto.Threshold),
+		question.TieBreak(dto.TieBreak),
 		dto.CreatedAt.Time,
 	)
 
-	return *ptr, err
+	if err != nil {
+		return question.Question{}, err
+	}
+
+	return *ptr, nil
 }
 
 type SqliteQuestionsRepository struct {
@@ -72,15 +95,31 @@ func NewSqliteQuestionsRepository(db *sql.DB) *SqliteQuestionsRepository {
 
 // ================= Questions ======================= //
 func (s *SqliteQuestionsRepository) CreateQuestion(ctx context.Context, question question.Question) (questionID int, err error) {
+	return insertQuestion(ctx, s.db, question)
+}
+
+// CreateQuestionTx creates the question in the transaction of another context, which commits it
+func (s *SqliteQuestionsRepository) CreateQuestionTx(ctx context.Context, tx *sql.Tx, question question.Question) (int, error) {
+	return insertQuestion(ctx, tx, question)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertQuestion(ctx context.Context, exec execer, question question.Question) (questionID int, err error) {
 	// TODO indepotent ?
 	dto := toQuestionDTO(&question)
 
-	result, err := s.db.ExecContext(ctx, `
-		INSERT INTO question (session_id, text, order_num, allow_multiple, max_choices)
-		VALUES (?, ?, ?, ?, ?)
-	`, dto.SessionID, dto.Text, dto.OrderNum, dto.AllowMultiple, dto.MaxChoices)
+	result, err := exec.ExecContext(ctx, `
+		INSERT INTO question (session_id, text, kind, tally_method, order_num, allow_multiple, max_choices, max_points, seats, threshold, tie_break)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, dto.SessionID, dto.Text, dto.Kind, dto.TallyMethod, dto.OrderNum, dto.AllowMultiple, dto.MaxChoices, dto.MaxPoints, dto.Seats, dto.Threshold, dto.TieBreak)
 
 	if err != nil {
+		if db.IsSessionFinalized(err) {
+			err = app.ErrSessionFinalized
+		}
 		return
 	}
 
@@ -94,16 +133,22 @@ func (r *SqliteQuestionsRepository) GetQuestionByID(ctx context.Context, id int)
 	var dto questionDTO
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, session_id, text, order_num, allow_multiple, max_choices, created_at
+		SELECT id, session_id, text, kind, tally_method, order_num, allow_multiple, max_choices, max_points, seats, threshold, tie_break, created_at
 		FROM question
 		WHERE id = ?
 	`, id).Scan(
 		&dto.ID,
 		&dto.SessionID,
 		&dto.Text,
+		&dto.Kind,
+		&dto.TallyMethod,
 		&dto.OrderNum,
 		&dto.AllowMultiple,
 		&dto.MaxChoices,
+		&dto.MaxPoints,
+		&dto.Seats,
+		&dto.Threshold,
+		&dto.TieBreak,
 		&dto.CreatedAt,
 	)
 
@@ -119,7 +164,7 @@ func (r *SqliteQuestionsRepository) GetQuestionByID(ctx context.Context, id int)
 
 func (r *SqliteQuestionsRepository) GetQuestionsBySessionID(ctx context.Context, sessionID uuid.UUID) ([]question.Question, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, session_id, text, order_num, allow_multiple, max_choices, created_at
+		SELECT id, session_id, text, kind, tally_method, order_num, allow_multiple, max_choices, max_points, seats, threshold, tie_break, created_at
 		FROM question
 		WHERE session_id = ?
 		ORDER BY order_num ASC
@@ -137,9 +182,15 @@ func (r *SqliteQuestionsRepository) GetQuestionsBySessionID(ctx context.Context,
 			&dto.ID,
 			&dto.SessionID,
 			&dto.Text,
+			&dto.Kind,
+			&dto.TallyMethod,
 			&dto.OrderNum,
 			&dto.AllowMultiple,
 			&dto.MaxChoices,
+			&dto.MaxPoints,
+			&dto.Seats,
+			&dto.Threshold,
+			&dto.TieBreak,
 			&dto.CreatedAt,
 		)
 		if err != nil {
@@ -163,6 +214,9 @@ func (r *SqliteQuestionsRepository) GetQuestionsBySessionID(ctx context.Context,
 
 func (r *SqliteQuestionsRepository) DeleteQuestion(ctx context.Context, id int) error {
 	_, err := r.db.ExecContext(ctx, `DELETE FROM question WHERE id = ?`, id)
+	if db.IsSessionFinalized(err) {
+		return app.ErrSessionFinalized
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete question %d: %w", id, err)
 	}
@@ -173,9 +227,12 @@ func (r *SqliteQuestionsRepository) UpdateQuestion(ctx context.Context, q questi
 
 	if _, err := r.db.ExecContext(ctx, `
 		UPDATE question
-		SET text = ?, order_num = ?, allow_multiple = ?, max_choices = ?
+		SET text = ?, tally_method = ?, order_num = ?, allow_multiple = ?, max_choices = ?, seats = ?, threshold = ?, tie_break = ?
 		WHERE id = ?
-	`, q.Text(), q.OrderNum(), q.AllowMultiple(), q.MaxChoices(), q.ID()); err != nil {
+	`, q.Text(), string(q.Method()), q.OrderNum(), q.AllowMultiple(), q.MaxChoices(), q.Seats(), string(q.Threshold()), string(q.TieBreak()), q.ID()); err != nil {
+		if db.IsSessionFinalized(err) {
+			return app.ErrSessionFinalized
+		}
 		return fmt.Errorf("failed to update question %d : %w", q.ID(), err)
 	}
 
@@ -7,6 +7,8 @@ import (
 
 	"github.com/73NN0/voting-app/internal/common/db"
 	"github.com/73NN0/voting-app/internal/questions/adapters"
+	"github.com/73NN0/voting-app/internal/questions/app"
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
 	"github.com/73NN0/voting-app/internal/questions/domain/question"
 	"github.com/google/uuid"
 )
@@ -102,3 +104,108 @@ func TestCreateQuestion(t *testing.T) {
 
 // TODO : Créer deux questions même sessionID + même orderNum → erreur UNIQUE violation
 // Vérifier que l’erreur est bien propagée (pas panic, pas erreur générique)
+
+func TestCreateRankedQuestion_KeepsKind(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteQuestionsRepository(database)
+	ctx := context.Background()
+
+	q, err := question.NewRankedQuestion(uuid.New(), "Classez les candidats", 1, 3, question.MethodSchulze)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := repo.CreateQuestion(ctx, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetched, err := repo.GetQuestionByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fetched.Kind() != question.KindRanked {
+		t.Errorf("kind: got %q, want %q", fetched.Kind(), question.KindRanked)
+	}
+
+	if fetched.Method() != question.MethodSchulze {
+		t.Errorf("method: got %q, want %q", fetched.Method(), question.MethodSchulze)
+	}
+
+	stv, err := question.NewSTVQuestion(q.SessionID(), "Élisez le bureau", 2, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err = repo.CreateQuestion(ctx, stv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetched, err = repo.GetQuestionByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fetched.Method() != question.MethodSTV || fetched.Seats() != 3 {
+		t.Errorf("got %q with %d seats, want stv with 3 seats", fetched.Method(), fetched.Seats())
+	}
+}
+
+func TestQuestionRepository_FinalizedSessionIsFrozen(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	questions := adapters.NewSqliteQuestionsRepository(database)
+	choices := adapters.NewSqliteChoicesRepositoy(database)
+	ctx := context.Background()
+
+	// GIVEN une session avec une question, puis son résultat figé dans result_history
+	sessionID := uuid.New()
+	id, err := questions.CreateQuestion(ctx, mustNewQuestion(t, sessionID, "Budget ?", 1, 1, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := database.ExecContext(ctx, `
+		INSERT INTO result_history (id, session_id, version, string_size, result_data, checksum)
+		VALUES (?, ?, 1, 2, '{}', 'x')
+	`, uuid.NewString(), sessionID.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN / THEN toute écriture sur ses questions et choix est refusée
+	if _, err := questions.CreateQuestion(ctx, mustNewQuestion(t, sessionID, "Autre ?", 2, 1, false)); !errors.Is(err, app.ErrSessionFinalized) {
+		t.Errorf("create question: expected %v, got %v", app.ErrSessionFinalized, err)
+	}
+
+	if _, err := choices.CreateChoice(ctx, choice.NewChoice(id, 1, "Oui")); !errors.Is(err, app.ErrSessionFinalized) {
+		t.Errorf("create choice: expected %v, got %v", app.ErrSessionFinalized, err)
+	}
+
+	if err := questions.DeleteQuestion(ctx, id); !errors.Is(err, app.ErrSessionFinalized) {
+		t.Errorf("delete question: expected %v, got %v", app.ErrSessionFinalized, err)
+	}
+
+	// une autre session n'est pas concernée
+	if _, err := questions.CreateQuestion(ctx, mustNewQuestion(t, uuid.New(), "Budget ?", 1, 1, false)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -15,10 +15,16 @@ var (
 	ErrVoteSessionNotFound = errors.New("vote session not found")
 	ErrQuestionNotFound    = errors.New("question not found")
 	ErrChoiceNotFound      = errors.New("choice not found ")
+	// ErrSessionFinalized : the questions and choices of a finalized session cannot change
+	ErrSessionFinalized = errors.New("vote session is finalized")
+	// ErrSessionLocked : the questions and choices of a session only change before it opens
+	ErrSessionLocked = errors.New("vote session has started, its questions cannot change")
 )
 
 type SessionChecker interface {
 	Exists(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	// IsEditable returns ErrVoteSessionNotFound when the session doesn't exist
+	IsEditable(ctx context.Context, sessionID uuid.UUID) (bool, error)
 }
 
 type Service struct {
@@ -47,21 +53,41 @@ func NewService(questionRepository question.Repository, choiceRepository choice.
 	}
 }
 
-// questions
+// checkEditable : the session must not be open yet
+func (s *Service) checkEditable(ctx context.Context, sessionID uuid.UUID) error {
+	editable, err := s.sessions.IsEditable(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrVoteSessionNotFound) {
+			return err
+		}
+		return fmt.Errorf("check session: %w", err)
+	}
 
-func (s *Service) CreateQuestion(ctx context.Context, sessionID uuid.UUID, text string, orderNum int, maxChoices int, allowMultiple bool) (int, error) {
-	exists, err := s.sessions.Exists(ctx, sessionID)
+	if !editable {
+		return ErrSessionLocked
+	}
 
+	return nil
+}
+
+// checkQuestionEditable checks the session of the question
+func (s *Service) checkQuestionEditable(ctx context.Context, questionID int) error {
+	q, err := s.questions.GetQuestionByID(ctx, questionID)
 	if err != nil {
-		return 0, fmt.Errorf("check session: %w", err)
+		return err
 	}
 
-	if !exists {
-		return 0, ErrVoteSessionNotFound
-	}
+	return s.checkEditable(ctx, q.SessionID())
+}
 
-	q, err := question.NewQuestion(sessionID, text, orderNum, maxChoices, allowMultiple)
+// questions
+
+func (s *Service) CreateQuestion(ctx context.Context, sessionID uuid.UUID, text string, orderNum int, rules question.Rules) (int, error) {
+	if err := s.checkEditable(ctx, sessionID); err != nil {
+		return 0, err
+	}
 
+	q, err := question.NewQuestionWithRules(sessionID, text, orderNum, rules)
 	if err != nil {
 		return 0, err
 	}
@@ -78,6 +104,10 @@ func (s *Service) ListQuestionsBySessionID(ctx context.Context, sessionID uuid.U
 }
 
 func (s *Service) DeleteQuestion(ctx context.Context, questionID int) error {
+	if err := s.checkQuestionEditable(ctx, questionID); err != nil {
+		return err
+	}
+
 	return s.questions.DeleteQuestion(ctx, questionID)
 }
 
@@ -88,6 +118,10 @@ func (s *Service) UpdateQuestion(ctx context.Context, id int, text string, order
 		return err
 	}
 
+	if err := s.checkEditable(ctx, q.SessionID()); err != nil {
+		return err
+	}
+
 	if err := q.UpdateText(text); err != nil {
 		return err
 	}
@@ -96,10 +130,12 @@ func (s *Service) UpdateQuestion(ctx context.Context, id int, text string, order
 		return err
 	}
 
-	if q.AllowMultiple() != allowMultiple {
-		q.ToggleAllowMultiple()
+	if err := q.UpdateMaxChoices(maxChoices); err != nil {
+		return err
 	}
 
+	q.UpdateAllowMultiple(allowMultiple)
+
 	return s.questions.UpdateQuestion(ctx, q)
 }
 
@@ -111,6 +147,10 @@ func (s *Service) CreateChoice(ctx context.Context, questionID int, orderNum int
 		return 0, errors.New("invalid")
 	}
 
+	if err := s.checkQuestionEditable(ctx, questionID); err != nil {
+		return 0, err
+	}
+
 	c := choice.NewChoice(questionID, orderNum, text)
 
 	return s.choices.CreateChoice(ctx, c)
@@ -121,6 +161,15 @@ func (s *Service) ListChoicesByQuestionID(ctx context.Context, questionID int) (
 }
 
 func (s *Service) DeleteChoice(ctx context.Context, choiceID int) error {
+	c, err := s.choices.GetChoiceByID(ctx, choiceID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkQuestionEditable(ctx, c.QuestionID()); err != nil {
+		return err
+	}
+
 	return s.choices.DeleteChoice(ctx, choiceID)
 }
 
@@ -133,6 +182,10 @@ func (s *Service) UpdateChoice(ctx context.Context, id int, text string, orderNu
 		return err
 	}
 
+	if err := s.checkQuestionEditable(ctx, c.QuestionID()); err != nil {
+		return err
+	}
+
 	if err := c.UpdateText(text); err != nil {
 		return err
 	}
@@ -173,6 +226,14 @@ func (s *Service) ChangeChoiceQuestion(ctx context.Context, choiceID int, newQue
 		return ErrQuestionNotFound
 	}
 
+	if err := s.checkQuestionEditable(ctx, c.QuestionID()); err != nil {
+		return err
+	}
+
+	if err := s.checkQuestionEditable(ctx, newQuestionID); err != nil {
+		return err
+	}
+
 	if err := c.UpdateQuestionID(newQuestionID); err != nil {
 		return err
 	}
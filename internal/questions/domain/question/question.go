@@ -9,13 +9,136 @@ import (
 
 // TODO add updatedAt
 
+// Kind tells how voters answer the question
+type Kind string
+
+const (
+	// KindChoice : pick one choice, or up to maxChoices when allowMultiple
+	KindChoice Kind = "choice"
+	// KindRanked : order up to maxChoices choices by preference, allowMultiple is ignored
+	KindRanked Kind = "ranked"
+	// KindApproval : approve any subset of the choices, up to maxChoices when it is not 0
+	KindApproval Kind = "approval"
+	// KindScore : give each choice from 0 to maxPoints
+	KindScore Kind = "score"
+	// KindCumulative : distribute at most maxPoints between the choices
+	KindCumulative Kind = "cumulative"
+)
+
+func (k Kind) IsValid() bool {
+	switch k {
+	case KindChoice, KindRanked, KindApproval, KindScore, KindCumulative:
+		return true
+	default:
+		return false
+	}
+}
+
+// Scored tells if the ballots of this kind give points to the choices
+func (k Kind) Scored() bool {
+	return k == KindScore || k == KindCumulative
+}
+
+// Method is how the votes of the question are tallied
+type Method string
+
+const (
+	MethodPlurality Method = "plurality"
+	// ranked questions
+	MethodIRV         Method = "irv"
+	MethodSchulze     Method = "schulze"
+	MethodRankedPairs Method = "ranked_pairs"
+	// MethodSTV : single transferable vote, the only method electing several seats
+	MethodSTV Method = "stv"
+	// MethodSum : the choice with the most points wins (score and cumulative questions)
+	MethodSum Method = "sum"
+)
+
+// DefaultMethod is used when no method is chosen
+func (k Kind) DefaultMethod() Method {
+	switch k {
+	case KindRanked:
+		return MethodIRV
+	case KindScore, KindCumulative:
+		return MethodSum
+	default:
+		return MethodPlurality
+	}
+}
+
+// Supports tells if the method can tally the questions of this kind
+func (k Kind) Supports(m Method) bool {
+	switch k {
+	case KindChoice, KindApproval:
+		return m == MethodPlurality
+	case KindScore, KindCumulative:
+		return m == MethodSum
+	case KindRanked:
+		return m == MethodIRV || m == MethodSchulze || m == MethodRankedPairs || m == MethodSTV
+	default:
+		return false
+	}
+}
+
+// Threshold is what the winner of a question needs to pass
+type Threshold string
+
+const (
+	// ThresholdNone : the tally method decides, a tie fails
+	ThresholdNone Threshold = "none"
+	// ThresholdSimple : more than half of the ballots cast on the question
+	ThresholdSimple Threshold = "simple"
+	// ThresholdAbsolute : more than half of the session participants
+	ThresholdAbsolute Threshold = "absolute"
+	// ThresholdSupermajority : at least two thirds of the ballots cast on the question
+	ThresholdSupermajority Threshold = "supermajority"
+)
+
+func (t Threshold) IsValid() bool {
+	switch t {
+	case ThresholdNone, ThresholdSimple, ThresholdAbsolute, ThresholdSupermajority:
+		return true
+	default:
+		return false
+	}
+}
+
+// TieBreak is how a tie for the winning place is broken
+type TieBreak string
+
+const (
+	// TieBreakNone : the tie stays, the question fails
+	TieBreakNone TieBreak = "none"
+	// TieBreakOrganizer : an organizer of the session picks one of the tied choices
+	TieBreakOrganizer TieBreak = "organizer"
+	// TieBreakOrder : the tied choice with the lowest order_num wins
+	TieBreakOrder TieBreak = "order"
+	// TieBreakRandom : drawn with a seed committed before the first ballot and revealed at close
+	TieBreakRandom TieBreak = "random"
+)
+
+func (t TieBreak) IsValid() bool {
+	switch t {
+	case TieBreakNone, TieBreakOrganizer, TieBreakOrder, TieBreakRandom:
+		return true
+	default:
+		return false
+	}
+}
+
 type Question struct {
 	createdAt     time.Time
 	sessionID     uuid.UUID
 	text          string
+	kind          Kind
+	method        Method
 	id            int
 	orderNum      int
 	maxChoices    int
+	maxPoints     int
+	seats         int
+	threshold     Threshold
+	tieBreak      TieBreak
 	allowMultiple bool
 }
 
@@ -23,37 +146,159 @@ var (
 	ErrEmptyText         = errors.New("question text cannot be empty")
 	ErrInvalidOrderNum   = errors.New("order_num must be >= 1")
 	ErrInvalidMaxChoice  = errors.New("max_choices must be >= 1")
+	ErrInvalidMaxPoints  = errors.New("max_points must be >= 1")
 	ErrInvalidQuestionID = errors.New("invalid question id")
+	ErrInvalidKind       = errors.New("invalid question kind")
+	ErrInvalidMethod     = errors.New("tally method not available for this question kind")
+	ErrInvalidSeats      = errors.New("seats must be >= 1")
+	ErrSeatsNeedSTV      = errors.New("several seats need the stv tally method")
+	ErrInvalidThreshold  = errors.New("a majority threshold needs the plurality tally method")
+	ErrInvalidTieBreak   = errors.New("invalid tie-break, stv breaks its own ties")
 )
 
 func (q Question) ID() int              { return q.id }
 func (q Question) SessionID() uuid.UUID { return q.sessionID }
 func (q Question) Text() string         { return q.text }
+func (q Question) Kind() Kind           { return q.kind }
+func (q Question) Method() Method       { return q.method }
 func (q Question) OrderNum() int        { return q.orderNum }
 func (q Question) AllowMultiple() bool  { return q.allowMultiple }
 func (q Question) MaxChoices() int      { return q.maxChoices }
+func (q Question) MaxPoints() int       { return q.maxPoints }
+func (q Question) Seats() int           { return q.seats }
+func (q Question) Threshold() Threshold { return q.threshold }
+func (q Question) TieBreak() TieBreak   { return q.tieBreak }
 func (q Question) CreatedAt() time.Time { return q.createdAt }
 
+// Rules are how a question is answered and tallied, fields the kind does not use are ignored
+type Rules struct {
+	Kind          Kind
+	Method        Method    // default method of the kind when empty
+	MaxChoices    int       // choice: selections, ranked: ranks, approval: approvals (0 for any)
+	AllowMultiple bool      // choice only
+	MaxPoints     int       // score: points per choice, cumulative: points to distribute
+	Seats         int       // 1 when 0, more only with stv
+	Threshold     Threshold // none when empty, the others need the plurality method
+	TieBreak      TieBreak  // none when empty, not with stv
+}
+
 func NewQuestion(sessionID uuid.UUID, text string, orderNum, maxChoices int, allowMultiple bool) (Question, error) {
+	return NewQuestionWithRules(sessionID, text, orderNum, Rules{
+		Kind:          KindChoice,
+		MaxChoices:    maxChoices,
+		AllowMultiple: allowMultiple,
+	})
+}
+
+// NewRankedQuestion : voters rank up to maxRanks choices, tallied with method (irv, schulze or ranked_pairs)
+func NewRankedQuestion(sessionID uuid.UUID, text string, orderNum, maxRanks int, method Method) (Question, error) {
+	if !KindRanked.Supports(method) {
+		return Question{}, ErrInvalidMethod
+	}
+
+	return NewQuestionWithRules(sessionID, text, orderNum, Rules{
+		Kind:       KindRanked,
+		Method:     method,
+		MaxChoices: maxRanks,
+	})
+}
+
+// NewSTVQuestion : voters rank up to maxRanks choices to elect seats of them
+func NewSTVQuestion(sessionID uuid.UUID, text string, orderNum, maxRanks, seats int) (Question, error) {
+	if seats < 1 {
+		return Question{}, ErrInvalidSeats
+	}
+
+	return NewQuestionWithRules(sessionID, text, orderNum, Rules{
+		Kind:       KindRanked,
+		Method:     MethodSTV,
+		MaxChoices: maxRanks,
+		Seats:      seats,
+	})
+}
+
+func NewQuestionWithRules(sessionID uuid.UUID, text string, orderNum int, rules Rules) (Question, error) {
 	if text == "" {
 		return Question{}, ErrEmptyText
 	}
 	if orderNum < 1 {
 		return Question{}, ErrInvalidOrderNum
 	}
-	if maxChoices < 1 {
-		return Question{}, ErrInvalidMaxChoice
+	if !rules.Kind.IsValid() {
+		return Question{}, ErrInvalidKind
+	}
+
+	if err := checkMaxChoices(rules.Kind, rules.MaxChoices); err != nil {
+		return Question{}, err
+	}
+	if rules.Kind.Scored() && rules.MaxPoints < 1 {
+		return Question{}, ErrInvalidMaxPoints
+	}
+
+	method := rules.Method
+	if method == "" {
+		method = rules.Kind.DefaultMethod()
+	}
+	if !rules.Kind.Supports(method) {
+		return Question{}, ErrInvalidMethod
+	}
+
+	seats := rules.Seats
+	if seats == 0 {
+		seats = 1
+	}
+	if err := checkSeats(seats, method); err != nil {
+		return Question{}, err
 	}
 
-	return Question{
+	threshold := rules.Threshold
+	if threshold == "" {
+		threshold = ThresholdNone
+	}
+	if err := checkThreshold(threshold, method); err != nil {
+		return Question{}, err
+	}
+
+	tieBreak := rules.TieBreak
+	if tieBreak == "" {
+		tieBreak = TieBreakNone
+	}
+	if err := checkTieBreak(tieBreak, method); err != nil {
+		return Question{}, err
+	}
+
+	q := Question{
 		// id is set by the database
-		sessionID:     sessionID,
-		text:          text,
-		orderNum:      orderNum,
-		maxChoices:    maxChoices,
-		allowMultiple: allowMultiple,
+		sessionID: sessionID,
+		text:      text,
+		kind:      rules.Kind,
+		method:    method,
+		orderNum:  orderNum,
+		seats:     seats,
+		threshold: threshold,
+		tieBreak:  tieBreak,
 		// create_at is set by the database
-	}, nil
+	}
+
+	switch rules.Kind {
+	case KindChoice:
+		q.maxChoices = rules.MaxChoices
+		q.allowMultiple = rules.AllowMultiple
+	case KindRanked, KindApproval:
+		q.maxChoices = rules.MaxChoices
+	case KindScore, KindCumulative:
+		q.maxPoints = rules.MaxPoints
+	}
+
+	return q, nil
+}
+
+// CopyTo is the same question in another session, its id and creation date are set by the database
+func (q Question) CopyTo(sessionID uuid.UUID) Question {
+	q.id = 0
+	q.sessionID = sessionID
+	q.createdAt = time.Time{}
+	return q
 }
 
 func MustNewQuestion(sessionID uuid.UUID, text string, orderNum, maxChoices int, allowMultiple bool) Question {
@@ -82,19 +327,113 @@ func (q *Question) ChangeOrderNum(newOrderNum int) error {
 	return nil
 }
 
+// UpdateMaxChoices : ignored by the score and cumulative questions, which have no max choices
 func (q *Question) UpdateMaxChoices(newMax int) error {
-	if newMax < 1 {
-		return ErrInvalidMaxChoice
+	if err := checkMaxChoices(q.kind, newMax); err != nil {
+		return err
+	}
+	if q.kind.Scored() {
+		return nil
 	}
 	q.maxChoices = newMax
 	return nil
 }
 
+// checkMaxChoices : at least 1 for choice and ranked questions, 0 is any for approval questions
+func checkMaxChoices(k Kind, max int) error {
+	switch k {
+	case KindChoice, KindRanked:
+		if max < 1 {
+			return ErrInvalidMaxChoice
+		}
+	case KindApproval:
+		if max < 0 {
+			return ErrInvalidMaxChoice
+		}
+	}
+	return nil
+}
+
+func (q *Question) ChangeMethod(m Method) error {
+	if !q.kind.Supports(m) {
+		return ErrInvalidMethod
+	}
+	if q.seats > 1 && m != MethodSTV {
+		return ErrSeatsNeedSTV
+	}
+	if err := checkThreshold(q.threshold, m); err != nil {
+		return err
+	}
+	if err := checkTieBreak(q.tieBreak, m); err != nil {
+		return err
+	}
+	q.method = m
+	return nil
+}
+
+func (q *Question) ChangeThreshold(t Threshold) error {
+	if err := checkThreshold(t, q.method); err != nil {
+		return err
+	}
+	q.threshold = t
+	return nil
+}
+
+func checkThreshold(t Threshold, m Method) error {
+	if !t.IsValid() {
+		return ErrInvalidThreshold
+	}
+	if t != ThresholdNone && m != MethodPlurality {
+		return ErrInvalidThreshold
+	}
+	return nil
+}
+
+func (q *Question) ChangeTieBreak(t TieBreak) error {
+	if err := checkTieBreak(t, q.method); err != nil {
+		return err
+	}
+	q.tieBreak = t
+	return nil
+}
+
+func checkTieBreak(t TieBreak, m Method) error {
+	if !t.IsValid() {
+		return ErrInvalidTieBreak
+	}
+	if t != TieBreakNone && m == MethodSTV {
+		return ErrInvalidTieBreak
+	}
+	return nil
+}
+
+func (q *Question) ChangeSeats(seats int) error {
+	if err := checkSeats(seats, q.method); err != nil {
+		return err
+	}
+	q.seats = seats
+	return nil
+}
+
+func checkSeats(seats int, m Method) error {
+	if seats < 1 {
+		return ErrInvalidSeats
+	}
+	if seats > 1 && m != MethodSTV {
+		return ErrSeatsNeedSTV
+	}
+	return nil
+}
+
+// ToggleAllowMultiple : only choice questions allow multiple selections, the other kinds ignore it
 func (q *Question) ToggleAllowMultiple() {
-	q.allowMultiple = !q.allowMultiple
+	q.UpdateAllowMultiple(!q.allowMultiple)
 }
 
 func (q *Question) UpdateAllowMultiple(allow bool) {
+	if q.kind != KindChoice {
+		return
+	}
 	q.allowMultiple = allow
 }
 
@@ -102,9 +441,15 @@ func Rehydrate(
 	id int,
 	sessionID uuid.UUID,
 	text string,
+	kind Kind,
+	method Method,
 	orderNum int,
 	allowMultiple bool,
 	maxChoices int,
+	maxPoints int,
+	seats int,
+	threshold Threshold,
+	tieBreak TieBreak,
 	createdAt time.Time,
 ) (*Question, error) {
 	if id <= 0 {
@@ -113,14 +458,35 @@ func Rehydrate(
 	if text == "" {
 		return nil, ErrEmptyText
 	}
+	if !kind.IsValid() {
+		return nil, ErrInvalidKind
+	}
+	if !kind.Supports(method) {
+		return nil, ErrInvalidMethod
+	}
+	if err := checkSeats(seats, method); err != nil {
+		return nil, err
+	}
+	if err := checkThreshold(threshold, method); err != nil {
+		return nil, err
+	}
+	if err := checkTieBreak(tieBreak, method); err != nil {
+		return nil, err
+	}
 
 	return &Question{
 		id:            id,
 		sessionID:     sessionID,
 		text:          text,
+		kind:          kind,
+		method:        method,
 		orderNum:      orderNum,
 		allowMultiple: allowMultiple,
 		maxChoices:    maxChoices,
+		maxPoints:     maxPoints,
+		seats:         seats,
+		threshold:     threshold,
+		tieBreak:      tieBreak,
 		createdAt:     createdAt,
 	}, nil
 }
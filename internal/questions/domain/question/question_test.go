@@ -43,3 +43,220 @@ func TestNewQuestion_Validations(t *testing.T) {
 		})
 	}
 }
+
+func TestNewRankedQuestion(t *testing.T) {
+	q, err := question.NewRankedQuestion(uuid.New(), "Qui pour présider ?", 1, 3, question.MethodIRV)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if q.Kind() != question.KindRanked {
+		t.Errorf("kind: got %q, want %q", q.Kind(), question.KindRanked)
+	}
+
+	if q.MaxChoices() != 3 {
+		t.Errorf("max ranks: got %d, want 3", q.MaxChoices())
+	}
+
+	if _, err := question.NewRankedQuestion(uuid.New(), "ok", 1, 0, question.MethodIRV); !errors.Is(err, question.ErrInvalidMaxChoice) {
+		t.Errorf("expected %v, got %v", question.ErrInvalidMaxChoice, err)
+	}
+
+	if _, err := question.NewRankedQuestion(uuid.New(), "ok", 1, 3, question.MethodPlurality); !errors.Is(err, question.ErrInvalidMethod) {
+		t.Errorf("expected %v, got %v", question.ErrInvalidMethod, err)
+	}
+}
+
+func TestQuestion_ChangeMethod(t *testing.T) {
+	q := question.MustNewQuestion(uuid.New(), "ok", 1, 1, false)
+
+	if q.Method() != question.MethodPlurality {
+		t.Errorf("default method: got %q, want %q", q.Method(), question.MethodPlurality)
+	}
+
+	// une question à choix ne se dépouille pas avec une méthode de classement
+	if err := q.ChangeMethod(question.MethodSchulze); !errors.Is(err, question.ErrInvalidMethod) {
+		t.Errorf("expected %v, got %v", question.ErrInvalidMethod, err)
+	}
+
+	ranked, err := question.NewRankedQuestion(uuid.New(), "ok", 1, 3, question.MethodIRV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ranked.ChangeMethod(question.MethodRankedPairs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ranked.Method() != question.MethodRankedPairs {
+		t.Errorf("method: got %q, want %q", ranked.Method(), question.MethodRankedPairs)
+	}
+}
+
+func TestNewSTVQuestion(t *testing.T) {
+	q, err := question.NewSTVQuestion(uuid.New(), "Élisez le bureau", 1, 5, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if q.Method() != question.MethodSTV || q.Seats() != 3 {
+		t.Errorf("got %q with %d seats, want stv with 3 seats", q.Method(), q.Seats())
+	}
+
+	if _, err := question.NewSTVQuestion(uuid.New(), "ok", 1, 5, 0); !errors.Is(err, question.ErrInvalidSeats) {
+		t.Errorf("expected %v, got %v", question.ErrInvalidSeats, err)
+	}
+
+	// plusieurs sièges ne se dépouillent qu'en stv
+	if err := q.ChangeMethod(question.MethodIRV); !errors.Is(err, question.ErrSeatsNeedSTV) {
+		t.Errorf("expected %v, got %v", question.ErrSeatsNeedSTV, err)
+	}
+
+	single := question.MustNewQuestion(uuid.New(), "ok", 1, 1, false)
+	if err := single.ChangeSeats(2); !errors.Is(err, question.ErrSeatsNeedSTV) {
+		t.Errorf("expected %v, got %v", question.ErrSeatsNeedSTV, err)
+	}
+}
+
+func TestNewQuestionWithRules(t *testing.T) {
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name       string
+		rules      question.Rules
+		wantErr    error
+		wantMethod question.Method
+	}{
+		{"kind inconnu", question.Rules{Kind: "vote"}, question.ErrInvalidKind, ""},
+		{"approbation sans limite", question.Rules{Kind: question.KindApproval}, nil, question.MethodPlurality},
+		{"approbation limite negative", question.Rules{Kind: question.KindApproval, MaxChoices: -1}, question.ErrInvalidMaxChoice, ""},
+		{"score", question.Rules{Kind: question.KindScore, MaxPoints: 10}, nil, question.MethodSum},
+		{"score sans max_points", question.Rules{Kind: question.KindScore}, question.ErrInvalidMaxPoints, ""},
+		{"cumulatif", question.Rules{Kind: question.KindCumulative, MaxPoints: 5}, nil, question.MethodSum},
+		{"cumulatif en irv", question.Rules{Kind: question.KindCumulative, MaxPoints: 5, Method: question.MethodIRV}, question.ErrInvalidMethod, ""},
+		{"stv sans sieges precises", question.Rules{Kind: question.KindRanked, Method: question.MethodSTV, MaxChoices: 3}, nil, question.MethodSTV},
+		{"majorite qualifiee", question.Rules{Kind: question.KindChoice, MaxChoices: 1, Threshold: question.ThresholdSupermajority}, nil, question.MethodPlurality},
+		{"seuil inconnu", question.Rules{Kind: question.KindChoice, MaxChoices: 1, Threshold: "unanimity"}, question.ErrInvalidThreshold, ""},
+		{"seuil en schulze", question.Rules{Kind: question.KindRanked, Method: question.MethodSchulze, MaxChoices: 3, Threshold: question.ThresholdSimple}, question.ErrInvalidThreshold, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := question.NewQuestionWithRules(sessionID, "ok", 1, tt.rules)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if err == nil && q.Method() != tt.wantMethod {
+				t.Errorf("method: got %q, want %q", q.Method(), tt.wantMethod)
+			}
+		})
+	}
+
+	// les règles qui ne concernent pas le kind sont ignorées
+	q, err := question.NewQuestionWithRules(sessionID, "ok", 1, question.Rules{Kind: question.KindScore, MaxPoints: 10, MaxChoices: 3, AllowMultiple: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.MaxPoints() != 10 || q.MaxChoices() != 0 || q.AllowMultiple() {
+		t.Errorf("got max_points=%d max_choices=%d allow_multiple=%v", q.MaxPoints(), q.MaxChoices(), q.AllowMultiple())
+	}
+}
+
+func TestQuestion_ChangeThreshold(t *testing.T) {
+	// GIVEN une question à choix sans seuil
+	q := question.MustNewQuestion(uuid.New(), "Adopter le budget ?", 1, 1, false)
+	if q.Threshold() != question.ThresholdNone {
+		t.Fatalf("threshold: got %q, want %q", q.Threshold(), question.ThresholdNone)
+	}
+
+	// WHEN on exige la majorité absolue
+	if err := q.ChangeThreshold(question.ThresholdAbsolute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN une méthode autre que plurality est refusée tant que le seuil reste
+	ranked, err := question.NewRankedQuestion(uuid.New(), "ok", 1, 3, question.MethodIRV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ranked.ChangeThreshold(question.ThresholdSimple); !errors.Is(err, question.ErrInvalidThreshold) {
+		t.Errorf("expected %v, got %v", question.ErrInvalidThreshold, err)
+	}
+}
+
+func TestQuestion_ChangeTieBreak(t *testing.T) {
+	// GIVEN une question à choix sans départage
+	q := question.MustNewQuestion(uuid.New(), "Choisir le lieu", 1, 1, false)
+	if q.TieBreak() != question.TieBreakNone {
+		t.Fatalf("tie-break: got %q, want %q", q.TieBreak(), question.TieBreakNone)
+	}
+
+	// WHEN on départage par tirage au sort
+	if err := q.ChangeTieBreak(question.TieBreakRandom); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN une règle inconnue est refusée
+	if err := q.ChangeTieBreak("coin"); !errors.Is(err, question.ErrInvalidTieBreak) {
+		t.Errorf("expected %v, got %v", question.ErrInvalidTieBreak, err)
+	}
+
+	// AND stv, qui départage seule, refuse toute règle
+	ranked, err := question.NewRankedQuestion(uuid.New(), "ok", 1, 3, question.MethodIRV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ranked.ChangeTieBreak(question.TieBreakOrder); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ranked.ChangeMethod(question.MethodSTV); !errors.Is(err, question.ErrInvalidTieBreak) {
+		t.Errorf("expected %v, got %v", question.ErrInvalidTieBreak, err)
+	}
+}
+
+func TestQuestion_UpdateRulesOfTheKind(t *testing.T) {
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name              string
+		rules             question.Rules
+		maxChoices        int
+		wantErr           error
+		wantMaxChoices    int
+		wantAllowMultiple bool
+	}{
+		{"choix : au moins 1", question.Rules{Kind: question.KindChoice, MaxChoices: 1}, 0, question.ErrInvalidMaxChoice, 1, true},
+		{"choix : multiple", question.Rules{Kind: question.KindChoice, MaxChoices: 1}, 3, nil, 3, true},
+		{"approbation : 0 pour tous", question.Rules{Kind: question.KindApproval, MaxChoices: 2}, 0, nil, 0, false},
+		{"approbation : négatif", question.Rules{Kind: question.KindApproval}, -1, question.ErrInvalidMaxChoice, 0, false},
+		{"classement : au moins 1", question.Rules{Kind: question.KindRanked, MaxChoices: 2}, 0, question.ErrInvalidMaxChoice, 2, false},
+		{"score : ignoré", question.Rules{Kind: question.KindScore, MaxPoints: 5}, 3, nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN une question du type testé
+			q, err := question.NewQuestionWithRules(sessionID, "Quelle couleur ?", 1, tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// WHEN on change son maximum et on permet plusieurs choix
+			err = q.UpdateMaxChoices(tt.maxChoices)
+			q.UpdateAllowMultiple(true)
+
+			// THEN seules les règles de son type changent
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateMaxChoices() err = %v, want %v", err, tt.wantErr)
+			}
+			if q.MaxChoices() != tt.wantMaxChoices {
+				t.Errorf("max choices = %d, want %d", q.MaxChoices(), tt.wantMaxChoices)
+			}
+			if q.AllowMultiple() != tt.wantAllowMultiple {
+				t.Errorf("allow multiple = %v, want %v", q.AllowMultiple(), tt.wantAllowMultiple)
+			}
+		})
+	}
+}
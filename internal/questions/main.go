@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/73NN0/voting-app/internal/common/clock"
 	"github.com/73NN0/voting-app/internal/common/db"
 	"github.com/73NN0/voting-app/internal/common/server"
 	"github.com/73NN0/voting-app/internal/questions/adapters"
@@ -36,7 +37,7 @@ func main() {
 
 	sessionsRepo := sessions.NewSqliteSessionRepository(database)
 
-	sessionsChecker := adapters.NewSessionCheckerInProcess(sessionsRepo)
+	sessionsChecker := adapters.NewSessionCheckerInProcess(sessionsRepo, clock.System{})
 
 	service := app.NewService(questionsRepo, choicesRepo, sessionsChecker)
 
@@ -11,6 +11,7 @@ import (
 	"github.com/73NN0/voting-app/internal/common/server/httperr"
 	"github.com/73NN0/voting-app/internal/common/server/httpstat"
 	"github.com/73NN0/voting-app/internal/questions/app"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
 	"github.com/google/uuid"
 )
 
@@ -22,10 +23,36 @@ type HttpHandler struct {
 
 type questionRequest struct {
 	SessionID     uuid.UUID `json:"session_id"`
+	Kind          string    `json:"kind"`         // choice (default) | ranked | approval | score | cumulative
+	TallyMethod   string    `json:"tally_method"` // default of the kind when empty
 	Text          string    `json:"text"`
 	OrderNum      int       `json:"order_num"`
 	MaxChoices    int       `json:"max_choices"`
 	AllowMultiple bool      `json:"allow_multiple"`
+	MaxPoints     int       `json:"max_points"` // score and cumulative
+	Seats         int       `json:"seats"`      // 1 when empty, more only with the stv method
+	Threshold     string    `json:"threshold"`  // none (default) | simple | absolute | supermajority
+	TieBreak      string    `json:"tie_break"`  // none (default) | organizer | order | random
+}
+
+func (req questionRequest) kind() question.Kind {
+	if req.Kind == "" {
+		return question.KindChoice
+	}
+	return question.Kind(req.Kind)
+}
+
+func (req questionRequest) toRules() question.Rules {
+	return question.Rules{
+		Kind:          req.kind(),
+		Method:        question.Method(req.TallyMethod),
+		MaxChoices:    req.MaxChoices,
+		AllowMultiple: req.AllowMultiple,
+		MaxPoints:     req.MaxPoints,
+		Seats:         req.Seats,
+		Threshold:     question.Threshold(req.Threshold),
+		TieBreak:      question.TieBreak(req.TieBreak),
+	}
 }
 
 func Validate(req questionRequest) error {
@@ -45,6 +72,48 @@ func Validate(req questionRequest) error {
 		return errors.New("max_choices must be positive when allow_multiple is true")
 	}
 
+	kind := req.kind()
+
+	if !kind.IsValid() {
+		return errors.New("kind must be choice, ranked, approval, score or cumulative")
+	}
+
+	if kind == question.KindRanked && req.MaxChoices < 1 {
+		return errors.New("max_choices must be positive for a ranked question")
+	}
+
+	if kind == question.KindApproval && req.MaxChoices < 0 {
+		return errors.New("max_choices cannot be negative")
+	}
+
+	if kind.Scored() && req.MaxPoints < 1 {
+		return errors.New("max_points must be positive for a score or cumulative question")
+	}
+
+	if req.TallyMethod != "" && !kind.Supports(question.Method(req.TallyMethod)) {
+		return errors.New("tally_method is not available for this kind of question")
+	}
+
+	if req.Seats < 0 {
+		return errors.New("seats must be positive")
+	}
+
+	if req.Seats > 1 && req.TallyMethod != string(question.MethodSTV) {
+		return errors.New("several seats need the stv tally_method")
+	}
+
+	if req.Threshold != "" && !question.Threshold(req.Threshold).IsValid() {
+		return errors.New("threshold must be none, simple, absolute or supermajority")
+	}
+
+	if req.TieBreak != "" && !question.TieBreak(req.TieBreak).IsValid() {
+		return errors.New("tie_break must be none, organizer, order or random")
+	}
+
+	if req.TieBreak != "" && req.TieBreak != string(question.TieBreakNone) && req.TallyMethod == string(question.MethodSTV) {
+		return errors.New("stv breaks its own ties, tie_break must be none")
+	}
+
 	return nil
 }
 
@@ -71,9 +140,12 @@ func (h *HttpHandler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := h.service.CreateQuestion(ctx, req.SessionID, req.Text, req.OrderNum, req.MaxChoices, req.AllowMultiple)
+	id, err := h.service.CreateQuestion(ctx, req.SessionID, req.Text, req.OrderNum, req.toRules())
 	if err != nil {
 		logger.Logger.Error("create question failed", "err", err)
+		if finalized(w, err) {
+			return
+		}
 		httperr.InternalServerError(w, err.Error())
 		return
 	}
@@ -142,6 +214,21 @@ func (h *HttpHandler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the rules of the kind are checked by the question
+	if err := h.service.UpdateQuestion(ctx, id, req.Text, req.OrderNum, req.MaxChoices, req.AllowMultiple); err != nil {
+		logger.Logger.Error("update question failed", "err", err)
+		switch {
+		case finalized(w, err):
+		case errors.Is(err, question.ErrEmptyText),
+			errors.Is(err, question.ErrInvalidOrderNum),
+			errors.Is(err, question.ErrInvalidMaxChoice):
+			httperr.UnprocessableEntity(w, err.Error())
+		default:
+			httperr.InternalServerError(w, err.Error())
+		}
+		return
+	}
+
 	q, err := h.service.GetQuestionByID(ctx, id)
 	if err != nil {
 		logger.Logger.Error("get question failed", "err", err)
@@ -149,17 +236,6 @@ func (h *HttpHandler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	q.UpdateText(req.Text)
-	q.ChangeOrderNum(req.OrderNum)
-	q.UpdateMaxChoices(req.MaxChoices)
-	q.UpdateAllowMultiple(req.AllowMultiple)
-
-	if err := h.service.UpdateQuestion(ctx, q.ID(), q.Text(), q.OrderNum(), q.MaxChoices(), q.AllowMultiple()); err != nil {
-		logger.Logger.Error("update question failed", "err", err)
-		httperr.InternalServerError(w, err.Error())
-		return
-	}
-
 	httpstat.OkJSON(w, q)
 }
 
@@ -176,6 +252,9 @@ func (h *HttpHandler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.DeleteQuestion(ctx, id); err != nil {
 		logger.Logger.Error("delete question failed", "err", err)
+		if finalized(w, err) {
+			return
+		}
 		httperr.NotFound(w, "question not found")
 		return
 	}
@@ -208,6 +287,9 @@ func (h *HttpHandler) CreateChoice(w http.ResponseWriter, r *http.Request) {
 	id, err := h.service.CreateChoice(ctx, questionID, req.OrderNum, req.Text)
 	if err != nil {
 		logger.Logger.Error("create choice failed", "err", err)
+		if finalized(w, err) {
+			return
+		}
 		httperr.BadRequest(w, err.Error())
 		return
 	}
@@ -215,6 +297,15 @@ func (h *HttpHandler) CreateChoice(w http.ResponseWriter, r *http.Request) {
 	httpstat.CreatedJSON(w, map[string]int{"id": id})
 }
 
+// finalized answers 409 when err comes from a write to a finalized, or started, session
+func finalized(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, app.ErrSessionFinalized) && !errors.Is(err, app.ErrSessionLocked) {
+		return false
+	}
+	httperr.Conflict(w, err.Error())
+	return true
+}
+
 // funny
 func (h *HttpHandler) teapot(w http.ResponseWriter, r *http.Request) {
 	httperr.Teapot(w, "teapot, would you like some tea sir ?")
@@ -278,6 +369,9 @@ func (h *HttpHandler) UpdateChoice(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.UpdateChoice(ctx, id, req.Text, req.OrderNum)
 	if err != nil {
+		if finalized(w, err) {
+			return
+		}
 		httperr.NotFound(w, "choice not found")
 		return
 	}
@@ -297,6 +391,9 @@ func (h *HttpHandler) DeleteChoice(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.DeleteChoice(ctx, id); err != nil {
+		if finalized(w, err) {
+			return
+		}
 		httperr.NotFound(w, "choice not found")
 		return
 	}
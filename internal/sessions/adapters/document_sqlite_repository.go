@@ -0,0 +1,102 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
+	"github.com/73NN0/voting-app/internal/sessions/domain/document"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/google/uuid"
+)
+
+// QuestionsTx and ChoicesTx give the questions context a way to write the questions and choices
+// of a document in the transaction importing it
+type QuestionsTx interface {
+	CreateQuestionTx(ctx context.Context, tx *sql.Tx, q question.Question) (int, error)
+}
+
+type ChoicesTx interface {
+	CreateChoiceTx(ctx context.Context, tx *sql.Tx, c choice.Choice) (int, error)
+}
+
+// SqliteDocumentsRepository writes a whole document, or clone, at once: the session, its members,
+// the questions and the choices share one transaction, which the repositories of each table cannot give
+type SqliteDocumentsRepository struct {
+	db        *sql.DB
+	questions QuestionsTx
+	choices   ChoicesTx
+}
+
+// Compile-time check
+var _ document.Repository = (*SqliteDocumentsRepository)(nil)
+
+func NewSqliteDocumentsRepository(db *sql.DB, questions QuestionsTx, choices ChoicesTx) *SqliteDocumentsRepository {
+	if db == nil {
+		panic("no db in SQL documents repository !")
+	}
+
+	if questions == nil {
+		panic("missing questions in SQL documents repository !")
+	}
+
+	if choices == nil {
+		panic("missing choices in SQL documents repository !")
+	}
+
+	return &SqliteDocumentsRepository{db: db, questions: questions, choices: choices}
+}
+
+func (r *SqliteDocumentsRepository) ImportSession(ctx context.Context, s *session.Session, organizerID uuid.UUID, entries []document.Entry) error {
+	organizer := session.Member{UserID: organizerID, Role: session.RoleOrganizer, Weight: session.DefaultWeight}
+
+	return r.write(ctx, s, []session.Member{organizer}, entries)
+}
+
+func (r *SqliteDocumentsRepository) CloneSession(ctx context.Context, s *session.Session, members []session.Member, entries []document.Entry) error {
+	return r.write(ctx, s, members, entries)
+}
+
+// write stores the session, its entries then its members, a failure at any step leaves nothing
+func (r *SqliteDocumentsRepository) write(ctx context.Context, s *session.Session, members []session.Member, entries []document.Entry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertSession(ctx, tx, s); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := r.insertEntry(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range members {
+		if err := insertMember(ctx, tx, s.ID(), m.UserID, m.Role, m.Weight); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SqliteDocumentsRepository) insertEntry(ctx context.Context, tx *sql.Tx, e document.Entry) error {
+	questionID, err := r.questions.CreateQuestionTx(ctx, tx, e.Question)
+	if err != nil {
+		return fmt.Errorf("failed to insert question %d: %w", e.Question.OrderNum(), err)
+	}
+
+	for _, c := range e.Choices {
+		if _, err := r.choices.CreateChoiceTx(ctx, tx, choice.NewChoice(questionID, c.OrderNum(), c.Text())); err != nil {
+			return fmt.Errorf("failed to insert choice %d of question %d: %w", c.OrderNum(), e.Question.OrderNum(), err)
+		}
+	}
+
+	return nil
+}
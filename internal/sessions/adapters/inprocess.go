@@ -0,0 +1,105 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
+	"github.com/73NN0/voting-app/internal/sessions/app"
+	"github.com/73NN0/voting-app/internal/sessions/domain/document"
+	"github.com/73NN0/voting-app/internal/users/domain/user"
+	votes "github.com/73NN0/voting-app/internal/votes/app"
+	"github.com/73NN0/voting-app/internal/votes/domain/snapshot"
+	"github.com/google/uuid"
+)
+
+type FinalizerInProcess struct {
+	results *votes.ResultsService
+}
+
+var _ app.Finalizer = (*FinalizerInProcess)(nil)
+
+func NewFinalizerInProcess(results *votes.ResultsService) *FinalizerInProcess {
+	if results == nil {
+		panic(" missing results service")
+	}
+
+	return &FinalizerInProcess{results: results}
+}
+
+// Finalize : a session already finalized is left as is
+func (f *FinalizerInProcess) Finalize(ctx context.Context, sessionID uuid.UUID) error {
+	_, err := f.results.Finalize(ctx, sessionID)
+	switch {
+	case err == nil, errors.Is(err, snapshot.ErrAlreadyFinalized):
+		return nil
+	case errors.Is(err, votes.ErrTiePending):
+		return app.ErrDecisionPending
+	}
+
+	return err
+}
+
+type UsersInProcess struct {
+	users user.Repository
+}
+
+var _ app.Users = (*UsersInProcess)(nil)
+
+func NewUsersInProcess(users user.Repository) *UsersInProcess {
+	if users == nil {
+		panic(" missing user repository")
+	}
+
+	return &UsersInProcess{users: users}
+}
+
+func (u *UsersInProcess) UserIDByEmail(ctx context.Context, email string) (uuid.UUID, error) {
+	found, err := u.users.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, err
+	}
+
+	return found.ID(), nil
+}
+
+type QuestionsInProcess struct {
+	questions question.Repository
+	choices   choice.Repository
+}
+
+var _ app.Questions = (*QuestionsInProcess)(nil)
+
+func NewQuestionsInProcess(questions question.Repository, choices choice.Repository) *QuestionsInProcess {
+	if questions == nil {
+		panic(" missing question repository")
+	}
+
+	if choices == nil {
+		panic(" missing choice repository")
+	}
+
+	return &QuestionsInProcess{questions: questions, choices: choices}
+}
+
+func (q *QuestionsInProcess) SessionEntries(ctx context.Context, sessionID uuid.UUID) ([]document.Entry, error) {
+	questions, err := q.questions.GetQuestionsBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]document.Entry, 0, len(questions))
+	for _, qu := range questions {
+		choices, err := q.choices.GetChoicesByQuestionID(ctx, qu.ID())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, document.Entry{Question: qu, Choices: choices})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,254 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/sessions/domain/invitation"
+	"github.com/73NN0/voting-app/internal/users/domain/user"
+	"github.com/google/uuid"
+)
+
+// ========== DTOs ==========
+
+// invitationDTO représente session_invitation en DB
+type invitationDTO struct {
+	ID         string         // TEXT (uuid)
+	SessionID  string         // TEXT (uuid)
+	Email      string         // TEXT
+	Weight     int            // INTEGER
+	TokenHash  string         // TEXT, hex sha256 of the token
+	Status     string         // TEXT (pending | accepted | declined)
+	UserID     sql.NullString // TEXT nullable (uuid)
+	CreatedAt  db.Timestamp   // TEXT
+	ExpiresAt  db.Timestamp   // TEXT
+	AnsweredAt *db.Timestamp  // TEXT nullable
+}
+
+func toInvitationDTO(i *invitation.Invitation) invitationDTO {
+	dto := invitationDTO{
+		ID:        i.ID().String(),
+		SessionID: i.SessionID().String(),
+		Email:     i.Email(),
+		Weight:    i.Weight(),
+		TokenHash: i.TokenHash(),
+		Status:    string(i.Status()),
+		CreatedAt: db.Timestamp{Time: i.CreatedAt()},
+		ExpiresAt: db.Timestamp{Time: i.ExpiresAt()},
+	}
+
+	if i.UserID() != uuid.Nil {
+		dto.UserID = sql.NullString{String: i.UserID().String(), Valid: true}
+	}
+
+	if answeredAt, ok := i.AnsweredAt(); ok {
+		dto.AnsweredAt = &db.Timestamp{Time: answeredAt}
+	}
+
+	return dto
+}
+
+func (dto invitationDTO) toInvitation() (*invitation.Invitation, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation id: %w", err)
+	}
+
+	sessionID, err := uuid.Parse(dto.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session id: %w", err)
+	}
+
+	userID := uuid.Nil
+	if dto.UserID.Valid {
+		if userID, err = uuid.Parse(dto.UserID.String); err != nil {
+			return nil, fmt.Errorf("invalid user id: %w", err)
+		}
+	}
+
+	var answeredAt *time.Time
+	if dto.AnsweredAt != nil {
+		answeredAt = &dto.AnsweredAt.Time
+	}
+
+	return invitation.Rehydrate(
+		id,
+		sessionID,
+		dto.Email,
+		dto.Weight,
+		dto.TokenHash,
+		invitation.Status(dto.Status),
+		userID,
+		dto.CreatedAt.Time,
+		dto.ExpiresAt.Time,
+		answeredAt,
+	)
+}
+
+// ========== Repository Implementation ==========
+
+// UsersTx creates, in the transaction accepting an invitation, the user it goes to
+type UsersTx interface {
+	CreateUserTx(ctx context.Context, tx *sql.Tx, u *user.User) error
+}
+
+type SqliteInvitationsRepository struct {
+	db    *sql.DB
+	users UsersTx
+}
+
+// Compile-time check
+var _ invitation.Repository = (*SqliteInvitationsRepository)(nil)
+
+func NewSqliteInvitationsRepository(db *sql.DB, users UsersTx) *SqliteInvitationsRepository {
+	if db == nil {
+		panic("no db in SQL invitations repository !")
+	}
+
+	if users == nil {
+		panic("missing users in SQL invitations repository !")
+	}
+
+	return &SqliteInvitationsRepository{db: db, users: users}
+}
+
+const invitationColumns = `id, session_id, email, weight, token_hash, status, user_id, created_at, expires_at, answered_at`
+
+func scanInvitation(row interface{ Scan(...any) error }) (*invitation.Invitation, error) {
+	var dto invitationDTO
+
+	err := row.Scan(
+		&dto.ID, &dto.SessionID, &dto.Email, &dto.Weight, &dto.TokenHash, &dto.Status,
+		&dto.UserID, &dto.CreatedAt, &dto.ExpiresAt, &dto.AnsweredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.toInvitation()
+}
+
+func (r *SqliteInvitationsRepository) CreateInvitation(ctx context.Context, i *invitation.Invitation) error {
+	dto := toInvitationDTO(i)
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO session_invitation (`+invitationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, dto.ID, dto.SessionID, dto.Email, dto.Weight, dto.TokenHash, dto.Status, dto.UserID, dto.CreatedAt, dto.ExpiresAt, dto.AnsweredAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to insert invitation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SqliteInvitationsRepository) GetInvitationByToken(ctx context.Context, tokenHash string) (*invitation.Invitation, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+invitationColumns+`
+		FROM session_invitation
+		WHERE token_hash = ?
+	`, tokenHash)
+
+	i, err := scanInvitation(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, invitation.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to query invitation: %w", err)
+	}
+
+	return i, nil
+}
+
+func (r *SqliteInvitationsRepository) ListInvitations(ctx context.Context, sessionID uuid.UUID) ([]*invitation.Invitation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+invitationColumns+`
+		FROM session_invitation
+		WHERE session_id = ?
+		ORDER BY created_at ASC
+	`, sessionID.String())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var invitations []*invitation.Invitation
+	for rows.Next() {
+		i, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+		invitations = append(invitations, i)
+	}
+
+	return invitations, rows.Err()
+}
+
+// AnswerInvitation only updates a pending row: two redemptions of the same token cannot both succeed
+func (r *SqliteInvitationsRepository) AnswerInvitation(ctx context.Context, i *invitation.Invitation) error {
+	return answerInvitation(ctx, r.db, i)
+}
+
+func (r *SqliteInvitationsRepository) AcceptInvitation(ctx context.Context, i *invitation.Invitation, newUser *user.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// the answer goes first : a lost race stops here, before the user is created
+	if err := answerInvitation(ctx, tx, i); err != nil {
+		return err
+	}
+
+	if newUser != nil {
+		if err := r.users.CreateUserTx(ctx, tx, newUser); err != nil {
+			return err
+		}
+	}
+
+	answeredAt, _ := i.AnsweredAt()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO session_and_participant (user_id, session_id, invited_at, weight)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`, i.UserID().String(), i.SessionID().String(), db.Timestamp{Time: answeredAt}, i.Weight())
+
+	if err != nil {
+		return fmt.Errorf("failed to add participant: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit invitation: %w", err)
+	}
+
+	return nil
+}
+
+func answerInvitation(ctx context.Context, exec interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+}, i *invitation.Invitation) error {
+	dto := toInvitationDTO(i)
+
+	result, err := exec.ExecContext(ctx, `
+		UPDATE session_invitation
+		SET status = ?, user_id = ?, answered_at = ?
+		WHERE id = ? AND status = ?
+	`, dto.Status, dto.UserID, dto.AnsweredAt, dto.ID, string(invitation.StatusPending))
+
+	if err != nil {
+		return fmt.Errorf("failed to answer invitation: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return invitation.ErrAnswered
+	}
+
+	return nil
+}
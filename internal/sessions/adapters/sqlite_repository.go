@@ -20,7 +20,15 @@ type sessionDTO struct {
 	Title       string        // TEXT
 	Description string        // TEXT
 	CreatedAt   db.Timestamp  // TEXT
+	StartsAt    *db.Timestamp // TEXT nullable
 	EndsAt      *db.Timestamp // TEXT nullable
+	QuorumKind  string        // TEXT (none | absolute | percent)
+	QuorumValue int           // INTEGER
+	Secret      bool          // INTEGER (0 or 1)
+	LiveResults bool          // INTEGER (0 or 1)
+	Visibility  string        // TEXT (public | after_close | organizers | voters)
+	State       string        // TEXT (draft | scheduled | open | closed | archived)
+	Template    bool          // INTEGER (0 or 1)
 }
 
 // participantDTO représente session_and_participant en DB
@@ -38,9 +46,20 @@ func toSessionDTO(s *session.Session) sessionDTO {
 		Title:       s.Title(),
 		Description: s.Description(),
 		CreatedAt:   db.Timestamp{Time: s.CreatedAt()},
+		QuorumKind:  string(s.Quorum().Kind),
+		QuorumValue: s.Quorum().Value,
+		Secret:      s.SecretBallot(),
+		LiveResults: s.LiveResults(),
+		Visibility:  string(s.ResultsVisibility()),
+		State:       string(s.State()),
+		Template:    s.Template(),
+	}
+
+	// startsAt et endsAt sont optionnels
+	if startsAt, ok := s.StartsAt(); ok {
+		dto.StartsAt = &db.Timestamp{Time: startsAt}
 	}
 
-	// endsAt est optionnel
 	if endsAt, ok := s.EndsAt(); ok {
 		dto.EndsAt = &db.Timestamp{Time: endsAt}
 	}
@@ -56,15 +75,17 @@ func (dto sessionDTO) toSession() (*session.Session, error) {
 		return nil, fmt.Errorf("invalid session id: %w", err)
 	}
 
-	// Unmarshal avec ou sans endsAt
+	quorum := session.Quorum{Kind: session.QuorumKind(dto.QuorumKind), Value: dto.QuorumValue}
+	visibility := session.Visibility(dto.Visibility)
+	state := session.State(dto.State)
+
+	// startsAt et endsAt sont optionnels
+	var startsAt, endsAt *time.Time
+	if dto.StartsAt != nil {
+		startsAt = &dto.StartsAt.Time
+	}
 	if dto.EndsAt != nil {
-		return session.Rehydrate(
-			id,
-			dto.Title,
-			dto.Description,
-			dto.CreatedAt.Time,
-			&dto.EndsAt.Time,
-		)
+		endsAt = &dto.EndsAt.Time
 	}
 
 	return session.Rehydrate(
@@ -72,7 +93,14 @@ func (dto sessionDTO) toSession() (*session.Session, error) {
 		dto.Title,
 		dto.Description,
 		dto.CreatedAt.Time,
-		nil,
+		startsAt,
+		endsAt,
+		quorum,
+		dto.Secret,
+		dto.LiveResults,
+		visibility,
+		state,
+		dto.Template,
 	)
 }
 
@@ -92,12 +120,38 @@ func NewSqliteSessionRepository(db *sql.DB) *SqliteSessionRepository {
 // ===== Session CRUD =====
 
 func (r *SqliteSessionRepository) CreateVoteSession(ctx context.Context, s *session.Session) error {
+	return insertSession(ctx, r.db, s)
+}
+
+func (r *SqliteSessionRepository) CreateOrganizedVoteSession(ctx context.Context, s *session.Session, organizerID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertSession(ctx, tx, s); err != nil {
+		return err
+	}
+
+	if err := insertMember(ctx, tx, s.ID(), organizerID, session.RoleOrganizer, session.DefaultWeight); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertSession(ctx context.Context, exec execer, s *session.Session) error {
 	dto := toSessionDTO(s)
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO vote_session (id, title, description, created_at, ends_at)
-		VALUES (?, ?, ?, ?, ?)
-	`, dto.ID, dto.Title, dto.Description, dto.CreatedAt, dto.EndsAt)
+	_, err := exec.ExecContext(ctx, `
+		INSERT INTO vote_session (id, title, description, created_at, starts_at, ends_at, quorum_kind, quorum_value, secret_ballot, live_results, results_visibility, state, template)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, dto.ID, dto.Title, dto.Description, dto.CreatedAt, dto.StartsAt, dto.EndsAt, dto.QuorumKind, dto.QuorumValue, dto.Secret, dto.LiveResults, dto.Visibility, dto.State, dto.Template)
 
 	if err != nil {
 		return fmt.Errorf("failed to insert session: %w", err)
@@ -106,11 +160,25 @@ func (r *SqliteSessionRepository) CreateVoteSession(ctx context.Context, s *sess
 	return nil
 }
 
+// insertMember adds userID to a new session with its role and weight
+func insertMember(ctx context.Context, exec execer, sessionID, userID uuid.UUID, role session.Role, weight int) error {
+	_, err := exec.ExecContext(ctx, `
+		INSERT INTO session_and_participant (user_id, session_id, invited_at, role, weight)
+		VALUES (?, ?, ?, ?, ?)
+	`, userID.String(), sessionID.String(), db.Timestamp{Time: time.Now().UTC()}, string(role), weight)
+
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", role, err)
+	}
+
+	return nil
+}
+
 func (r *SqliteSessionRepository) GetVoteSessionByID(ctx context.Context, id uuid.UUID) (*session.Session, error) {
 	var dto sessionDTO
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, title, description, created_at, ends_at
+		SELECT id, title, description, created_at, starts_at, ends_at, quorum_kind, quorum_value, secret_ballot, live_results, results_visibility, state, template
 		FROM vote_session
 		WHERE id = ?
 	`, id.String()).Scan(
@@ -118,7 +186,15 @@ func (r *SqliteSessionRepository) GetVoteSessionByID(ctx context.Context, id uui
 		&dto.Title,
 		&dto.Description,
 		&dto.CreatedAt,
+		&dto.StartsAt,
 		&dto.EndsAt,
+		&dto.QuorumKind,
+		&dto.QuorumValue,
+		&dto.Secret,
+		&dto.LiveResults,
+		&dto.Visibility,
+		&dto.State,
+		&dto.Template,
 	)
 
 	if err != nil {
@@ -133,7 +209,7 @@ func (r *SqliteSessionRepository) GetVoteSessionByID(ctx context.Context, id uui
 
 func (r *SqliteSessionRepository) GetUserVoteSessions(ctx context.Context, userID uuid.UUID) ([]*session.Session, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT vs.id, vs.title, vs.description, vs.created_at, vs.ends_at
+		SELECT vs.id, vs.title, vs.description, vs.created_at, vs.starts_at, vs.ends_at, vs.quorum_kind, vs.quorum_value, vs.secret_ballot, vs.live_results, vs.results_visibility, vs.state, vs.template
 		FROM vote_session vs
 		INNER JOIN session_and_participant sp ON vs.id = sp.session_id
 		WHERE sp.user_id = ?
@@ -148,7 +224,7 @@ func (r *SqliteSessionRepository) GetUserVoteSessions(ctx context.Context, userI
 	var sessions []*session.Session
 	for rows.Next() {
 		var dto sessionDTO
-		err := rows.Scan(&dto.ID, &dto.Title, &dto.Description, &dto.CreatedAt, &dto.EndsAt)
+		err := rows.Scan(&dto.ID, &dto.Title, &dto.Description, &dto.CreatedAt, &dto.StartsAt, &dto.EndsAt, &dto.QuorumKind, &dto.QuorumValue, &dto.Secret, &dto.LiveResults, &dto.Visibility, &dto.State, &dto.Template)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
 		}
@@ -168,11 +244,14 @@ func (r *SqliteSessionRepository) UpdateVoteSession(ctx context.Context, s *sess
 
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE vote_session
-		SET title = ?, description = ?, ends_at = ?
+		SET title = ?, description = ?, starts_at = ?, ends_at = ?, quorum_kind = ?, quorum_value = ?, secret_ballot = ?, live_results = ?, results_visibility = ?
 		WHERE id = ?
-	`, dto.Title, dto.Description, dto.EndsAt, dto.ID)
+	`, dto.Title, dto.Description, dto.StartsAt, dto.EndsAt, dto.QuorumKind, dto.QuorumValue, dto.Secret, dto.LiveResults, dto.Visibility, dto.ID)
 
 	if err != nil {
+		if db.IsSessionFinalized(err) {
+			return session.ErrFinalized
+		}
 		return fmt.Errorf("failed to update session: %w", err)
 	}
 
@@ -182,34 +261,61 @@ func (r *SqliteSessionRepository) UpdateVoteSession(ctx context.Context, s *sess
 func (r *SqliteSessionRepository) DeleteVoteSession(ctx context.Context, id uuid.UUID) error {
 	_, err := r.db.ExecContext(ctx, `DELETE FROM vote_session WHERE id = ?`, id.String())
 	if err != nil {
+		if db.IsSessionFinalized(err) {
+			return session.ErrFinalized
+		}
 		return fmt.Errorf("failed to delete session %s: %w", id, err)
 	}
 	return nil
 }
 
-func (r *SqliteSessionRepository) CloseVoteSession(ctx context.Context, id uuid.UUID) error {
-	now := db.Timestamp{Time: time.Now().UTC()}
-
+func (r *SqliteSessionRepository) CloseVoteSession(ctx context.Context, id uuid.UUID, at time.Time) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE vote_session
-		SET ends_at = ?
+		SET ends_at = ?, state = ?
 		WHERE id = ?
-	`, now, id.String())
+	`, db.Timestamp{Time: at.UTC()}, string(session.StateClosed), id.String())
 
 	if err != nil {
+		if db.IsSessionFinalized(err) {
+			return session.ErrFinalized
+		}
 		return fmt.Errorf("failed to close session: %w", err)
 	}
 
 	return nil
 }
 
+func (r *SqliteSessionRepository) SetVoteSessionState(ctx context.Context, id uuid.UUID, state session.State) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE vote_session
+		SET state = ?
+		WHERE id = ?
+	`, string(state), id.String())
+
+	if err != nil {
+		return fmt.Errorf("failed to set session state: %w", err)
+	}
+
+	return nil
+}
+
 func (r *SqliteSessionRepository) ListVoteSessions(ctx context.Context, limit, offset int) ([]*session.Session, error) {
+	return r.listVoteSessions(ctx, false, limit, offset)
+}
+
+func (r *SqliteSessionRepository) ListVoteSessionTemplates(ctx context.Context, limit, offset int) ([]*session.Session, error) {
+	return r.listVoteSessions(ctx, true, limit, offset)
+}
+
+func (r *SqliteSessionRepository) listVoteSessions(ctx context.Context, template bool, limit, offset int) ([]*session.Session, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, title, description, created_at, ends_at
+		SELECT id, title, description, created_at, starts_at, ends_at, quorum_kind, quorum_value, secret_ballot, live_results, results_visibility, state, template
 		FROM vote_session
+		WHERE template = ?
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
-	`, limit, offset)
+	`, template, limit, offset)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sessions: %w", err)
@@ -219,7 +325,40 @@ func (r *SqliteSessionRepository) ListVoteSessions(ctx context.Context, limit, o
 	var sessions []*session.Session
 	for rows.Next() {
 		var dto sessionDTO
-		err := rows.Scan(&dto.ID, &dto.Title, &dto.Description, &dto.CreatedAt, &dto.EndsAt)
+		err := rows.Scan(&dto.ID, &dto.Title, &dto.Description, &dto.CreatedAt, &dto.StartsAt, &dto.EndsAt, &dto.QuorumKind, &dto.QuorumValue, &dto.Secret, &dto.LiveResults, &dto.Visibility, &dto.State, &dto.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+
+		s, err := dto.toSession()
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *SqliteSessionRepository) ListPendingVoteSessions(ctx context.Context) ([]*session.Session, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, title, description, created_at, starts_at, ends_at, quorum_kind, quorum_value, secret_ballot, live_results, results_visibility, state, template
+		FROM vote_session vs
+		WHERE (state = ? AND starts_at IS NOT NULL)
+		   OR (state = ? AND ends_at IS NOT NULL)
+		   OR (state = ? AND NOT EXISTS (SELECT 1 FROM result_history rh WHERE rh.session_id = vs.id))
+		ORDER BY created_at ASC
+	`, string(session.StateScheduled), string(session.StateOpen), string(session.StateClosed))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*session.Session
+	for rows.Next() {
+		var dto sessionDTO
+		err := rows.Scan(&dto.ID, &dto.Title, &dto.Description, &dto.CreatedAt, &dto.StartsAt, &dto.EndsAt, &dto.QuorumKind, &dto.QuorumValue, &dto.Secret, &dto.LiveResults, &dto.Visibility, &dto.State, &dto.Template)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
 		}
@@ -245,6 +384,9 @@ func (r *SqliteSessionRepository) AddParticipant(ctx context.Context, sessionID,
 	`, userID.String(), sessionID.String(), now)
 
 	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return session.ErrAlreadyParticipant
+		}
 		return fmt.Errorf("failed to add participant: %w", err)
 	}
 
@@ -291,7 +433,7 @@ func (r *SqliteSessionRepository) GetParticipants(ctx context.Context, sessionID
 }
 
 func (r *SqliteSessionRepository) RemoveParticipant(ctx context.Context, sessionID, userID uuid.UUID) error {
-	_, err := r.db.ExecContext(ctx, `
+	result, err := r.db.ExecContext(ctx, `
 		DELETE FROM session_and_participant
 		WHERE session_id = ? AND user_id = ?
 	`, sessionID.String(), userID.String())
@@ -300,9 +442,102 @@ func (r *SqliteSessionRepository) RemoveParticipant(ctx context.Context, session
 		return fmt.Errorf("failed to remove participant: %w", err)
 	}
 
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return session.ErrNotParticipant
+	}
+
+	return nil
+}
+
+func (r *SqliteSessionRepository) AddOrganizer(ctx context.Context, sessionID, userID uuid.UUID) error {
+	now := db.Timestamp{Time: time.Now().UTC()}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO session_and_participant (user_id, session_id, invited_at, role)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id, session_id) DO UPDATE SET role = excluded.role
+	`, userID.String(), sessionID.String(), now, string(session.RoleOrganizer))
+
+	if err != nil {
+		return fmt.Errorf("failed to add organizer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SqliteSessionRepository) IsOrganizer(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	var count int
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM session_and_participant
+		WHERE session_id = ? AND user_id = ? AND role = ?
+	`, sessionID.String(), userID.String(), string(session.RoleOrganizer)).Scan(&count)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check organizer: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *SqliteSessionRepository) SetWeight(ctx context.Context, sessionID, userID uuid.UUID, weight int) error {
+	if err := session.CheckWeight(weight); err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE session_and_participant
+		SET weight = ?
+		WHERE session_id = ? AND user_id = ?
+	`, weight, sessionID.String(), userID.String())
+
+	if err != nil {
+		return fmt.Errorf("failed to set participant weight: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return session.ErrNotParticipant
+	}
+
 	return nil
 }
 
+func (r *SqliteSessionRepository) GetWeight(ctx context.Context, sessionID, userID uuid.UUID) (int, error) {
+	var weight int
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT weight
+		FROM session_and_participant
+		WHERE session_id = ? AND user_id = ?
+	`, sessionID.String(), userID.String()).Scan(&weight)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, session.ErrNotParticipant
+		}
+		return 0, fmt.Errorf("failed to get participant weight: %w", err)
+	}
+
+	return weight, nil
+}
+
+func (r *SqliteSessionRepository) WeighParticipants(ctx context.Context, sessionID uuid.UUID) (int, error) {
+	var total int
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(weight), 0)
+		FROM session_and_participant
+		WHERE session_id = ?
+	`, sessionID.String()).Scan(&total)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to weigh participants: %w", err)
+	}
+
+	return total, nil
+}
+
 func (r *SqliteSessionRepository) IsParticipant(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
 	var count int
 
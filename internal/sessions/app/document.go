@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+
+	"github.com/73NN0/voting-app/internal/sessions/domain/document"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/google/uuid"
+)
+
+// DocumentService imports a session described by a document and exports a session back
+// into the same format, so that it can be versioned next to the code
+type DocumentService struct {
+	sessions  session.Repository
+	documents document.Repository
+	questions Questions
+}
+
+func NewDocumentService(sessionRepository session.Repository, documentRepository document.Repository, questions Questions) *DocumentService {
+	if sessionRepository == nil {
+		panic("missing session repository")
+	}
+
+	if documentRepository == nil {
+		panic("missing document repository")
+	}
+
+	if questions == nil {
+		panic("missing questions")
+	}
+
+	return &DocumentService{
+		sessions:  sessionRepository,
+		documents: documentRepository,
+		questions: questions,
+	}
+}
+
+// ImportSession creates a draft session organized by organizerID from the document,
+// nothing is stored when a part of the document is invalid
+func (s *DocumentService) ImportSession(ctx context.Context, organizerID uuid.UUID, doc document.Document) (*session.Session, error) {
+	vs, entries, err := doc.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.documents.ImportSession(ctx, vs, organizerID, entries); err != nil {
+		return nil, err
+	}
+
+	return vs, nil
+}
+
+// ExportSession : the document is public, like the session and its questions
+func (s *DocumentService) ExportSession(ctx context.Context, sessionID uuid.UUID) (document.Document, error) {
+	vs, err := s.sessions.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		return document.Document{}, err
+	}
+
+	entries, err := s.questions.SessionEntries(ctx, sessionID)
+	if err != nil {
+		return document.Document{}, err
+	}
+
+	return document.FromSession(vs, entries), nil
+}
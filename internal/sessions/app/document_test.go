@@ -0,0 +1,101 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	questions "github.com/73NN0/voting-app/internal/questions/adapters"
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
+	"github.com/73NN0/voting-app/internal/sessions/adapters"
+	"github.com/73NN0/voting-app/internal/sessions/app"
+	"github.com/73NN0/voting-app/internal/sessions/domain/document"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/google/uuid"
+)
+
+func TestDocumentService_ImportExport(t *testing.T) {
+	ctx := context.Background()
+	database := newDatabase(t)
+	repo := adapters.NewSqliteSessionRepository(database)
+	questionRepo := questions.NewSqliteQuestionsRepository(database)
+	choiceRepo := questions.NewSqliteChoicesRepositoy(database)
+	service := app.NewDocumentService(
+		repo,
+		adapters.NewSqliteDocumentsRepository(database, questionRepo, choiceRepo),
+		adapters.NewQuestionsInProcess(questionRepo, choiceRepo),
+	)
+
+	// GIVEN un document de deux questions
+	doc := document.Document{
+		Title:        "AG",
+		Description:  "assemblée annuelle",
+		SecretBallot: true,
+		Questions: []document.Question{
+			{OrderNum: 2, Text: "Élire le trésorier", MaxChoices: 1, Choices: []document.Choice{{OrderNum: 1, Text: "Alice"}, {OrderNum: 2, Text: "Bob"}}},
+			{OrderNum: 1, Text: "Approuver les comptes ?", Kind: string(question.KindApproval), Choices: []document.Choice{{OrderNum: 1, Text: "Oui"}}},
+		},
+	}
+	organizer := uuid.New()
+
+	// WHEN on l'importe puis on exporte la session créée
+	vs, err := service.ImportSession(ctx, organizer, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := service.ExportSession(ctx, vs.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN l'importeur organise le brouillon créé
+	if ok, err := repo.IsOrganizer(ctx, vs.ID(), organizer); err != nil || !ok {
+		t.Errorf("importer is not organizer: %v", err)
+	}
+	if vs.State() != session.StateDraft {
+		t.Errorf("state = %s, want %s", vs.State(), session.StateDraft)
+	}
+
+	// AND l'export redonne le document, questions dans l'ordre de order_num
+	if exported.Title != "AG" || !exported.SecretBallot || len(exported.Questions) != 2 {
+		t.Fatalf("exported = %+v", exported)
+	}
+	if q := exported.Questions[0]; q.OrderNum != 1 || q.Kind != string(question.KindApproval) || len(q.Choices) != 1 {
+		t.Errorf("question 1 = %+v", q)
+	}
+	if q := exported.Questions[1]; q.Text != "Élire le trésorier" || len(q.Choices) != 2 || q.Choices[1].Text != "Bob" {
+		t.Errorf("question 2 = %+v", q)
+	}
+}
+
+func TestSqliteDocumentsRepository_ImportIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	database := newDatabase(t)
+	repo := adapters.NewSqliteSessionRepository(database)
+	documents := adapters.NewSqliteDocumentsRepository(database, questions.NewSqliteQuestionsRepository(database), questions.NewSqliteChoicesRepositoy(database))
+
+	// GIVEN deux entrées de même order_num, que seule la base refuse
+	vs, err := session.NewSessionNoEnd("AG", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []document.Entry
+	for range 2 {
+		q, err := question.NewQuestion(vs.ID(), "Oui ?", 1, 1, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, document.Entry{Question: q, Choices: []choice.Choice{choice.NewChoice(0, 1, "Oui")}})
+	}
+
+	// WHEN on importe
+	if err := documents.ImportSession(ctx, vs, uuid.New(), entries); err == nil {
+		t.Fatal("import of a duplicated order_num succeeded")
+	}
+
+	// THEN rien n'est resté en base
+	if _, err := repo.GetVoteSessionByID(ctx, vs.ID()); !errors.Is(err, session.ErrNotFound) {
+		t.Errorf("GetVoteSessionByID() err = %v, want %v", err, session.ErrNotFound)
+	}
+}
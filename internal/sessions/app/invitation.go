@@ -0,0 +1,224 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/sessions/domain/invitation"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/73NN0/voting-app/internal/users/domain/user"
+	"github.com/google/uuid"
+)
+
+// Users gives the users context a way to find the user redeeming an invitation,
+// the invitation repository creates it when there is none
+type Users interface {
+	// UserIDByEmail returns uuid.Nil when no user has this email
+	UserIDByEmail(ctx context.Context, email string) (uuid.UUID, error)
+}
+
+// InvitationService invites people to a session by email. The organizer gets the token once,
+// when the invitation is created, and shares the link; whoever holds the token answers it.
+type InvitationService struct {
+	sessions    session.Repository
+	invitations invitation.Repository
+	users       Users
+	clock       clock.Clock
+}
+
+func NewInvitationService(sessionRepository session.Repository, invitationRepository invitation.Repository, users Users, clk clock.Clock) *InvitationService {
+	if sessionRepository == nil {
+		panic("missing session repository")
+	}
+
+	if invitationRepository == nil {
+		panic("missing invitation repository")
+	}
+
+	if users == nil {
+		panic("missing users")
+	}
+
+	if clk == nil {
+		panic("missing clock")
+	}
+
+	return &InvitationService{
+		sessions:    sessionRepository,
+		invitations: invitationRepository,
+		users:       users,
+		clock:       clk,
+	}
+}
+
+// notEnded : nobody joins a closed or archived session
+func notEnded(vs *session.Session, now time.Time) error {
+	if st := vs.StateAt(now); st == session.StateClosed || st == session.StateArchived {
+		return session.ErrEnded
+	}
+	return nil
+}
+
+// Invite creates an invitation of email to the session and returns its token, the only time it is given.
+// The invitation expires after invitation.DefaultTTL when expiresAt is nil.
+func (s *InvitationService) Invite(
+	ctx context.Context,
+	organizerID, sessionID uuid.UUID,
+	email string,
+	weight int,
+	expiresAt *time.Time,
+) (*invitation.Invitation, string, error) {
+	if err := session.CheckWeight(weight); err != nil {
+		return nil, "", err
+	}
+
+	vs, err := organizedSession(ctx, s.sessions, sessionID, organizerID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.clock.Now()
+
+	if err := notEnded(vs, now); err != nil {
+		return nil, "", err
+	}
+
+	if err := vs.CheckParticipantWeight(weight); err != nil {
+		return nil, "", err
+	}
+
+	invitations, err := s.invitations.ListInvitations(ctx, sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, i := range invitations {
+		if i.Email() == email && i.StatusAt(now) == invitation.StatusPending {
+			return nil, "", invitation.ErrAlreadyInvited
+		}
+	}
+
+	expiry := now.Add(invitation.DefaultTTL)
+	if expiresAt != nil {
+		expiry = expiresAt.UTC()
+	}
+
+	inv, token, err := invitation.New(sessionID, email, weight, expiry, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.invitations.CreateInvitation(ctx, inv); err != nil {
+		return nil, "", err
+	}
+
+	return inv, token, nil
+}
+
+func (s *InvitationService) ListInvitations(ctx context.Context, organizerID, sessionID uuid.UUID) ([]*invitation.Invitation, error) {
+	if _, err := organizedSession(ctx, s.sessions, sessionID, organizerID); err != nil {
+		return nil, err
+	}
+
+	return s.invitations.ListInvitations(ctx, sessionID)
+}
+
+func (s *InvitationService) GetInvitation(ctx context.Context, token string) (*invitation.Invitation, error) {
+	return s.invitations.GetInvitationByToken(ctx, invitation.Hash(token))
+}
+
+// AcceptInvitation adds the user to the session with the weight of the invitation.
+// userID is the caller, uuid.Nil when anonymous: the invitation then goes to the user with
+// its email, created with name when there is none.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, token string, userID uuid.UUID, name string) (*invitation.Invitation, error) {
+	inv, vs, now, err := s.answerable(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// the session became a secret ballot after the invitation
+	if err := vs.CheckParticipantWeight(inv.Weight()); err != nil {
+		return nil, err
+	}
+
+	var newUser *user.User
+	if userID == uuid.Nil {
+		if userID, newUser, err = s.userFor(ctx, inv.Email(), name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := inv.Accept(userID, now); err != nil {
+		return nil, err
+	}
+
+	// answer, new user and participant go in one transaction: the answer is what makes
+	// the token single-use, a redemption losing the race stores nothing
+	if err := s.invitations.AcceptInvitation(ctx, inv, newUser); err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+func (s *InvitationService) DeclineInvitation(ctx context.Context, token string) (*invitation.Invitation, error) {
+	inv, _, now, err := s.answerable(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := inv.Decline(now); err != nil {
+		return nil, err
+	}
+
+	if err := s.invitations.AnswerInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+// answerable loads the invitation of the token, still pending, and its session, not ended
+func (s *InvitationService) answerable(ctx context.Context, token string) (*invitation.Invitation, *session.Session, time.Time, error) {
+	inv, err := s.invitations.GetInvitationByToken(ctx, invitation.Hash(token))
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	now := s.clock.Now()
+
+	if err := inv.Answerable(now); err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	vs, err := s.sessions.GetVoteSessionByID(ctx, inv.SessionID())
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	if err := notEnded(vs, now); err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	return inv, vs, now, nil
+}
+
+// userFor returns the user with email, or a new one named name, not stored yet, when there is none
+func (s *InvitationService) userFor(ctx context.Context, email, name string) (uuid.UUID, *user.User, error) {
+	userID, err := s.users.UserIDByEmail(ctx, email)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	if userID != uuid.Nil {
+		return userID, nil, nil
+	}
+
+	created, err := user.NewUser(name, email)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	return created.ID(), created, nil
+}
@@ -0,0 +1,82 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/sessions/adapters"
+	"github.com/73NN0/voting-app/internal/sessions/app"
+	"github.com/73NN0/voting-app/internal/sessions/domain/invitation"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	users "github.com/73NN0/voting-app/internal/users/adapters"
+	"github.com/73NN0/voting-app/internal/users/domain/user"
+	"github.com/google/uuid"
+)
+
+func TestInvitationService_AcceptInvitationLosingTheRace(t *testing.T) {
+	ctx := context.Background()
+	database := newDatabase(t)
+	repo := adapters.NewSqliteSessionRepository(database)
+	userRepo := users.NewSqliteUserRepository(database)
+	invitations := adapters.NewSqliteInvitationsRepository(database, userRepo)
+	service := app.NewInvitationService(repo, invitations, adapters.NewUsersInProcess(userRepo), clock.System{})
+
+	// GIVEN une invitation pondérée, lue par deux personnes qui ont le lien
+	organizer, err := user.NewUser("alice", "alice@example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := userRepo.CreateUser(ctx, organizer); err != nil {
+		t.Fatal(err)
+	}
+	vs, err := newService(database, &fakeQuestions{}, clock.System{}).CreateSession(ctx, organizer.ID(), app.Settings{Title: "AG", ResultsVisibility: session.VisibilityPublic, Quorum: session.NoQuorum()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, token, err := service.Invite(ctx, organizer.ID(), vs.ID(), "dave@example.org", 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := service.GetInvitation(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN la première l'accepte, anonymement
+	inv, err := service.AcceptInvitation(ctx, token, uuid.Nil, "dave")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN l'utilisateur est créé et participe avec le poids de l'invitation
+	if _, err := userRepo.GetUserByID(ctx, inv.UserID()); err != nil {
+		t.Fatalf("invited user not created: %v", err)
+	}
+	if w, err := repo.GetWeight(ctx, vs.ID(), inv.UserID()); err != nil || w != 2 {
+		t.Errorf("weight = %d, %v, want 2", w, err)
+	}
+
+	// WHEN la seconde, qui a lu l'invitation encore en attente, l'accepte aussi
+	loser, err := user.NewUser("dave", "dave@example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stale.Accept(loser.ID(), time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+	err = invitations.AcceptInvitation(ctx, stale, loser)
+
+	// THEN elle perd, sans créer d'utilisateur ni de participant
+	if !errors.Is(err, invitation.ErrAnswered) {
+		t.Fatalf("AcceptInvitation() err = %v, want %v", err, invitation.ErrAnswered)
+	}
+	if _, err := userRepo.GetUserByID(ctx, loser.ID()); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("GetUserByID() err = %v, want %v", err, user.ErrNotFound)
+	}
+	if ok, err := repo.IsParticipant(ctx, vs.ID(), loser.ID()); err != nil || ok {
+		t.Errorf("losing user is participant: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/common/logger"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/google/uuid"
+)
+
+// ErrDecisionPending : a tie of the session waits for the decision of an organizer
+var ErrDecisionPending = errors.New("finalization waits for the decision of an organizer")
+
+// Finalizer gives the votes context a way to close and finalize a session once it ended.
+// Finalize returns ErrDecisionPending while an organizer has to break a tie.
+type Finalizer interface {
+	Finalize(ctx context.Context, sessionID uuid.UUID) error
+}
+
+const (
+	// firstRetry is the delay before retrying a failed transition, doubled on every failure up to lastRetry
+	firstRetry = 30 * time.Second
+	lastRetry  = time.Hour
+)
+
+// Scheduler opens the scheduled sessions at their start date and finalizes the sessions
+// once they are closed, at their end date or by an organizer.
+//
+// Nothing is planned in memory: every tick reads the pending sessions from the database,
+// so the transitions missed while the process was stopped are applied on the first tick.
+// A transition which fails is retried with a growing delay. A finalization waiting for
+// a tie-break is not a failure: it is tried again on every tick, until an organizer decides.
+type Scheduler struct {
+	sessions  session.Repository
+	finalizer Finalizer
+	clock     clock.Clock
+
+	mu      sync.Mutex
+	retries map[uuid.UUID]retry
+}
+
+// retry of a failed transition
+type retry struct {
+	failures int
+	at       time.Time
+}
+
+func NewScheduler(sessionRepository session.Repository, finalizer Finalizer, clk clock.Clock) *Scheduler {
+	if sessionRepository == nil {
+		panic("missing session repository")
+	}
+
+	if finalizer == nil {
+		panic("missing finalizer")
+	}
+
+	if clk == nil {
+		panic("missing clock")
+	}
+
+	return &Scheduler{
+		sessions:  sessionRepository,
+		finalizer: finalizer,
+		clock:     clk,
+		retries:   make(map[uuid.UUID]retry),
+	}
+}
+
+// Run ticks at once, then every interval until ctx is done
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx); err != nil {
+			logger.Logger.Error("scheduler tick failed", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick applies the transitions due at the time of the clock
+func (s *Scheduler) Tick(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, err := s.sessions.ListPendingVoteSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.clock.Now()
+
+	// the sessions no longer pending are forgotten, finalized or deleted meanwhile
+	for id := range s.retries {
+		if !slices.ContainsFunc(pending, func(vs *session.Session) bool { return vs.ID() == id }) {
+			delete(s.retries, id)
+		}
+	}
+
+	for _, vs := range pending {
+		r, failed := s.retries[vs.ID()]
+		if failed && now.Before(r.at) {
+			continue
+		}
+
+		err := s.apply(ctx, vs, now)
+		switch {
+		case err == nil, errors.Is(err, ErrDecisionPending):
+			delete(s.retries, vs.ID())
+		default:
+			r.failures++
+			r.at = now.Add(backoff(r.failures))
+			s.retries[vs.ID()] = r
+			logger.Logger.Error("scheduled transition failed", "session", vs.ID(), "err", err, "retry_at", r.at)
+		}
+	}
+
+	return nil
+}
+
+// backoff is the delay before the next try after failures failed tries
+func backoff(failures int) time.Duration {
+	delay := firstRetry
+	for i := 1; i < failures && delay < lastRetry; i++ {
+		delay *= 2
+	}
+	return min(delay, lastRetry)
+}
+
+func (s *Scheduler) apply(ctx context.Context, vs *session.Session, now time.Time) error {
+	switch vs.StateAt(now) {
+	case session.StateScheduled:
+		startsAt, ok := vs.StartsAt()
+		if !ok || now.Before(startsAt) {
+			return nil
+		}
+
+		// the whole window passed while the process was stopped
+		if endsAt, ok := vs.EndsAt(); ok && !now.Before(endsAt) {
+			if err := vs.Expire(now); err != nil {
+				return err
+			}
+
+			logger.Logger.Info("closing scheduled session already ended", "session", vs.ID())
+			if err := s.sessions.SetVoteSessionState(ctx, vs.ID(), session.StateClosed); err != nil {
+				return err
+			}
+			return s.finalizer.Finalize(ctx, vs.ID())
+		}
+
+		if err := vs.Open(now); err != nil {
+			return err
+		}
+
+		logger.Logger.Info("opening scheduled session", "session", vs.ID())
+		return s.sessions.SetVoteSessionState(ctx, vs.ID(), session.StateOpen)
+
+	case session.StateClosed:
+		logger.Logger.Info("finalizing ended session", "session", vs.ID())
+		return s.finalizer.Finalize(ctx, vs.ID())
+	}
+
+	return nil
+}
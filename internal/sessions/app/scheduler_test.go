@@ -0,0 +1,280 @@
+package app_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/sessions/adapters"
+	"github.com/73NN0/voting-app/internal/sessions/app"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/google/uuid"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+type fakeFinalizer struct {
+	err   error
+	calls []uuid.UUID
+}
+
+func (f *fakeFinalizer) Finalize(_ context.Context, sessionID uuid.UUID) error {
+	f.calls = append(f.calls, sessionID)
+	return f.err
+}
+
+func newDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanup)
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	return database
+}
+
+func newSessionRepository(t *testing.T) session.Repository {
+	t.Helper()
+	return adapters.NewSqliteSessionRepository(newDatabase(t))
+}
+
+func TestScheduler_OpensAtStartDate(t *testing.T) {
+	ctx := context.Background()
+	repo := newSessionRepository(t)
+	clk := &fakeClock{now: time.Now().UTC()}
+
+	// GIVEN une session planifiée dans une heure
+	vs, err := session.NewSessionNoEnd("Budget", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.SetStartDate(clk.now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.Schedule(clk.now); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateVoteSession(ctx, vs); err != nil {
+		t.Fatal(err)
+	}
+
+	scheduler := app.NewScheduler(repo, &fakeFinalizer{}, clk)
+
+	// WHEN le planificateur passe avant la date de début
+	if err := scheduler.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN la session reste planifiée
+	got, err := repo.GetVoteSessionByID(ctx, vs.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State() != session.StateScheduled {
+		t.Fatalf("state before start = %s, want %s", got.State(), session.StateScheduled)
+	}
+
+	// WHEN il repasse une fois la date de début atteinte
+	clk.now = clk.now.Add(time.Hour)
+	if err := scheduler.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN la session est ouverte
+	got, err = repo.GetVoteSessionByID(ctx, vs.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State() != session.StateOpen {
+		t.Fatalf("state after start = %s, want %s", got.State(), session.StateOpen)
+	}
+}
+
+func TestScheduler_FinalizesEndedSessions(t *testing.T) {
+	ctx := context.Background()
+	repo := newSessionRepository(t)
+	clk := &fakeClock{now: time.Now().UTC()}
+
+	// GIVEN une session ouverte qui finit dans une heure
+	vs, err := session.NewSessionWithEnd("Bureau", "", clk.now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.Open(clk.now); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateVoteSession(ctx, vs); err != nil {
+		t.Fatal(err)
+	}
+
+	finalizer := &fakeFinalizer{}
+	scheduler := app.NewScheduler(repo, finalizer, clk)
+
+	// WHEN le planificateur passe avant la fin
+	if err := scheduler.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN rien n'est finalisé
+	if len(finalizer.calls) != 0 {
+		t.Fatalf("finalized before the end: %v", finalizer.calls)
+	}
+
+	// WHEN il repasse après la fin
+	clk.now = clk.now.Add(2 * time.Hour)
+	if err := scheduler.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN la session est finalisée
+	if len(finalizer.calls) != 1 || finalizer.calls[0] != vs.ID() {
+		t.Fatalf("finalize calls = %v, want [%s]", finalizer.calls, vs.ID())
+	}
+}
+
+func TestScheduler_RetriesFailedTransitionLater(t *testing.T) {
+	ctx := context.Background()
+	repo := newSessionRepository(t)
+	clk := &fakeClock{now: time.Now().UTC()}
+
+	// GIVEN une session close dont la finalisation échoue
+	vs, err := session.NewSessionNoEnd("Statuts", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.Open(clk.now); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateVoteSession(ctx, vs); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CloseVoteSession(ctx, vs.ID(), time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+
+	finalizer := &fakeFinalizer{err: errors.New("database is locked")}
+	scheduler := app.NewScheduler(repo, finalizer, clk)
+
+	// WHEN le planificateur repasse aussitôt
+	for range 2 {
+		if err := scheduler.Tick(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// THEN la finalisation n'est tentée qu'une fois
+	if len(finalizer.calls) != 1 {
+		t.Fatalf("finalize calls = %d, want 1", len(finalizer.calls))
+	}
+
+	// WHEN il repasse après le délai
+	finalizer.err = nil
+	clk.now = clk.now.Add(time.Minute)
+	if err := scheduler.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN il retente la finalisation
+	if len(finalizer.calls) != 2 {
+		t.Fatalf("finalize calls after the delay = %d, want 2", len(finalizer.calls))
+	}
+}
+
+func TestScheduler_WaitsForTieBreakDecision(t *testing.T) {
+	ctx := context.Background()
+	repo := newSessionRepository(t)
+	clk := &fakeClock{now: time.Now().UTC()}
+
+	// GIVEN une session close dont une égalité attend un organisateur
+	vs, err := session.NewSessionNoEnd("Bureau", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.Open(clk.now); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateVoteSession(ctx, vs); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CloseVoteSession(ctx, vs.ID(), time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+
+	finalizer := &fakeFinalizer{err: app.ErrDecisionPending}
+	scheduler := app.NewScheduler(repo, finalizer, clk)
+
+	// WHEN le planificateur passe avant puis après la décision
+	if err := scheduler.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+	finalizer.err = nil
+	if err := scheduler.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN la finalisation est retentée dès le passage suivant
+	if len(finalizer.calls) != 2 {
+		t.Fatalf("finalize calls = %d, want 2", len(finalizer.calls))
+	}
+}
+
+func TestScheduler_ClosesScheduledSessionAlreadyEnded(t *testing.T) {
+	ctx := context.Background()
+	repo := newSessionRepository(t)
+	clk := &fakeClock{now: time.Now().UTC()}
+
+	// GIVEN une session planifiée qui ouvre dans une heure et finit dans trois
+	end := clk.now.Add(3 * time.Hour)
+	vs, err := session.NewSessionWithEnd("AG", "", end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.SetStartDate(clk.now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.Schedule(clk.now); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateVoteSession(ctx, vs); err != nil {
+		t.Fatal(err)
+	}
+
+	finalizer := &fakeFinalizer{}
+	scheduler := app.NewScheduler(repo, finalizer, clk)
+
+	// WHEN le planificateur ne passe qu'après la fin, arrêté pendant toute la session
+	clk.now = clk.now.Add(4 * time.Hour)
+	if err := scheduler.Tick(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN la session est close à sa date de fin puis finalisée
+	got, err := repo.GetVoteSessionByID(ctx, vs.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State() != session.StateClosed {
+		t.Fatalf("state = %s, want %s", got.State(), session.StateClosed)
+	}
+	if endsAt, _ := got.EndsAt(); !endsAt.Equal(end) {
+		t.Errorf("ends_at = %s, want %s", endsAt, end)
+	}
+	if len(finalizer.calls) != 1 || finalizer.calls[0] != vs.ID() {
+		t.Fatalf("finalize calls = %v, want [%s]", finalizer.calls, vs.ID())
+	}
+}
@@ -0,0 +1,369 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/sessions/domain/document"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/google/uuid"
+)
+
+var ErrNotOrganizer = errors.New("user is not an organizer of this vote session")
+
+// Settings are the fields of a session given on creation and replaced on update
+type Settings struct {
+	Title             string
+	Description       string
+	StartsAt          *time.Time // needed to schedule the session
+	EndsAt            *time.Time // no end when nil
+	Quorum            session.Quorum
+	SecretBallot      bool
+	LiveResults       bool
+	ResultsVisibility session.Visibility
+}
+
+func (st Settings) apply(s *session.Session) error {
+	if err := s.UpdateTitle(st.Title); err != nil {
+		return err
+	}
+
+	s.UpdateDescription(st.Description)
+
+	if st.EndsAt != nil {
+		s.SetEndDate(*st.EndsAt)
+	} else {
+		s.RemoveEndDate()
+	}
+
+	if st.StartsAt != nil {
+		if err := s.SetStartDate(*st.StartsAt); err != nil {
+			return err
+		}
+	} else if err := s.RemoveStartDate(); err != nil {
+		return err
+	}
+
+	if err := s.SetQuorum(st.Quorum); err != nil {
+		return err
+	}
+
+	if err := s.SetSecretBallot(st.SecretBallot); err != nil {
+		return err
+	}
+
+	if err := s.SetLiveResults(st.LiveResults); err != nil {
+		return err
+	}
+
+	return s.SetResultsVisibility(st.ResultsVisibility)
+}
+
+// Questions gives the questions context a way to read the questions and choices of a session
+type Questions interface {
+	// SessionEntries returns the questions of the session by order_num, each with its choices
+	SessionEntries(ctx context.Context, sessionID uuid.UUID) ([]document.Entry, error)
+}
+
+// CloneOptions tell what a clone keeps of its source, the settings are always copied
+type CloneOptions struct {
+	Title        string // title of the source when empty
+	Participants bool   // copies the participants with their role and weight
+	Template     bool   // saves the clone as a template
+}
+
+type Service struct {
+	sessions  session.Repository
+	documents document.Repository
+	questions Questions
+	clock     clock.Clock
+}
+
+// NewService : clk decides the state of the sessions, it is the clock of the scheduler
+func NewService(sessionRepository session.Repository, documentRepository document.Repository, questions Questions, clk clock.Clock) *Service {
+	if sessionRepository == nil {
+		panic("missing session repository")
+	}
+
+	if documentRepository == nil {
+		panic("missing document repository")
+	}
+
+	if questions == nil {
+		panic("missing questions")
+	}
+
+	if clk == nil {
+		panic("missing clock")
+	}
+
+	return &Service{
+		sessions:  sessionRepository,
+		documents: documentRepository,
+		questions: questions,
+		clock:     clk,
+	}
+}
+
+// organizedSession loads the session, organizerID must be one of its organizers
+func organizedSession(ctx context.Context, sessions session.Repository, sessionID, organizerID uuid.UUID) (*session.Session, error) {
+	vs, err := sessions.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	organizer, err := sessions.IsOrganizer(ctx, sessionID, organizerID)
+	if err != nil {
+		return nil, fmt.Errorf("is organizer: %w", err)
+	}
+
+	if !organizer {
+		return nil, ErrNotOrganizer
+	}
+
+	return vs, nil
+}
+
+// sessions
+
+// CreateSession creates the session with organizerID as its first organizer
+func (s *Service) CreateSession(ctx context.Context, organizerID uuid.UUID, settings Settings) (*session.Session, error) {
+	vs, err := session.NewSessionNoEnd(settings.Title, settings.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := settings.apply(vs); err != nil {
+		return nil, err
+	}
+
+	// without its organizer, nobody could change or delete the session
+	if err := s.sessions.CreateOrganizedVoteSession(ctx, vs, organizerID); err != nil {
+		return nil, err
+	}
+
+	return vs, nil
+}
+
+func (s *Service) GetSession(ctx context.Context, sessionID uuid.UUID) (*session.Session, error) {
+	return s.sessions.GetVoteSessionByID(ctx, sessionID)
+}
+
+func (s *Service) ListSessions(ctx context.Context, limit, offset int) ([]*session.Session, error) {
+	return s.sessions.ListVoteSessions(ctx, limit, offset)
+}
+
+func (s *Service) ListTemplates(ctx context.Context, limit, offset int) ([]*session.Session, error) {
+	return s.sessions.ListVoteSessionTemplates(ctx, limit, offset)
+}
+
+// CloneSession copies the session, its questions and choices into a new draft, or template,
+// organized by organizerID. Cloning a template is how a session is created from it.
+// The clone is written in one transaction: a clone which fails halfway leaves nothing.
+func (s *Service) CloneSession(ctx context.Context, organizerID, sessionID uuid.UUID, opts CloneOptions) (*session.Session, error) {
+	src, err := organizedSession(ctx, s.sessions, sessionID, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.questions.SessionEntries(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("read questions: %w", err)
+	}
+
+	members, err := s.cloneMembers(ctx, sessionID, organizerID, opts.Participants)
+	if err != nil {
+		return nil, fmt.Errorf("read participants: %w", err)
+	}
+
+	clone := src.Clone(opts.Title, opts.Template)
+
+	for i := range entries {
+		entries[i].Question = entries[i].Question.CopyTo(clone.ID())
+	}
+
+	if err := s.documents.CloneSession(ctx, clone, members, entries); err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
+
+// cloneMembers : organizerID organizes the clone, with participants the members of the source
+// keep their role and weight
+func (s *Service) cloneMembers(ctx context.Context, sessionID, organizerID uuid.UUID, participants bool) ([]session.Member, error) {
+	var members []session.Member
+
+	if participants {
+		userIDs, err := s.sessions.GetParticipants(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, userID := range userIDs {
+			organizer, err := s.sessions.IsOrganizer(ctx, sessionID, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			weight, err := s.sessions.GetWeight(ctx, sessionID, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			role := session.RoleParticipant
+			if organizer {
+				role = session.RoleOrganizer
+			}
+
+			members = append(members, session.Member{UserID: userID, Role: role, Weight: weight})
+		}
+	}
+
+	// promotes organizerID when it was copied as a participant
+	for i := range members {
+		if members[i].UserID == organizerID {
+			members[i].Role = session.RoleOrganizer
+			return members, nil
+		}
+	}
+
+	return append(members, session.Member{UserID: organizerID, Role: session.RoleOrganizer, Weight: session.DefaultWeight}), nil
+}
+
+// UpdateSession replaces the settings of the session: the quorum, secret ballot and
+// results settings only change before it opens, the end date of a finalized session never.
+// A session whose participants are weighted cannot become a secret ballot.
+func (s *Service) UpdateSession(ctx context.Context, organizerID, sessionID uuid.UUID, settings Settings) (*session.Session, error) {
+	vs, err := organizedSession(ctx, s.sessions, sessionID, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.SecretBallot && !vs.SecretBallot() {
+		if err := s.checkUnweighted(ctx, sessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := settings.apply(vs); err != nil {
+		return nil, err
+	}
+
+	if err := s.sessions.UpdateVoteSession(ctx, vs); err != nil {
+		return nil, err
+	}
+
+	return vs, nil
+}
+
+// ChangeState moves the session to next, the transition is checked by the Session
+func (s *Service) ChangeState(ctx context.Context, organizerID, sessionID uuid.UUID, next session.State) (*session.Session, error) {
+	vs, err := organizedSession(ctx, s.sessions, sessionID, organizerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+
+	if err := vs.ChangeState(next, now); err != nil {
+		return nil, err
+	}
+
+	if next == session.StateClosed {
+		err = s.sessions.CloseVoteSession(ctx, sessionID, now)
+	} else {
+		err = s.sessions.SetVoteSessionState(ctx, sessionID, next)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return vs, nil
+}
+
+// CloseSession ends the session now
+func (s *Service) CloseSession(ctx context.Context, organizerID, sessionID uuid.UUID) error {
+	_, err := s.ChangeState(ctx, organizerID, sessionID, session.StateClosed)
+	return err
+}
+
+// DeleteSession : a finalized session is kept with its result
+func (s *Service) DeleteSession(ctx context.Context, organizerID, sessionID uuid.UUID) error {
+	if _, err := organizedSession(ctx, s.sessions, sessionID, organizerID); err != nil {
+		return err
+	}
+
+	return s.sessions.DeleteVoteSession(ctx, sessionID)
+}
+
+// checkUnweighted : every participant has the default weight
+func (s *Service) checkUnweighted(ctx context.Context, sessionID uuid.UUID) error {
+	participants, err := s.sessions.GetParticipants(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	weight, err := s.sessions.WeighParticipants(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if weight != len(participants)*session.DefaultWeight {
+		return session.ErrWeightedSecret
+	}
+
+	return nil
+}
+
+// participants
+
+// AddParticipant adds userID to the session, its ballots count weight times
+func (s *Service) AddParticipant(ctx context.Context, organizerID, sessionID, userID uuid.UUID, weight int) error {
+	if err := session.CheckWeight(weight); err != nil {
+		return err
+	}
+
+	vs, err := organizedSession(ctx, s.sessions, sessionID, organizerID)
+	if err != nil {
+		return err
+	}
+
+	if err := vs.CheckParticipantWeight(weight); err != nil {
+		return err
+	}
+
+	return addParticipant(ctx, s.sessions, sessionID, userID, weight)
+}
+
+func addParticipant(ctx context.Context, sessions session.Repository, sessionID, userID uuid.UUID, weight int) error {
+	if err := sessions.AddParticipant(ctx, sessionID, userID); err != nil {
+		return err
+	}
+
+	if weight == session.DefaultWeight {
+		return nil
+	}
+
+	return sessions.SetWeight(ctx, sessionID, userID, weight)
+}
+
+func (s *Service) RemoveParticipant(ctx context.Context, organizerID, sessionID, userID uuid.UUID) error {
+	if _, err := organizedSession(ctx, s.sessions, sessionID, organizerID); err != nil {
+		return err
+	}
+
+	return s.sessions.RemoveParticipant(ctx, sessionID, userID)
+}
+
+func (s *Service) ListParticipants(ctx context.Context, sessionID uuid.UUID) (uuid.UUIDs, error) {
+	if _, err := s.sessions.GetVoteSessionByID(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	return s.sessions.GetParticipants(ctx, sessionID)
+}
@@ -0,0 +1,316 @@
+package app_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	qadapters "github.com/73NN0/voting-app/internal/questions/adapters"
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
+	"github.com/73NN0/voting-app/internal/sessions/adapters"
+	"github.com/73NN0/voting-app/internal/sessions/app"
+	"github.com/73NN0/voting-app/internal/sessions/domain/document"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	users "github.com/73NN0/voting-app/internal/users/adapters"
+	"github.com/73NN0/voting-app/internal/users/domain/user"
+	"github.com/google/uuid"
+)
+
+type fakeQuestions struct {
+	err     error
+	entries []document.Entry // of every session
+}
+
+func (q *fakeQuestions) SessionEntries(context.Context, uuid.UUID) ([]document.Entry, error) {
+	return q.entries, q.err
+}
+
+// newService builds the service on database, the questions of the sessions are read from questions
+func newService(database *sql.DB, questions app.Questions, clk clock.Clock) *app.Service {
+	return app.NewService(
+		adapters.NewSqliteSessionRepository(database),
+		adapters.NewSqliteDocumentsRepository(database, qadapters.NewSqliteQuestionsRepository(database), qadapters.NewSqliteChoicesRepositoy(database)),
+		questions,
+		clk,
+	)
+}
+
+// oneQuestion is the entry of a question with one choice
+func oneQuestion(t *testing.T, sessionID uuid.UUID) document.Entry {
+	t.Helper()
+
+	q, err := question.NewQuestion(sessionID, "Oui ?", 1, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return document.Entry{Question: q, Choices: []choice.Choice{choice.NewChoice(0, 1, "Oui")}}
+}
+
+func countRows(t *testing.T, database *sql.DB, query string, args ...any) int {
+	t.Helper()
+
+	var n int
+	if err := database.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+
+	return n
+}
+
+func TestService_CloneSession(t *testing.T) {
+	ctx := context.Background()
+	database := newDatabase(t)
+	repo := adapters.NewSqliteSessionRepository(database)
+	questions := &fakeQuestions{}
+	service := newService(database, questions, clock.System{})
+
+	// GIVEN une session avec un organisateur, un co-organisateur et un participant pondéré
+	userRepo := users.NewSqliteUserRepository(database)
+	var ids []uuid.UUID
+	for _, name := range []string{"alice", "bob", "carol"} {
+		u, err := user.NewUser(name, name+"@example.org")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := userRepo.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, u.ID())
+	}
+	organizer, coOrganizer, participant := ids[0], ids[1], ids[2]
+	src, err := service.CreateSession(ctx, organizer, app.Settings{Title: "AG 2026", ResultsVisibility: session.VisibilityPublic, Quorum: session.NoQuorum()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddOrganizer(ctx, src.ID(), coOrganizer); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.AddParticipant(ctx, organizer, src.ID(), participant, 3); err != nil {
+		t.Fatal(err)
+	}
+	questions.entries = []document.Entry{oneQuestion(t, src.ID())}
+
+	// WHEN un non-organisateur la clone
+	// THEN c'est refusé
+	if _, err := service.CloneSession(ctx, participant, src.ID(), app.CloneOptions{}); !errors.Is(err, app.ErrNotOrganizer) {
+		t.Fatalf("expected %v, got %v", app.ErrNotOrganizer, err)
+	}
+
+	// WHEN l'organisateur la clone avec ses participants
+	clone, err := service.CloneSession(ctx, organizer, src.ID(), app.CloneOptions{Title: "AG 2027", Participants: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN les questions sont copiées, et les participants avec leur rôle et leur poids
+	if n := countRows(t, database, `SELECT COUNT(*) FROM question WHERE session_id = ?`, clone.ID().String()); n != 1 {
+		t.Fatalf("questions of the clone = %d, want 1", n)
+	}
+	if n := countRows(t, database, `SELECT COUNT(*) FROM choice c JOIN question q ON q.id = c.question_id WHERE q.session_id = ?`, clone.ID().String()); n != 1 {
+		t.Fatalf("choices of the clone = %d, want 1", n)
+	}
+
+	for _, tt := range []struct {
+		userID        uuid.UUID
+		wantOrganizer bool
+		wantWeight    int
+	}{
+		{organizer, true, 1},
+		{coOrganizer, true, 1},
+		{participant, false, 3},
+	} {
+		isOrganizer, err := repo.IsOrganizer(ctx, clone.ID(), tt.userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		weight, err := repo.GetWeight(ctx, clone.ID(), tt.userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isOrganizer != tt.wantOrganizer || weight != tt.wantWeight {
+			t.Errorf("%s: organizer = %v, weight = %d, want %v, %d", tt.userID, isOrganizer, weight, tt.wantOrganizer, tt.wantWeight)
+		}
+	}
+}
+
+func TestService_ChangeStateReadsTheClock(t *testing.T) {
+	ctx := context.Background()
+	database := newDatabase(t)
+	clk := &fakeClock{now: time.Date(2030, 3, 1, 18, 0, 0, 0, time.UTC)}
+	service := newService(database, &fakeQuestions{}, clk)
+
+	// GIVEN une session ouverte sans date de fin
+	organizer, err := user.NewUser("alice", "alice@example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.NewSqliteUserRepository(database).CreateUser(ctx, organizer); err != nil {
+		t.Fatal(err)
+	}
+	vs, err := service.CreateSession(ctx, organizer.ID(), app.Settings{Title: "AG", ResultsVisibility: session.VisibilityPublic, Quorum: session.NoQuorum()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ChangeState(ctx, organizer.ID(), vs.ID(), session.StateOpen); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN l'organisateur la ferme
+	if err := service.CloseSession(ctx, organizer.ID(), vs.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN elle se termine à l'heure de l'horloge, pas à celle du système
+	got, err := adapters.NewSqliteSessionRepository(database).GetVoteSessionByID(ctx, vs.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endsAt, ok := got.EndsAt(); !ok || !endsAt.Equal(clk.now) {
+		t.Errorf("ends_at = %v, want %v", endsAt, clk.now)
+	}
+	if got.StateAt(clk.now) != session.StateClosed {
+		t.Errorf("state = %s, want %s", got.StateAt(clk.now), session.StateClosed)
+	}
+}
+
+func TestService_CreateSessionIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	database := newDatabase(t)
+	service := newService(database, &fakeQuestions{}, clock.System{})
+
+	// GIVEN une base qui refuse l'organisateur
+	if _, err := database.Exec(`
+		CREATE TRIGGER refuse_participant BEFORE INSERT ON session_and_participant
+		BEGIN
+			SELECT RAISE(ABORT, 'participant refused');
+		END
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on crée une session
+	if _, err := service.CreateSession(ctx, uuid.New(), app.Settings{Title: "AG", ResultsVisibility: session.VisibilityPublic, Quorum: session.NoQuorum()}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// THEN aucune session sans organisateur ne reste
+	sessions, err := service.ListSessions(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("sessions = %d, want 0", len(sessions))
+	}
+}
+
+func TestService_CloneSessionFailed(t *testing.T) {
+	ctx := context.Background()
+	service := newService(newDatabase(t), &fakeQuestions{err: errors.New("question copy failed")}, clock.System{})
+
+	// GIVEN une session
+	organizer := uuid.New()
+	src, err := service.CreateSession(ctx, organizer, app.Settings{Title: "AG", ResultsVisibility: session.VisibilityPublic, Quorum: session.NoQuorum()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN la copie des questions échoue pendant l'enregistrement d'un modèle
+	if _, err := service.CloneSession(ctx, organizer, src.ID(), app.CloneOptions{Template: true}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// THEN le modèle à moitié copié est supprimé
+	templates, err := service.ListTemplates(ctx, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 0 {
+		t.Errorf("templates = %d, want 0", len(templates))
+	}
+}
+
+func TestService_CloneSessionIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	database := newDatabase(t)
+	questions := &fakeQuestions{}
+	service := newService(database, questions, clock.System{})
+
+	// GIVEN une session avec une question
+	organizer := uuid.New()
+	src, err := service.CreateSession(ctx, organizer, app.Settings{Title: "AG", ResultsVisibility: session.VisibilityPublic, Quorum: session.NoQuorum()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions.entries = []document.Entry{oneQuestion(t, src.ID())}
+
+	// AND une base qui refuse les membres de toute autre session, après la copie des questions
+	if _, err := database.Exec(`
+		CREATE TRIGGER refuse_clone_members BEFORE INSERT ON session_and_participant
+		WHEN NEW.session_id != '` + src.ID().String() + `'
+		BEGIN
+			SELECT RAISE(ABORT, 'member refused');
+		END
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on la clone
+	if _, err := service.CloneSession(ctx, organizer, src.ID(), app.CloneOptions{}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// THEN ni le clone ni ses questions et ses choix ne restent
+	if n := countRows(t, database, `SELECT COUNT(*) FROM vote_session`); n != 1 {
+		t.Errorf("sessions = %d, want 1", n)
+	}
+	if n := countRows(t, database, `SELECT COUNT(*) FROM question`); n != 0 {
+		t.Errorf("questions = %d, want 0", n)
+	}
+	if n := countRows(t, database, `SELECT COUNT(*) FROM choice`); n != 0 {
+		t.Errorf("choices = %d, want 0", n)
+	}
+}
+
+func TestService_SecretBallotIsNotWeighted(t *testing.T) {
+	ctx := context.Background()
+	service := newService(newDatabase(t), &fakeQuestions{}, clock.System{})
+	organizer := uuid.New()
+
+	settings := app.Settings{Title: "AG", ResultsVisibility: session.VisibilityPublic, Quorum: session.NoQuorum()}
+	secret := settings
+	secret.SecretBallot = true
+
+	// GIVEN un scrutin secret et une session dont un participant est pondéré
+	vs, err := service.CreateSession(ctx, organizer, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weighted, err := service.CreateSession(ctx, organizer, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.AddParticipant(ctx, organizer, weighted.ID(), uuid.New(), 3); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on pondère un participant du scrutin secret
+	err = service.AddParticipant(ctx, organizer, vs.ID(), uuid.New(), 3)
+
+	// THEN c'est refusé, sans pondération c'est accepté
+	if !errors.Is(err, session.ErrWeightedSecret) {
+		t.Errorf("AddParticipant() err = %v, want %v", err, session.ErrWeightedSecret)
+	}
+	if err := service.AddParticipant(ctx, organizer, vs.ID(), uuid.New(), session.DefaultWeight); err != nil {
+		t.Errorf("AddParticipant() err = %v", err)
+	}
+
+	// AND la session pondérée ne peut pas devenir secrète
+	if _, err := service.UpdateSession(ctx, organizer, weighted.ID(), secret); !errors.Is(err, session.ErrWeightedSecret) {
+		t.Errorf("UpdateSession() err = %v, want %v", err, session.ErrWeightedSecret)
+	}
+}
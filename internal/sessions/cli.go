@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/73NN0/voting-app/internal/common/db"
+	questions "github.com/73NN0/voting-app/internal/questions/adapters"
+	"github.com/73NN0/voting-app/internal/sessions/adapters"
+	"github.com/73NN0/voting-app/internal/sessions/app"
+)
+
+// openDocuments gives the subcommands the documents of the database at dsn, close it with cleanup
+func openDocuments(dsn string) (*app.DocumentService, func(), error) {
+	database, cleanup, err := db.OpenSQLite(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = db.InitializeSchemas(database); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	questionRepo := questions.NewSqliteQuestionsRepository(database)
+	choiceRepo := questions.NewSqliteChoicesRepositoy(database)
+
+	documents := app.NewDocumentService(
+		adapters.NewSqliteSessionRepository(database),
+		adapters.NewSqliteDocumentsRepository(database, questionRepo, choiceRepo),
+		adapters.NewQuestionsInProcess(questionRepo, choiceRepo),
+	)
+
+	return documents, cleanup, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/73NN0/voting-app/internal/sessions/domain/document"
+	"github.com/google/uuid"
+)
+
+// runImport : sessions import -organizer <id> [-format json|yaml] [file]
+// The document is read on stdin without file, its format is guessed from the extension of the file.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dsn := fs.String("dsn", "voting.db", "sqlite data source name")
+	organizerStr := fs.String("organizer", "", "id of the user organizing the imported session")
+	formatStr := fs.String("format", "", "json or yaml, from the file extension when empty")
+	fs.Parse(args)
+
+	organizerID, err := uuid.Parse(*organizerStr)
+	if err != nil {
+		return fmt.Errorf("invalid organizer id %q", *organizerStr)
+	}
+
+	var r io.Reader = os.Stdin
+	if file := fs.Arg(0); file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+
+		if *formatStr == "" {
+			*formatStr = strings.TrimPrefix(filepath.Ext(file), ".")
+		}
+	}
+
+	if *formatStr == "" {
+		return errors.New("-format is needed to read stdin")
+	}
+
+	format, err := document.ParseFormat(*formatStr)
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	doc, err := document.Parse(data, format)
+	if err != nil {
+		return err
+	}
+
+	documents, cleanup, err := openDocuments(*dsn)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	s, err := documents.ImportSession(context.Background(), organizerID, doc)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(s.ID())
+	return nil
+}
+
+// runExport : sessions export -session <id> [-format json|yaml] [-o file]
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dsn := fs.String("dsn", "voting.db", "sqlite data source name")
+	sessionStr := fs.String("session", "", "id of the vote session")
+	formatStr := fs.String("format", "yaml", "json or yaml")
+	out := fs.String("o", "", "output file, stdout when empty")
+	fs.Parse(args)
+
+	sessionID, err := uuid.Parse(*sessionStr)
+	if err != nil {
+		return fmt.Errorf("invalid session id %q", *sessionStr)
+	}
+
+	format, err := document.ParseFormat(*formatStr)
+	if err != nil {
+		return err
+	}
+
+	documents, cleanup, err := openDocuments(*dsn)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	doc, err := documents.ExportSession(context.Background(), sessionID)
+	if err != nil {
+		return err
+	}
+
+	data, err := document.Encode(doc, format)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(*out, data, 0o644)
+}
@@ -0,0 +1,326 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// A document describes a whole session, its settings, questions and choices, in JSON or YAML,
+// so a session can be written by hand, kept in git and imported again.
+// It carries neither ids nor participants nor votes: importing it always creates a new draft.
+
+// Version of the document format, bumped when Document changes
+const Version = 1
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+var (
+	ErrUnknownFormat      = errors.New("unknown document format")
+	ErrUnsupportedVersion = errors.New("unsupported document version")
+	ErrNoQuestion         = errors.New("document has no question")
+	ErrDuplicateOrder     = errors.New("two entries of the document have the same order_num")
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatJSON, FormatYAML:
+		return f, nil
+	case "yml":
+		return FormatYAML, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// ContentType of the downloaded document
+func (f Format) ContentType() string {
+	if f == FormatYAML {
+		return "application/yaml"
+	}
+	return "application/json"
+}
+
+type Document struct {
+	Version           int        `json:"version" yaml:"version"` // Version when empty
+	Title             string     `json:"title" yaml:"title"`
+	Description       string     `json:"description,omitempty" yaml:"description,omitempty"`
+	StartsAt          *time.Time `json:"starts_at,omitempty" yaml:"starts_at,omitempty"`
+	EndsAt            *time.Time `json:"ends_at,omitempty" yaml:"ends_at,omitempty"`
+	Quorum            *Quorum    `json:"quorum,omitempty" yaml:"quorum,omitempty"` // none when empty
+	SecretBallot      bool       `json:"secret_ballot,omitempty" yaml:"secret_ballot,omitempty"`
+	LiveResults       bool       `json:"live_results,omitempty" yaml:"live_results,omitempty"`
+	ResultsVisibility string     `json:"results_visibility,omitempty" yaml:"results_visibility,omitempty"` // public when empty
+	Questions         []Question `json:"questions" yaml:"questions"`
+}
+
+type Quorum struct {
+	Kind  string `json:"kind" yaml:"kind"`
+	Value int    `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Question : the fields left empty take the defaults of question.NewQuestionWithRules
+type Question struct {
+	OrderNum      int      `json:"order_num" yaml:"order_num"`
+	Text          string   `json:"text" yaml:"text"`
+	Kind          string   `json:"kind,omitempty" yaml:"kind,omitempty"` // choice when empty
+	TallyMethod   string   `json:"tally_method,omitempty" yaml:"tally_method,omitempty"`
+	MaxChoices    int      `json:"max_choices,omitempty" yaml:"max_choices,omitempty"`
+	AllowMultiple bool     `json:"allow_multiple,omitempty" yaml:"allow_multiple,omitempty"`
+	MaxPoints     int      `json:"max_points,omitempty" yaml:"max_points,omitempty"`
+	Seats         int      `json:"seats,omitempty" yaml:"seats,omitempty"`
+	Threshold     string   `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	TieBreak      string   `json:"tie_break,omitempty" yaml:"tie_break,omitempty"`
+	Choices       []Choice `json:"choices" yaml:"choices"`
+}
+
+type Choice struct {
+	OrderNum int    `json:"order_num" yaml:"order_num"`
+	Text     string `json:"text" yaml:"text"`
+}
+
+// Entry is a question of the document and its choices, checked and ready to be stored.
+// The choices get the id of the question once it is stored.
+type Entry struct {
+	Question question.Question
+	Choices  []choice.Choice
+}
+
+// Parse reads a document, unknown fields are refused so that a typo is not silently ignored
+func Parse(data []byte, format Format) (Document, error) {
+	var doc Document
+	var err error
+
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&doc)
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&doc)
+	default:
+		return Document{}, ErrUnknownFormat
+	}
+
+	if err != nil {
+		return Document{}, fmt.Errorf("invalid %s document: %w", format, err)
+	}
+
+	if doc.Version == 0 {
+		doc.Version = Version
+	}
+
+	if doc.Version != Version {
+		return Document{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, doc.Version)
+	}
+
+	return doc, nil
+}
+
+// Encode writes the document the same way every time, so that two exports diff cleanly in git
+func Encode(doc Document, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// Build checks the document with the rules of the domain and gives the new draft session and its entries
+func (d Document) Build() (*session.Session, []Entry, error) {
+	vs, err := session.NewSessionNoEnd(d.Title, d.Description)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if d.EndsAt != nil {
+		vs.SetEndDate(d.EndsAt.UTC())
+	}
+
+	if d.StartsAt != nil {
+		if err := vs.SetStartDate(d.StartsAt.UTC()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if d.Quorum != nil {
+		if err := vs.SetQuorum(session.Quorum{Kind: session.QuorumKind(d.Quorum.Kind), Value: d.Quorum.Value}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := vs.SetSecretBallot(d.SecretBallot); err != nil {
+		return nil, nil, err
+	}
+
+	if err := vs.SetLiveResults(d.LiveResults); err != nil {
+		return nil, nil, err
+	}
+
+	if d.ResultsVisibility != "" {
+		if err := vs.SetResultsVisibility(session.Visibility(d.ResultsVisibility)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(d.Questions) == 0 {
+		return nil, nil, ErrNoQuestion
+	}
+
+	entries := make([]Entry, 0, len(d.Questions))
+	questionOrders := make(map[int]bool)
+
+	for _, q := range d.Questions {
+		if questionOrders[q.OrderNum] {
+			return nil, nil, fmt.Errorf("%w: question %d", ErrDuplicateOrder, q.OrderNum)
+		}
+		questionOrders[q.OrderNum] = true
+
+		entry, err := q.entry(vs.ID())
+		if err != nil {
+			return nil, nil, fmt.Errorf("question %d: %w", q.OrderNum, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return vs, entries, nil
+}
+
+func (q Question) entry(sessionID uuid.UUID) (Entry, error) {
+	kind := question.Kind(q.Kind)
+	if kind == "" {
+		kind = question.KindChoice
+	}
+
+	built, err := question.NewQuestionWithRules(sessionID, q.Text, q.OrderNum, question.Rules{
+		Kind:          kind,
+		Method:        question.Method(q.TallyMethod),
+		MaxChoices:    q.MaxChoices,
+		AllowMultiple: q.AllowMultiple,
+		MaxPoints:     q.MaxPoints,
+		Seats:         q.Seats,
+		Threshold:     question.Threshold(q.Threshold),
+		TieBreak:      question.TieBreak(q.TieBreak),
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+
+	choices := make([]choice.Choice, 0, len(q.Choices))
+	choiceOrders := make(map[int]bool)
+
+	for _, c := range q.Choices {
+		switch {
+		case c.Text == "":
+			return Entry{}, choice.ErrEmptyChoiceText
+		case c.OrderNum < 1:
+			return Entry{}, choice.ErrInvalidChoiceOrder
+		case choiceOrders[c.OrderNum]:
+			return Entry{}, fmt.Errorf("%w: choice %d", ErrDuplicateOrder, c.OrderNum)
+		}
+		choiceOrders[c.OrderNum] = true
+
+		// the question id is set once the question is stored
+		choices = append(choices, choice.NewChoice(0, c.OrderNum, c.Text))
+	}
+
+	return Entry{Question: built, Choices: choices}, nil
+}
+
+// FromSession writes the session back as a document, without what the format does not carry
+func FromSession(vs *session.Session, entries []Entry) Document {
+	doc := Document{
+		Version:      Version,
+		Title:        vs.Title(),
+		Description:  vs.Description(),
+		SecretBallot: vs.SecretBallot(),
+		LiveResults:  vs.LiveResults(),
+		Questions:    make([]Question, 0, len(entries)),
+	}
+
+	if startsAt, ok := vs.StartsAt(); ok {
+		doc.StartsAt = &startsAt
+	}
+
+	if endsAt, ok := vs.EndsAt(); ok {
+		doc.EndsAt = &endsAt
+	}
+
+	if q := vs.Quorum(); q.Kind != session.QuorumNone {
+		doc.Quorum = &Quorum{Kind: string(q.Kind), Value: q.Value}
+	}
+
+	if v := vs.ResultsVisibility(); v != session.VisibilityPublic {
+		doc.ResultsVisibility = string(v)
+	}
+
+	for _, e := range entries {
+		doc.Questions = append(doc.Questions, questionFrom(e))
+	}
+
+	return doc
+}
+
+// questionFrom leaves out the rules which are the defaults of the kind
+func questionFrom(e Entry) Question {
+	q := e.Question
+
+	out := Question{
+		OrderNum:      q.OrderNum(),
+		Text:          q.Text(),
+		Kind:          string(q.Kind()),
+		MaxChoices:    q.MaxChoices(),
+		AllowMultiple: q.AllowMultiple(),
+		MaxPoints:     q.MaxPoints(),
+		Choices:       make([]Choice, 0, len(e.Choices)),
+	}
+
+	if q.Method() != q.Kind().DefaultMethod() {
+		out.TallyMethod = string(q.Method())
+	}
+	if q.Seats() > 1 {
+		out.Seats = q.Seats()
+	}
+	if q.Threshold() != question.ThresholdNone {
+		out.Threshold = string(q.Threshold())
+	}
+	if q.TieBreak() != question.TieBreakNone {
+		out.TieBreak = string(q.TieBreak())
+	}
+
+	for _, c := range e.Choices {
+		out.Choices = append(out.Choices, Choice{OrderNum: c.OrderNum(), Text: c.Text()})
+	}
+
+	return out
+}
@@ -0,0 +1,166 @@
+package document_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
+	"github.com/73NN0/voting-app/internal/sessions/domain/document"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+)
+
+const budget = `title: Budget 2027
+description: vote du budget
+ends_at: 2027-06-30T18:00:00Z
+quorum:
+  kind: percent
+  value: 50
+secret_ballot: true
+questions:
+  - order_num: 1
+    text: Approuver le budget ?
+    max_choices: 1
+    choices:
+      - order_num: 1
+        text: Pour
+      - order_num: 2
+        text: Contre
+  - order_num: 2
+    text: Élire le bureau
+    kind: ranked
+    tally_method: stv
+    max_choices: 3
+    seats: 2
+    choices:
+      - order_num: 1
+        text: Alice
+      - order_num: 2
+        text: Bob
+      - order_num: 3
+        text: Carol
+`
+
+func TestDocument_RoundTrip(t *testing.T) {
+	for _, format := range []document.Format{document.FormatYAML, document.FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			// GIVEN l'export, dans le format testé, de la session d'un document YAML
+			doc, err := document.Parse([]byte(budget), document.FormatYAML)
+			if err != nil {
+				t.Fatal(err)
+			}
+			src, srcEntries, err := doc.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := document.Encode(document.FromSession(src, srcEntries), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// WHEN on le relit et on construit la session
+			parsed, err := document.Parse(data, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vs, entries, err := parsed.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// THEN la session est un brouillon avec les réglages du document
+			if vs.State() != session.StateDraft || vs.Title() != "Budget 2027" || !vs.SecretBallot() {
+				t.Errorf("session = %s %q secret=%v", vs.State(), vs.Title(), vs.SecretBallot())
+			}
+			if q := vs.Quorum(); q.Kind != session.QuorumPercent || q.Value != 50 {
+				t.Errorf("quorum = %+v", q)
+			}
+			if len(entries) != 2 || len(entries[1].Choices) != 3 {
+				t.Fatalf("entries = %+v", entries)
+			}
+			if q := entries[1].Question; q.Kind() != question.KindRanked || q.Seats() != 2 || q.SessionID() != vs.ID() {
+				t.Errorf("question 2 = %s seats=%d", q.Kind(), q.Seats())
+			}
+
+			// AND l'exporter à nouveau redonne le même fichier
+			again, err := document.Encode(document.FromSession(vs, entries), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(again, data) {
+				t.Errorf("export differs from import:\n%s\nwant\n%s", again, data)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		format  document.Format
+		wantErr error
+	}{
+		{"version par défaut", `{"title":"x","questions":[]}`, document.FormatJSON, nil},
+		{"version inconnue", `{"version":2,"title":"x"}`, document.FormatJSON, document.ErrUnsupportedVersion},
+		{"format inconnu", `title: x`, document.Format("toml"), document.ErrUnknownFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN on lit le document
+			doc, err := document.Parse([]byte(tt.data), tt.format)
+
+			// THEN
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && doc.Version != document.Version {
+				t.Errorf("version = %d, want %d", doc.Version, document.Version)
+			}
+		})
+	}
+
+	// THEN une faute de frappe dans un champ est refusée, en JSON comme en YAML
+	if _, err := document.Parse([]byte(`{"title":"x","titel":"y"}`), document.FormatJSON); err == nil {
+		t.Error("unknown JSON field accepted")
+	}
+	if _, err := document.Parse([]byte("title: x\nquestion: []\n"), document.FormatYAML); err == nil {
+		t.Error("unknown YAML field accepted")
+	}
+}
+
+func TestDocument_Build(t *testing.T) {
+	one := func(order int, choices ...document.Choice) document.Question {
+		return document.Question{OrderNum: order, Text: "Oui ?", MaxChoices: 1, Choices: choices}
+	}
+	yes := document.Choice{OrderNum: 1, Text: "Oui"}
+
+	tests := []struct {
+		name    string
+		doc     document.Document
+		wantErr error
+	}{
+		{"happy path", document.Document{Title: "AG", Questions: []document.Question{one(1, yes)}}, nil},
+		{"sans titre", document.Document{Questions: []document.Question{one(1, yes)}}, session.ErrEmptyTitle},
+		{"sans question", document.Document{Title: "AG"}, document.ErrNoQuestion},
+		{"questions de même ordre", document.Document{Title: "AG", Questions: []document.Question{one(1, yes), one(1, yes)}}, document.ErrDuplicateOrder},
+		{"choix de même ordre", document.Document{Title: "AG", Questions: []document.Question{one(1, yes, yes)}}, document.ErrDuplicateOrder},
+		{"choix vide", document.Document{Title: "AG", Questions: []document.Question{one(1, document.Choice{OrderNum: 1})}}, choice.ErrEmptyChoiceText},
+		{"type inconnu", document.Document{Title: "AG", Questions: []document.Question{{OrderNum: 1, Text: "?", Kind: "poll"}}}, question.ErrInvalidKind},
+		{"visibilité inconnue", document.Document{Title: "AG", ResultsVisibility: "everyone", Questions: []document.Question{one(1, yes)}}, session.ErrInvalidVisibility},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN on construit la session du document
+			_, _, err := tt.doc.Build()
+
+			// THEN
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Build() err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
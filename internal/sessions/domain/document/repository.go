@@ -0,0 +1,18 @@
+package document
+
+import (
+	"context"
+
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// ImportSession stores the session, its organizer, questions and choices in one transaction:
+	// a document is imported whole or not at all
+	ImportSession(context.Context, *session.Session, uuid.UUID /* organizer id */, []Entry) error
+
+	// CloneSession stores the clone, its questions and choices, and its members in one transaction:
+	// a clone is copied whole or not at all
+	CloneSession(context.Context, *session.Session, []session.Member, []Entry) error
+}
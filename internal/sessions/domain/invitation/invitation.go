@@ -0,0 +1,187 @@
+package invitation
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// An invitation asks someone, by email, to join a session as a participant.
+// It carries a single-use token: only its sha256 is stored, the token itself is given once,
+// when the invitation is created. Redeeming the token accepts or declines the invitation,
+// a pending invitation whose expiry date is passed reads as expired.
+
+// DefaultTTL is the lifetime of an invitation created without expiry date
+const DefaultTTL = 7 * 24 * time.Hour
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusAccepted Status = "accepted"
+	StatusDeclined Status = "declined"
+	// StatusExpired is never stored, see StatusAt
+	StatusExpired Status = "expired"
+)
+
+var (
+	ErrNotFound       = errors.New("invitation not found")
+	ErrInvalidEmail   = errors.New("invalid invitation email")
+	ErrInvalidStatus  = errors.New("invalid invitation status")
+	ErrExpiryPassed   = errors.New("invitation expiry date must be in the future")
+	ErrExpired        = errors.New("invitation is expired")
+	ErrAnswered       = errors.New("invitation is already answered")
+	ErrAlreadyInvited = errors.New("email already has a pending invitation to this session")
+)
+
+type Invitation struct {
+	id         uuid.UUID
+	sessionID  uuid.UUID
+	email      string
+	weight     int // weight of the participant once accepted
+	tokenHash  string
+	status     Status
+	userID     uuid.UUID // uuid.Nil until accepted
+	createdAt  time.Time
+	expiresAt  time.Time
+	answeredAt *time.Time // nullable
+}
+
+// Getters
+func (i *Invitation) ID() uuid.UUID        { return i.id }
+func (i *Invitation) SessionID() uuid.UUID { return i.sessionID }
+func (i *Invitation) Email() string        { return i.email }
+func (i *Invitation) Weight() int          { return i.weight }
+func (i *Invitation) TokenHash() string    { return i.tokenHash }
+func (i *Invitation) UserID() uuid.UUID    { return i.userID }
+func (i *Invitation) CreatedAt() time.Time { return i.createdAt }
+func (i *Invitation) ExpiresAt() time.Time { return i.expiresAt }
+
+// Status is the stored status, see StatusAt for the status at a given time
+func (i *Invitation) Status() Status { return i.status }
+
+func (i *Invitation) AnsweredAt() (time.Time, bool) {
+	if i.answeredAt == nil {
+		return time.Time{}, false
+	}
+	return *i.answeredAt, true
+}
+
+// StatusAt : a pending invitation expires at its expiry date
+func (i *Invitation) StatusAt(now time.Time) Status {
+	if i.status == StatusPending && !now.Before(i.expiresAt) {
+		return StatusExpired
+	}
+	return i.status
+}
+
+// New creates a pending invitation and its token, the token is not kept by the invitation
+func New(sessionID uuid.UUID, email string, weight int, expiresAt, now time.Time) (*Invitation, string, error) {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return nil, "", fmt.Errorf("%w: %s", ErrInvalidEmail, email)
+	}
+
+	if !expiresAt.After(now) {
+		return nil, "", ErrExpiryPassed
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", fmt.Errorf("draw token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return &Invitation{
+		id:        uuid.New(),
+		sessionID: sessionID,
+		email:     email,
+		weight:    weight,
+		tokenHash: Hash(token),
+		status:    StatusPending,
+		createdAt: now,
+		expiresAt: expiresAt,
+	}, token, nil
+}
+
+func Rehydrate(
+	id, sessionID uuid.UUID,
+	email string,
+	weight int,
+	tokenHash string,
+	status Status,
+	userID uuid.UUID,
+	createdAt, expiresAt time.Time,
+	answeredAt *time.Time,
+) (*Invitation, error) {
+	if _, err := ParseStatus(string(status)); err != nil || status == StatusExpired {
+		return nil, ErrInvalidStatus
+	}
+
+	return &Invitation{
+		id:         id,
+		sessionID:  sessionID,
+		email:      email,
+		weight:     weight,
+		tokenHash:  tokenHash,
+		status:     status,
+		userID:     userID,
+		createdAt:  createdAt,
+		expiresAt:  expiresAt,
+		answeredAt: answeredAt,
+	}, nil
+}
+
+func ParseStatus(s string) (Status, error) {
+	switch st := Status(s); st {
+	case StatusPending, StatusAccepted, StatusDeclined, StatusExpired:
+		return st, nil
+	}
+	return "", ErrInvalidStatus
+}
+
+// Hash is the hex sha256 of the token, the only form of the token kept in the database
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Accept links the invitation to userID
+func (i *Invitation) Accept(userID uuid.UUID, now time.Time) error {
+	if err := i.Answerable(now); err != nil {
+		return err
+	}
+
+	i.status = StatusAccepted
+	i.userID = userID
+	i.answeredAt = &now
+	return nil
+}
+
+func (i *Invitation) Decline(now time.Time) error {
+	if err := i.Answerable(now); err != nil {
+		return err
+	}
+
+	i.status = StatusDeclined
+	i.answeredAt = &now
+	return nil
+}
+
+// Answerable : a token is used once, before its expiry date
+func (i *Invitation) Answerable(now time.Time) error {
+	switch i.StatusAt(now) {
+	case StatusPending:
+		return nil
+	case StatusExpired:
+		return ErrExpired
+	}
+	return ErrAnswered
+}
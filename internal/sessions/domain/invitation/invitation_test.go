@@ -0,0 +1,101 @@
+package invitation_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/sessions/domain/invitation"
+	"github.com/google/uuid"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		email     string
+		expiresAt time.Time
+		wantErr   error
+	}{
+		{"happy path", "alice@example.org", now.Add(time.Hour), nil},
+		{"email invalide", "alice", now.Add(time.Hour), invitation.ErrInvalidEmail},
+		{"email avec nom", "Alice <alice@example.org>", now.Add(time.Hour), invitation.ErrInvalidEmail},
+		{"expiration passée", "alice@example.org", now, invitation.ErrExpiryPassed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN on crée l'invitation
+			inv, token, err := invitation.New(uuid.New(), tt.email, 1, tt.expiresAt, now)
+
+			// THEN
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("New() err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if token == "" || inv.TokenHash() != invitation.Hash(token) {
+				t.Errorf("token hash = %q, want the hash of the token", inv.TokenHash())
+			}
+			if inv.StatusAt(now) != invitation.StatusPending {
+				t.Errorf("status = %s, want %s", inv.StatusAt(now), invitation.StatusPending)
+			}
+		})
+	}
+}
+
+func TestInvitation_Answer(t *testing.T) {
+	now := time.Now().UTC()
+	userID := uuid.New()
+
+	accept := func(i *invitation.Invitation, at time.Time) error { return i.Accept(userID, at) }
+	decline := func(i *invitation.Invitation, at time.Time) error { return i.Decline(at) }
+
+	tests := []struct {
+		name       string
+		before     func(*invitation.Invitation, time.Time) error // answer given first, nil for none
+		answer     func(*invitation.Invitation, time.Time) error
+		at         time.Time
+		wantErr    error
+		wantStatus invitation.Status
+	}{
+		{"accepter", nil, accept, now, nil, invitation.StatusAccepted},
+		{"refuser", nil, decline, now, nil, invitation.StatusDeclined},
+		{"accepter deux fois", accept, accept, now, invitation.ErrAnswered, invitation.StatusAccepted},
+		{"accepter après refus", decline, accept, now, invitation.ErrAnswered, invitation.StatusDeclined},
+		{"accepter une fois expirée", nil, accept, now.Add(2 * time.Hour), invitation.ErrExpired, invitation.StatusExpired},
+		{"refuser une fois expirée", nil, decline, now.Add(2 * time.Hour), invitation.ErrExpired, invitation.StatusExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN une invitation valable une heure
+			inv, _, err := invitation.New(uuid.New(), "bob@example.org", 2, now.Add(time.Hour), now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.before != nil {
+				if err := tt.before(inv, now); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// WHEN on y répond
+			err = tt.answer(inv, tt.at)
+
+			// THEN
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("answer err = %v, want %v", err, tt.wantErr)
+			}
+			if got := inv.StatusAt(tt.at); got != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got, tt.wantStatus)
+			}
+			if tt.wantStatus == invitation.StatusAccepted && inv.UserID() != userID {
+				t.Errorf("user id = %s, want %s", inv.UserID(), userID)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package invitation
+
+import (
+	"context"
+
+	"github.com/73NN0/voting-app/internal/users/domain/user"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	CreateInvitation(context.Context, *Invitation) error
+
+	// GetInvitationByToken returns ErrNotFound when no invitation has this token hash
+	GetInvitationByToken(context.Context, string /* token hash */) (*Invitation, error)
+
+	ListInvitations(context.Context, uuid.UUID /* session id */) ([]*Invitation, error)
+
+	// AnswerInvitation stores the answer of a pending invitation,
+	// it returns ErrAnswered when the invitation was answered meanwhile
+	AnswerInvitation(context.Context, *Invitation) error
+
+	// AcceptInvitation stores the acceptance of a pending invitation and adds its user to the
+	// session with the weight of the invitation, in one transaction. newUser, when not nil, is
+	// that user, created in the same transaction. It returns ErrAnswered like AnswerInvitation,
+	// nothing is then stored; a user already participant keeps its weight.
+	AcceptInvitation(context.Context, *Invitation, *user.User /* new user, nil when it exists */) error
+}
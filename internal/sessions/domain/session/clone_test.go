@@ -0,0 +1,85 @@
+package session_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+)
+
+func TestSession_Clone(t *testing.T) {
+	now := time.Now().UTC()
+
+	// GIVEN une session ouverte, secrète, avec quorum et dates
+	src, err := session.NewSessionWithEnd("AG 2026", "assemblée annuelle", now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SetQuorum(session.Quorum{Kind: session.QuorumPercent, Value: 50}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SetSecretBallot(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Open(now); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on la clone
+	clone := src.Clone("AG 2027", false)
+
+	// THEN le clone est un brouillon neuf, avec les réglages mais sans les dates
+	if clone.ID() == src.ID() {
+		t.Error("clone keeps the id of its source")
+	}
+	if clone.Title() != "AG 2027" || clone.Description() != src.Description() {
+		t.Errorf("title, description = %q, %q", clone.Title(), clone.Description())
+	}
+	if clone.State() != session.StateDraft || clone.Template() {
+		t.Errorf("state = %s, template = %v, want a draft session", clone.State(), clone.Template())
+	}
+	if clone.Quorum() != src.Quorum() || !clone.SecretBallot() {
+		t.Errorf("quorum = %v, secret = %v, want the settings of the source", clone.Quorum(), clone.SecretBallot())
+	}
+	if _, ok := clone.EndsAt(); ok {
+		t.Error("clone keeps the end date of its source")
+	}
+
+	// WHEN on le clone sans titre
+	// THEN il garde le titre de la source
+	if got := src.Clone("", false).Title(); got != src.Title() {
+		t.Errorf("title = %q, want %q", got, src.Title())
+	}
+}
+
+func TestSession_TemplateNeverOpens(t *testing.T) {
+	now := time.Now().UTC()
+
+	// GIVEN un modèle avec une date de début
+	src, err := session.NewSessionNoEnd("AG", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := src.Clone("Modèle AG", true)
+	if err := template.SetStartDate(now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on le planifie ou l'ouvre
+	// THEN il reste un brouillon
+	for _, next := range []session.State{session.StateScheduled, session.StateOpen} {
+		if err := template.ChangeState(next, now); !errors.Is(err, session.ErrTemplate) {
+			t.Errorf("%s: expected %v, got %v", next, session.ErrTemplate, err)
+		}
+	}
+	if template.State() != session.StateDraft {
+		t.Errorf("state = %s, want %s", template.State(), session.StateDraft)
+	}
+
+	// WHEN on clone le modèle
+	// THEN le clone est une session qui s'ouvre
+	if err := template.Clone("AG 2027", false).Open(now); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package session
+
+import "errors"
+
+// QuorumKind tells how the quorum of a session is expressed
+type QuorumKind string
+
+const (
+	QuorumNone QuorumKind = "none"
+	// QuorumAbsolute : Value is a number of voters
+	QuorumAbsolute QuorumKind = "absolute"
+	// QuorumPercent : Value is a percentage of the participants
+	QuorumPercent QuorumKind = "percent"
+)
+
+var ErrInvalidQuorum = errors.New("invalid quorum")
+
+// Quorum is the minimum turnout for the results of a session to be valid
+type Quorum struct {
+	Kind  QuorumKind
+	Value int
+}
+
+func NoQuorum() Quorum {
+	return Quorum{Kind: QuorumNone}
+}
+
+func NewQuorum(kind QuorumKind, value int) (Quorum, error) {
+	switch kind {
+	case QuorumNone:
+		return NoQuorum(), nil
+	case QuorumAbsolute:
+		if value < 1 {
+			return Quorum{}, ErrInvalidQuorum
+		}
+	case QuorumPercent:
+		if value < 1 || value > 100 {
+			return Quorum{}, ErrInvalidQuorum
+		}
+	default:
+		return Quorum{}, ErrInvalidQuorum
+	}
+
+	return Quorum{Kind: kind, Value: value}, nil
+}
@@ -2,6 +2,7 @@ package session
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -9,23 +10,54 @@ import (
 type Repository interface {
 	CreateVoteSession(context.Context, *Session) error
 
+	// CreateOrganizedVoteSession stores the session and its first organizer in one transaction
+	CreateOrganizedVoteSession(context.Context, *Session, uuid.UUID /* organizer id */) error
+
 	GetVoteSessionByID(context.Context, uuid.UUID /*session id */) (*Session, error)
 
 	GetUserVoteSessions(context.Context, uuid.UUID /*session id */) ([]*Session, error)
 
 	UpdateVoteSession(context.Context, *Session) error
 
+	// DeleteVoteSession returns ErrFinalized when the session is finalized
 	DeleteVoteSession(context.Context, uuid.UUID /*session id */) error
 
-	CloseVoteSession(context.Context, uuid.UUID /*session id */) error
+	// CloseVoteSession sets the end date to at and the state to closed
+	CloseVoteSession(context.Context, uuid.UUID /*session id */, time.Time /* at */) error
+
+	// SetVoteSessionState only writes the state, the transition is checked by the Session
+	SetVoteSessionState(context.Context, uuid.UUID /*session id */, State) error
 
+	// ListVoteSessions lists the sessions which are not templates
 	ListVoteSessions(context.Context, int /* limit */, int /*offset */) ([]*Session, error)
 
+	ListVoteSessionTemplates(context.Context, int /* limit */, int /*offset */) ([]*Session, error)
+
+	// ListPendingVoteSessions lists the sessions the scheduler may move: scheduled with a start date,
+	// open with an end date, closed but not finalized
+	ListPendingVoteSessions(context.Context) ([]*Session, error)
+
+	// AddParticipant returns ErrAlreadyParticipant when the user is already in the session
 	AddParticipant(context.Context, uuid.UUID /*session id */, uuid.UUID /* user id */) error
 
 	GetParticipants(context.Context, uuid.UUID /*session id */) (uuid.UUIDs /* user id */, error)
 
+	// RemoveParticipant returns ErrNotParticipant when the user is not a participant of the session
 	RemoveParticipant(context.Context, uuid.UUID /*session id */, uuid.UUID /*user id */) error
 
 	IsParticipant(context.Context, uuid.UUID /*session id */, uuid.UUID /*user id */) (bool, error)
+
+	// AddOrganizer adds the user as organizer, or promotes the participant
+	AddOrganizer(context.Context, uuid.UUID /*session id */, uuid.UUID /* user id */) error
+
+	IsOrganizer(context.Context, uuid.UUID /*session id */, uuid.UUID /*user id */) (bool, error)
+
+	// SetWeight returns ErrNotParticipant when the user is not a participant of the session
+	SetWeight(context.Context, uuid.UUID /*session id */, uuid.UUID /*user id */, int /* weight */) error
+
+	// GetWeight returns ErrNotParticipant when the user is not a participant of the session
+	GetWeight(context.Context, uuid.UUID /*session id */, uuid.UUID /*user id */) (int, error)
+
+	// WeighParticipants sums the weights of the participants of the session
+	WeighParticipants(context.Context, uuid.UUID /*session id */) (int, error)
 }
@@ -13,13 +13,30 @@ type Session struct {
 	title       string
 	description string
 	createdAt   time.Time
+	startsAt    *time.Time // nullable, a scheduled session opens at startsAt
 	endsAt      *time.Time // nullable
+	quorum      Quorum
+	// secretBallot : votes are stored without their voter, only the participation is kept
+	secretBallot bool
+	// liveResults : running tallies are pushed to the live stream while the session is open
+	liveResults bool
+	// resultsVisibility : who sees the results, and live tallies, and when
+	resultsVisibility Visibility
+	state             State
+	// template : a reusable draft, it is cloned but never opened
+	template bool
 }
 
 var (
 	ErrNotFound         = errors.New("session not found")
 	ErrEmptyTitle       = errors.New("session title cannot be empty")
 	ErrInvalidSessionID = errors.New("invalid session id")
+	ErrStartAfterEnd    = errors.New("start date must be before end date")
+	ErrNoStartDate      = errors.New("a scheduled session needs a start date")
+	ErrTemplate         = errors.New("a template is never opened, clone it into a session")
+	ErrSettingsLocked   = errors.New("quorum, secret ballot and results settings only change before the session opens")
+	// ErrFinalized : the end date, quorum and secret ballot of a finalized session are frozen
+	ErrFinalized = errors.New("vote session is finalized")
 )
 
 // Getters
@@ -27,11 +44,27 @@ func (s *Session) ID() uuid.UUID        { return s.id }
 func (s *Session) Title() string        { return s.title }
 func (s *Session) Description() string  { return s.description }
 func (s *Session) CreatedAt() time.Time { return s.createdAt }
+func (s *Session) Quorum() Quorum       { return s.quorum }
+func (s *Session) SecretBallot() bool   { return s.secretBallot }
+func (s *Session) LiveResults() bool    { return s.liveResults }
+func (s *Session) Template() bool       { return s.template }
+
+func (s *Session) ResultsVisibility() Visibility { return s.resultsVisibility }
+
+// State is the stored state, see StateAt for the state at a given time
+func (s *Session) State() State { return s.state }
 
 func (s *Session) HasEnd() bool {
 	return s.endsAt != nil
 }
 
+func (s *Session) StartsAt() (time.Time, bool) {
+	if s.startsAt == nil {
+		return time.Time{}, false
+	}
+	return *s.startsAt, true
+}
+
 func (s *Session) EndsAt() (time.Time, bool) {
 	if s.endsAt == nil {
 		return time.Time{}, false
@@ -39,6 +72,19 @@ func (s *Session) EndsAt() (time.Time, bool) {
 	return *s.endsAt, true
 }
 
+// StateAt : an open session is closed once its end date is passed
+func (s *Session) StateAt(now time.Time) State {
+	if s.state == StateOpen && s.endsAt != nil && !now.Before(*s.endsAt) {
+		return StateClosed
+	}
+	return s.state
+}
+
+// IsOpenAt : an open session without end date stays open until it is closed
+func (s *Session) IsOpenAt(now time.Time) bool {
+	return s.StateAt(now) == StateOpen
+}
+
 // Constructeurs
 func NewSessionNoEnd(title, description string) (*Session, error) {
 	if title == "" {
@@ -51,6 +97,10 @@ func NewSessionNoEnd(title, description string) (*Session, error) {
 		description: description,
 		createdAt:   time.Now().UTC(),
 		endsAt:      nil,
+		quorum:      NoQuorum(),
+
+		resultsVisibility: VisibilityPublic,
+		state:             StateDraft,
 	}, nil
 }
 
@@ -65,6 +115,10 @@ func NewSessionWithEnd(title, description string, endsAt time.Time) (*Session, e
 		description: description,
 		createdAt:   time.Now().UTC(),
 		endsAt:      &endsAt,
+		quorum:      NoQuorum(),
+
+		resultsVisibility: VisibilityPublic,
+		state:             StateDraft,
 	}, nil
 }
 
@@ -73,7 +127,14 @@ func Rehydrate(
 	title string,
 	description string,
 	createdAt time.Time,
+	startsAt *time.Time,
 	endsAt *time.Time,
+	quorum Quorum,
+	secretBallot bool,
+	liveResults bool,
+	resultsVisibility Visibility,
+	state State,
+	template bool,
 ) (*Session, error) {
 	if id == uuid.Nil {
 		return nil, ErrInvalidSessionID
@@ -86,19 +147,65 @@ func Rehydrate(
 		return nil, errors.New("end date cannot be before creation date")
 	}
 
+	if startsAt != nil && endsAt != nil && !startsAt.Before(*endsAt) {
+		return nil, ErrStartAfterEnd
+	}
+
 	if description == "" && title != "" {
 		description = title
 	}
 
+	if _, err := NewQuorum(quorum.Kind, quorum.Value); err != nil {
+		return nil, err
+	}
+
+	if _, err := ParseVisibility(string(resultsVisibility)); err != nil {
+		return nil, err
+	}
+
+	if _, err := ParseState(string(state)); err != nil {
+		return nil, err
+	}
+
 	return &Session{
-		id:          id,
-		title:       title,
-		description: description,
-		createdAt:   createdAt,
-		endsAt:      endsAt,
+		id:           id,
+		title:        title,
+		description:  description,
+		createdAt:    createdAt,
+		startsAt:     startsAt,
+		endsAt:       endsAt,
+		quorum:       quorum,
+		secretBallot: secretBallot,
+		liveResults:  liveResults,
+
+		resultsVisibility: resultsVisibility,
+		state:             state,
+		template:          template,
 	}, nil
 }
 
+// Clone is a new draft with the settings of s, without its dates,
+// titled like s when title is empty
+func (s *Session) Clone(title string, template bool) *Session {
+	if title == "" {
+		title = s.title
+	}
+
+	return &Session{
+		id:           uuid.New(),
+		title:        title,
+		description:  s.description,
+		createdAt:    time.Now().UTC(),
+		quorum:       s.quorum,
+		secretBallot: s.secretBallot,
+		liveResults:  s.liveResults,
+
+		resultsVisibility: s.resultsVisibility,
+		state:             StateDraft,
+		template:          template,
+	}
+}
+
 // Comportement métier
 func (s *Session) UpdateTitle(newTitle string) error {
 	if newTitle == "" {
@@ -112,6 +219,24 @@ func (s *Session) UpdateDescription(newDescription string) {
 	s.description = newDescription
 }
 
+// SetStartDate : the start date must be before the end date
+func (s *Session) SetStartDate(startsAt time.Time) error {
+	if s.endsAt != nil && !startsAt.Before(*s.endsAt) {
+		return ErrStartAfterEnd
+	}
+	s.startsAt = &startsAt
+	return nil
+}
+
+// RemoveStartDate : a scheduled session keeps its start date
+func (s *Session) RemoveStartDate() error {
+	if s.state == StateScheduled {
+		return ErrNoStartDate
+	}
+	s.startsAt = nil
+	return nil
+}
+
 func (s *Session) SetEndDate(endsAt time.Time) {
 	s.endsAt = &endsAt
 }
@@ -119,3 +244,131 @@ func (s *Session) SetEndDate(endsAt time.Time) {
 func (s *Session) RemoveEndDate() {
 	s.endsAt = nil
 }
+
+// SetQuorum : like the secret ballot and the results settings, the quorum only changes
+// while the session is editable, the ballots already cast were cast under it
+func (s *Session) SetQuorum(q Quorum) error {
+	checked, err := NewQuorum(q.Kind, q.Value)
+	if err != nil {
+		return err
+	}
+	if checked != s.quorum && !s.state.Editable() {
+		return ErrSettingsLocked
+	}
+	s.quorum = checked
+	return nil
+}
+
+// SetSecretBallot : votes cast before the change would keep their voter
+func (s *Session) SetSecretBallot(secret bool) error {
+	if secret != s.secretBallot && !s.state.Editable() {
+		return ErrSettingsLocked
+	}
+	s.secretBallot = secret
+	return nil
+}
+
+// SetLiveResults : turnout is always live, the running tallies only when set
+func (s *Session) SetLiveResults(live bool) error {
+	if live != s.liveResults && !s.state.Editable() {
+		return ErrSettingsLocked
+	}
+	s.liveResults = live
+	return nil
+}
+
+func (s *Session) SetResultsVisibility(v Visibility) error {
+	checked, err := ParseVisibility(string(v))
+	if err != nil {
+		return err
+	}
+	if checked != s.resultsVisibility && !s.state.Editable() {
+		return ErrSettingsLocked
+	}
+	s.resultsVisibility = checked
+	return nil
+}
+
+// Lifecycle, the transitions start from the state at now
+
+func (s *Session) transition(next State, now time.Time) error {
+	if s.template {
+		return ErrTemplate
+	}
+	if !s.StateAt(now).CanBecome(next) {
+		return ErrInvalidTransition
+	}
+	s.state = next
+	return nil
+}
+
+// Schedule : the session opens at its start date
+func (s *Session) Schedule(now time.Time) error {
+	if s.template {
+		return ErrTemplate
+	}
+	if s.startsAt == nil {
+		return ErrNoStartDate
+	}
+	return s.transition(StateScheduled, now)
+}
+
+// Unschedule brings a scheduled session back to draft
+func (s *Session) Unschedule(now time.Time) error {
+	return s.transition(StateDraft, now)
+}
+
+// Open : a session whose end date is passed cannot open
+func (s *Session) Open(now time.Time) error {
+	if s.template {
+		return ErrTemplate
+	}
+	if !s.StateAt(now).CanBecome(StateOpen) {
+		return ErrInvalidTransition
+	}
+	if s.endsAt != nil && !now.Before(*s.endsAt) {
+		return ErrEnded
+	}
+	s.state = StateOpen
+	return nil
+}
+
+// Close ends the session at now
+func (s *Session) Close(now time.Time) error {
+	if err := s.transition(StateClosed, now); err != nil {
+		return err
+	}
+	s.endsAt = &now
+	return nil
+}
+
+// Expire closes a scheduled session whose end date passed before it was opened, the
+// scheduler being stopped over its whole window. Its end date is kept.
+func (s *Session) Expire(now time.Time) error {
+	if s.StateAt(now) != StateScheduled || s.endsAt == nil || now.Before(*s.endsAt) {
+		return ErrInvalidTransition
+	}
+	s.state = StateClosed
+	return nil
+}
+
+func (s *Session) Archive(now time.Time) error {
+	return s.transition(StateArchived, now)
+}
+
+// ChangeState applies the transition to next
+func (s *Session) ChangeState(next State, now time.Time) error {
+	switch next {
+	case StateDraft:
+		return s.Unschedule(now)
+	case StateScheduled:
+		return s.Schedule(now)
+	case StateOpen:
+		return s.Open(now)
+	case StateClosed:
+		return s.Close(now)
+	case StateArchived:
+		return s.Archive(now)
+	}
+	return ErrInvalidState
+}
@@ -0,0 +1,61 @@
+package session
+
+import (
+	"errors"
+	"slices"
+)
+
+// State of a session in its lifecycle:
+//
+//	draft ⇄ scheduled → open → closed → archived
+//	draft ──────────────↗
+//
+// A session is created as a draft. Its questions and choices only change before it opens,
+// ballots are only cast while it is open.
+type State string
+
+const (
+	StateDraft State = "draft"
+	// StateScheduled : ready, opened at its start date by the scheduler
+	StateScheduled State = "scheduled"
+	StateOpen      State = "open"
+	StateClosed    State = "closed"
+	// StateArchived : closed and kept read-only
+	StateArchived State = "archived"
+)
+
+var (
+	ErrInvalidState      = errors.New("invalid session state")
+	ErrInvalidTransition = errors.New("session cannot go to this state from its current state")
+	ErrEnded             = errors.New("end date of the session is already passed")
+)
+
+var transitions = map[State][]State{
+	StateDraft:     {StateScheduled, StateOpen},
+	StateScheduled: {StateDraft, StateOpen},
+	StateOpen:      {StateClosed},
+	StateClosed:    {StateArchived},
+}
+
+func ParseState(s string) (State, error) {
+	switch st := State(s); st {
+	case StateDraft, StateScheduled, StateOpen, StateClosed, StateArchived:
+		return st, nil
+	}
+	return "", ErrInvalidState
+}
+
+// CanBecome tells whether next is reachable from s in one transition
+func (s State) CanBecome(next State) bool {
+	return slices.Contains(transitions[s], next)
+}
+
+// Editable : questions and choices only change before the session opens
+func (s State) Editable() bool {
+	return s == StateDraft || s == StateScheduled
+}
+
+// Started : the session is, or was, open
+func (s State) Started() bool {
+	return s == StateOpen || s == StateClosed || s == StateArchived
+}
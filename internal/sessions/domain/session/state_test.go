@@ -0,0 +1,189 @@
+package session_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+)
+
+func TestSession_ChangeState(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name    string
+		path    []session.State // transitions applied before the last one
+		next    session.State
+		wantErr error
+	}{
+		{"draft → open", nil, session.StateOpen, nil},
+		{"draft → scheduled", nil, session.StateScheduled, nil},
+		{"scheduled → draft", []session.State{session.StateScheduled}, session.StateDraft, nil},
+		{"scheduled → open", []session.State{session.StateScheduled}, session.StateOpen, nil},
+		{"open → closed", []session.State{session.StateOpen}, session.StateClosed, nil},
+		{"closed → archived", []session.State{session.StateOpen, session.StateClosed}, session.StateArchived, nil},
+		{"draft → closed", nil, session.StateClosed, session.ErrInvalidTransition},
+		{"draft → archived", nil, session.StateArchived, session.ErrInvalidTransition},
+		{"open → draft", []session.State{session.StateOpen}, session.StateDraft, session.ErrInvalidTransition},
+		{"closed → open", []session.State{session.StateOpen, session.StateClosed}, session.StateOpen, session.ErrInvalidTransition},
+		{"archived → closed", []session.State{session.StateOpen, session.StateClosed, session.StateArchived}, session.StateClosed, session.ErrInvalidTransition},
+		{"état inconnu", nil, session.State("paused"), session.ErrInvalidState},
+	}
+
+	// the sessions start in an hour, scheduling needs a start date
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN une session amenée dans l'état de départ
+			s, err := session.NewSessionNoEnd("AG", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.SetStartDate(now.Add(time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+			for _, st := range tt.path {
+				if err := s.ChangeState(st, now); err != nil {
+					t.Fatalf("setup %s: %v", st, err)
+				}
+			}
+			before := s.State()
+
+			// WHEN on demande la transition
+			err = s.ChangeState(tt.next, now)
+
+			// THEN seules les transitions du cycle de vie sont acceptées
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && s.State() != tt.next {
+				t.Errorf("state: got %s, want %s", s.State(), tt.next)
+			}
+			if err != nil && s.State() != before {
+				t.Errorf("state changed on error: got %s, want %s", s.State(), before)
+			}
+		})
+	}
+}
+
+func TestSession_StateAt(t *testing.T) {
+	now := time.Now().UTC()
+
+	// GIVEN une session ouverte qui se termine dans une heure
+	s, err := session.NewSessionWithEnd("AG", "", now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.State() != session.StateDraft || s.IsOpenAt(now) {
+		t.Fatalf("a new session is a closed draft, got %s", s.State())
+	}
+	if err := s.Open(now); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN la date de fin passe
+	later := now.Add(2 * time.Hour)
+
+	// THEN elle est lue fermée, et peut être archivée
+	if !s.IsOpenAt(now) || s.IsOpenAt(later) {
+		t.Errorf("open at now: %v, open after the end: %v", s.IsOpenAt(now), s.IsOpenAt(later))
+	}
+	if s.StateAt(later) != session.StateClosed {
+		t.Errorf("state after the end: got %s", s.StateAt(later))
+	}
+	if err := s.Close(later); !errors.Is(err, session.ErrInvalidTransition) {
+		t.Errorf("expected %v, got %v", session.ErrInvalidTransition, err)
+	}
+	if err := s.Archive(later); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSession_OpenAfterEnd(t *testing.T) {
+	now := time.Now().UTC()
+
+	// GIVEN un brouillon dont la date de fin est passée
+	s, err := session.NewSessionWithEnd("AG", "", now.Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on l'ouvre
+	err = s.Open(now)
+
+	// THEN l'ouverture est refusée
+	if !errors.Is(err, session.ErrEnded) {
+		t.Errorf("expected %v, got %v", session.ErrEnded, err)
+	}
+}
+
+func TestSession_ScheduleNeedsStartDate(t *testing.T) {
+	now := time.Now().UTC()
+
+	// GIVEN un brouillon qui se termine dans une heure, sans date de début
+	s, err := session.NewSessionWithEnd("AG", "", now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on le planifie
+	err = s.Schedule(now)
+
+	// THEN il faut une date de début, avant la date de fin
+	if !errors.Is(err, session.ErrNoStartDate) {
+		t.Errorf("expected %v, got %v", session.ErrNoStartDate, err)
+	}
+	if err := s.SetStartDate(now.Add(2 * time.Hour)); !errors.Is(err, session.ErrStartAfterEnd) {
+		t.Errorf("expected %v, got %v", session.ErrStartAfterEnd, err)
+	}
+	if err := s.SetStartDate(now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Schedule(now); err != nil || s.State() != session.StateScheduled {
+		t.Errorf("got state %s, err %v", s.State(), err)
+	}
+}
+
+func TestSession_SettingsLockedOnceOpen(t *testing.T) {
+	now := time.Now().UTC()
+
+	// GIVEN une session ouverte
+	s, err := session.NewSessionWithEnd("AG", "", now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Open(now); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		set  func() error
+	}{
+		{"quorum", func() error { return s.SetQuorum(session.Quorum{Kind: session.QuorumAbsolute, Value: 3}) }},
+		{"scrutin secret", func() error { return s.SetSecretBallot(true) }},
+		{"résultats en direct", func() error { return s.SetLiveResults(true) }},
+		{"visibilité", func() error { return s.SetResultsVisibility(session.VisibilityOrganizers) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN on change le réglage
+			err := tt.set()
+
+			// THEN c'est refusé
+			if !errors.Is(err, session.ErrSettingsLocked) {
+				t.Errorf("expected %v, got %v", session.ErrSettingsLocked, err)
+			}
+		})
+	}
+
+	// AND redonner les mêmes réglages reste possible
+	if err := s.SetSecretBallot(false); err != nil {
+		t.Errorf("unchanged secret ballot: %v", err)
+	}
+	if err := s.SetResultsVisibility(session.VisibilityPublic); err != nil {
+		t.Errorf("unchanged visibility: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Visibility tells who sees the results of a session and when
+type Visibility string
+
+const (
+	VisibilityPublic Visibility = "public"
+	// VisibilityAfterClose : nobody sees the results while the session is open
+	VisibilityAfterClose Visibility = "after_close"
+	VisibilityOrganizers Visibility = "organizers"
+	// VisibilityVoters : organizers, and participants once they voted
+	VisibilityVoters Visibility = "voters"
+)
+
+var ErrInvalidVisibility = errors.New("invalid results visibility")
+
+func ParseVisibility(s string) (Visibility, error) {
+	switch v := Visibility(s); v {
+	case VisibilityPublic, VisibilityAfterClose, VisibilityOrganizers, VisibilityVoters:
+		return v, nil
+	}
+	return "", ErrInvalidVisibility
+}
+
+// Role of a user in a session, organizers are participants too
+type Role string
+
+const (
+	RoleParticipant Role = "participant"
+	RoleOrganizer   Role = "organizer"
+)
+
+// Member is a user of a session with its role and weight
+type Member struct {
+	UserID uuid.UUID
+	Role   Role
+	Weight int
+}
+
+// DefaultWeight is the weight of a participant added without one
+const DefaultWeight = 1
+
+var (
+	ErrInvalidWeight      = errors.New("weight must be >= 1")
+	ErrWeightedSecret     = errors.New("every participant of a secret ballot weighs 1: the weight of a vote would tell its voter")
+	ErrNotParticipant     = errors.New("user is not a participant of this session")
+	ErrAlreadyParticipant = errors.New("user is already a participant of this session")
+)
+
+// CheckWeight : the weight of a participant is the number of shares its ballots count for
+func CheckWeight(weight int) error {
+	if weight < 1 {
+		return ErrInvalidWeight
+	}
+	return nil
+}
+
+// CheckParticipantWeight : secret ballots and weights are mutually exclusive, the votes of
+// a secret ballot carry the weight of their voter, a weight held by few would identify them
+func (s *Session) CheckParticipantWeight(weight int) error {
+	if err := CheckWeight(weight); err != nil {
+		return err
+	}
+
+	if s.secretBallot && weight != DefaultWeight {
+		return ErrWeightedSecret
+	}
+
+	return nil
+}
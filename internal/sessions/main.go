@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/common/server"
+	questions "github.com/73NN0/voting-app/internal/questions/adapters"
+	"github.com/73NN0/voting-app/internal/sessions/adapters"
+	"github.com/73NN0/voting-app/internal/sessions/app"
+	"github.com/73NN0/voting-app/internal/sessions/ports"
+	users "github.com/73NN0/voting-app/internal/users/adapters"
+	votes "github.com/73NN0/voting-app/internal/votes/adapters"
+	votesapp "github.com/73NN0/voting-app/internal/votes/app"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			if err := runImport(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "export":
+			if err := runExport(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	addr := flag.String("addr", ":4002", "HTTP network address")
+	dsn := flag.String("dsn", "voting.db", "sqlite data source name")
+	devUser := flag.Bool("dev-user-header", false, "trust the "+server.UserIDHeader+" header as the caller id, anyone can forge it: development only")
+	every := flag.Duration("schedule-every", 30*time.Second, "interval of the scheduler opening and finalizing sessions, 0 disables it")
+	flag.Parse()
+
+	if *devUser {
+		log.Println("warning: " + server.UserIDHeader + " is trusted, do not run in production")
+		server.TrustUserIDHeader(true)
+	}
+
+	database, cleanup, err := db.OpenSQLite(*dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer cleanup()
+
+	if err = db.InitializeSchemas(database); err != nil {
+		log.Fatal(err)
+	}
+
+	sessionRepo := adapters.NewSqliteSessionRepository(database)
+	questionRepo := questions.NewSqliteQuestionsRepository(database)
+	choiceRepo := questions.NewSqliteChoicesRepositoy(database)
+
+	questionsInProcess := adapters.NewQuestionsInProcess(questionRepo, choiceRepo)
+	documentRepo := adapters.NewSqliteDocumentsRepository(database, questionRepo, choiceRepo)
+
+	clk := clock.System{}
+	service := app.NewService(sessionRepo, documentRepo, questionsInProcess, clk)
+	userRepo := users.NewSqliteUserRepository(database)
+	invitations := app.NewInvitationService(
+		sessionRepo,
+		adapters.NewSqliteInvitationsRepository(database, userRepo),
+		adapters.NewUsersInProcess(userRepo),
+		clk,
+	)
+	documents := app.NewDocumentService(sessionRepo, documentRepo, questionsInProcess)
+
+	if *every > 0 {
+		sessionsChecker := votes.NewSessionCheckerInProcess(sessionRepo, clk)
+		results := votesapp.NewResultsService(
+			votes.NewSqliteVotesRepository(database),
+			votes.NewSqliteSnapshotsRepository(database),
+			votes.NewSqliteTieBreaksRepository(database),
+			votes.NewQuestionReaderInProcess(questionRepo, choiceRepo),
+			sessionsChecker,
+			sessionsChecker,
+			clk,
+		)
+
+		scheduler := app.NewScheduler(sessionRepo, adapters.NewFinalizerInProcess(results), clk)
+		go scheduler.Run(context.Background(), *every)
+	}
+
+	router := server.NewRouter()
+
+	ports.AddRoutes(router, ports.NewHttpHandler(service, invitations, documents, clk))
+
+	http.ListenAndServe(*addr, router.Handler())
+}
@@ -0,0 +1,1032 @@
+package ports
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/common/logger"
+	"github.com/73NN0/voting-app/internal/common/server"
+	"github.com/73NN0/voting-app/internal/common/server/httperr"
+	"github.com/73NN0/voting-app/internal/common/server/httpstat"
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
+	"github.com/73NN0/voting-app/internal/sessions/app"
+	"github.com/73NN0/voting-app/internal/sessions/domain/document"
+	"github.com/73NN0/voting-app/internal/sessions/domain/invitation"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/73NN0/voting-app/internal/users/domain/user"
+	"github.com/google/uuid"
+)
+
+type HttpHandler struct {
+	service     *app.Service
+	invitations *app.InvitationService
+	documents   *app.DocumentService
+	clock       clock.Clock // the state shown is the one of the services
+}
+
+func NewHttpHandler(service *app.Service, invitations *app.InvitationService, documents *app.DocumentService, clk clock.Clock) *HttpHandler {
+	return &HttpHandler{
+		service:     service,
+		invitations: invitations,
+		documents:   documents,
+		clock:       clk,
+	}
+}
+
+// defaultLimit and maxLimit bound the page of GET /sessions
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// maxDocumentSize bounds the body of POST /sessions/import
+const maxDocumentSize = 1 << 20
+
+type quorumRequest struct {
+	Kind  string `json:"kind"`  // none (default) | absolute | percent
+	Value int    `json:"value"` // voters, or percentage of the participants
+}
+
+type sessionRequest struct {
+	Title             string        `json:"title"`
+	Description       string        `json:"description"`
+	StartsAt          *time.Time    `json:"starts_at"` // needed to schedule the session
+	EndsAt            *time.Time    `json:"ends_at"`   // no end when empty
+	Quorum            quorumRequest `json:"quorum"`
+	SecretBallot      bool          `json:"secret_ballot"`
+	LiveResults       bool          `json:"live_results"`
+	ResultsVisibility string        `json:"results_visibility"` // public (default) | after_close | organizers | voters
+}
+
+func (req sessionRequest) quorum() session.Quorum {
+	if req.Quorum.Kind == "" {
+		return session.NoQuorum()
+	}
+	return session.Quorum{Kind: session.QuorumKind(req.Quorum.Kind), Value: req.Quorum.Value}
+}
+
+func (req sessionRequest) visibility() session.Visibility {
+	if req.ResultsVisibility == "" {
+		return session.VisibilityPublic
+	}
+	return session.Visibility(req.ResultsVisibility)
+}
+
+func (req sessionRequest) toSettings() app.Settings {
+	return app.Settings{
+		Title:             req.Title,
+		Description:       req.Description,
+		StartsAt:          req.StartsAt,
+		EndsAt:            req.EndsAt,
+		Quorum:            req.quorum(),
+		SecretBallot:      req.SecretBallot,
+		LiveResults:       req.LiveResults,
+		ResultsVisibility: req.visibility(),
+	}
+}
+
+func Validate(req sessionRequest) error {
+	if req.Title == "" {
+		return errors.New("title is required")
+	}
+
+	if req.EndsAt != nil && !req.EndsAt.After(time.Now()) {
+		return errors.New("ends_at must be in the future")
+	}
+
+	if req.StartsAt != nil && req.EndsAt != nil && !req.StartsAt.Before(*req.EndsAt) {
+		return errors.New("starts_at must be before ends_at")
+	}
+
+	if _, err := session.NewQuorum(req.quorum().Kind, req.quorum().Value); err != nil {
+		return errors.New("quorum must be none, absolute (value >= 1) or percent (value from 1 to 100)")
+	}
+
+	if _, err := session.ParseVisibility(string(req.visibility())); err != nil {
+		return errors.New("results_visibility must be public, after_close, organizers or voters")
+	}
+
+	return nil
+}
+
+type stateRequest struct {
+	State string `json:"state"` // draft | scheduled | open | closed | archived
+}
+
+func ValidateState(req stateRequest) error {
+	if _, err := session.ParseState(req.State); err != nil {
+		return errors.New("state must be draft, scheduled, open, closed or archived")
+	}
+	return nil
+}
+
+type cloneRequest struct {
+	Title        string `json:"title"`        // title of the source when empty
+	Participants bool   `json:"participants"` // copies the participants, their role and weight
+	Template     bool   `json:"template"`     // saves the clone as a template
+}
+
+func (req cloneRequest) toOptions() app.CloneOptions {
+	return app.CloneOptions{
+		Title:        req.Title,
+		Participants: req.Participants,
+		Template:     req.Template,
+	}
+}
+
+type participantRequest struct {
+	UserID uuid.UUID `json:"user_id"`
+	Weight int       `json:"weight"` // 1 when empty
+}
+
+func (req participantRequest) weight() int {
+	if req.Weight == 0 {
+		return session.DefaultWeight
+	}
+	return req.Weight
+}
+
+func ValidateParticipant(req participantRequest) error {
+	if req.UserID == uuid.Nil {
+		return errors.New("user_id is required")
+	}
+
+	if req.Weight < 0 {
+		return errors.New("weight must be positive")
+	}
+
+	return nil
+}
+
+type invitationRequest struct {
+	Email     string     `json:"email"`
+	Weight    int        `json:"weight"`     // 1 when empty
+	ExpiresAt *time.Time `json:"expires_at"` // in 7 days when empty
+}
+
+func (req invitationRequest) weight() int {
+	if req.Weight == 0 {
+		return session.DefaultWeight
+	}
+	return req.Weight
+}
+
+func ValidateInvitation(req invitationRequest) error {
+	if req.Email == "" {
+		return errors.New("email is required")
+	}
+
+	if req.Weight < 0 {
+		return errors.New("weight must be positive")
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+
+	return nil
+}
+
+type acceptRequest struct {
+	Name string `json:"name"` // creates the user when nobody has the email of the invitation
+}
+
+type quorumResponse struct {
+	Kind  session.QuorumKind `json:"kind"`
+	Value int                `json:"value"`
+}
+
+type sessionResponse struct {
+	ID                uuid.UUID          `json:"id"`
+	Title             string             `json:"title"`
+	Description       string             `json:"description"`
+	CreatedAt         time.Time          `json:"created_at"`
+	StartsAt          *time.Time         `json:"starts_at,omitempty"`
+	EndsAt            *time.Time         `json:"ends_at,omitempty"`
+	State             session.State      `json:"state"`
+	Open              bool               `json:"open"`
+	Quorum            quorumResponse     `json:"quorum"`
+	SecretBallot      bool               `json:"secret_ballot"`
+	LiveResults       bool               `json:"live_results"`
+	ResultsVisibility session.Visibility `json:"results_visibility"`
+	Template          bool               `json:"template"`
+}
+
+func toSessionResponse(s *session.Session, now time.Time) sessionResponse {
+	resp := sessionResponse{
+		ID:                s.ID(),
+		Title:             s.Title(),
+		Description:       s.Description(),
+		CreatedAt:         s.CreatedAt(),
+		State:             s.StateAt(now),
+		Open:              s.IsOpenAt(now),
+		Quorum:            quorumResponse{Kind: s.Quorum().Kind, Value: s.Quorum().Value},
+		SecretBallot:      s.SecretBallot(),
+		LiveResults:       s.LiveResults(),
+		ResultsVisibility: s.ResultsVisibility(),
+		Template:          s.Template(),
+	}
+
+	if startsAt, ok := s.StartsAt(); ok {
+		resp.StartsAt = &startsAt
+	}
+
+	if endsAt, ok := s.EndsAt(); ok {
+		resp.EndsAt = &endsAt
+	}
+
+	return resp
+}
+
+type invitationResponse struct {
+	ID         uuid.UUID         `json:"id"`
+	SessionID  uuid.UUID         `json:"session_id"`
+	Email      string            `json:"email"`
+	Weight     int               `json:"weight"`
+	Status     invitation.Status `json:"status"`
+	UserID     *uuid.UUID        `json:"user_id,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	AnsweredAt *time.Time        `json:"answered_at,omitempty"`
+	Token      string            `json:"token,omitempty"` // only in the response of the invitation
+}
+
+func toInvitationResponse(i *invitation.Invitation, now time.Time) invitationResponse {
+	resp := invitationResponse{
+		ID:        i.ID(),
+		SessionID: i.SessionID(),
+		Email:     i.Email(),
+		Weight:    i.Weight(),
+		Status:    i.StatusAt(now),
+		CreatedAt: i.CreatedAt(),
+		ExpiresAt: i.ExpiresAt(),
+	}
+
+	if userID := i.UserID(); userID != uuid.Nil {
+		resp.UserID = &userID
+	}
+
+	if answeredAt, ok := i.AnsweredAt(); ok {
+		resp.AnsweredAt = &answeredAt
+	}
+
+	return resp
+}
+
+// writeSessionError maps domain and app errors to HTTP status codes
+func writeSessionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, app.ErrNotOrganizer):
+		httperr.Forbidden(w, err.Error())
+	case errors.Is(err, session.ErrNotFound),
+		errors.Is(err, session.ErrNotParticipant),
+		errors.Is(err, invitation.ErrNotFound):
+		httperr.NotFound(w, err.Error())
+	case errors.Is(err, invitation.ErrExpired):
+		httperr.Gone(w, err.Error())
+	case errors.Is(err, session.ErrFinalized),
+		errors.Is(err, session.ErrAlreadyParticipant),
+		errors.Is(err, invitation.ErrAnswered),
+		errors.Is(err, invitation.ErrAlreadyInvited),
+		errors.Is(err, session.ErrInvalidTransition),
+		errors.Is(err, session.ErrEnded),
+		errors.Is(err, session.ErrTemplate),
+		errors.Is(err, session.ErrSettingsLocked):
+		httperr.Conflict(w, err.Error())
+	case errors.Is(err, session.ErrEmptyTitle),
+		errors.Is(err, session.ErrStartAfterEnd),
+		errors.Is(err, session.ErrNoStartDate),
+		errors.Is(err, session.ErrInvalidQuorum),
+		errors.Is(err, session.ErrInvalidVisibility),
+		errors.Is(err, session.ErrInvalidState),
+		errors.Is(err, session.ErrInvalidWeight),
+		errors.Is(err, session.ErrWeightedSecret),
+		errors.Is(err, invitation.ErrInvalidEmail),
+		errors.Is(err, invitation.ErrExpiryPassed),
+		errors.Is(err, user.ErrEmptyName),
+		errors.Is(err, user.ErrInvalidEmail),
+		errors.Is(err, document.ErrNoQuestion),
+		errors.Is(err, document.ErrDuplicateOrder),
+		errors.Is(err, question.ErrEmptyText),
+		errors.Is(err, question.ErrInvalidOrderNum),
+		errors.Is(err, question.ErrInvalidMaxChoice),
+		errors.Is(err, question.ErrInvalidMaxPoints),
+		errors.Is(err, question.ErrInvalidKind),
+		errors.Is(err, question.ErrInvalidMethod),
+		errors.Is(err, question.ErrInvalidSeats),
+		errors.Is(err, question.ErrSeatsNeedSTV),
+		errors.Is(err, question.ErrInvalidThreshold),
+		errors.Is(err, question.ErrInvalidTieBreak),
+		errors.Is(err, choice.ErrEmptyChoiceText),
+		errors.Is(err, choice.ErrInvalidChoiceOrder):
+		httperr.UnprocessableEntity(w, err.Error())
+	default:
+		httperr.InternalServerError(w, err.Error())
+	}
+}
+
+// sessionAndUser reads {sessionID} and the caller id, it writes the error response itself
+func sessionAndUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	sessionID, ok := sessionIDFrom(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := server.UserID(r)
+	if err != nil {
+		httperr.Unauthorized(w, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return sessionID, userID, true
+}
+
+func sessionIDFrom(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	idStr := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Logger.Warn("invalid session ID", "idStr", idStr)
+		httperr.BadRequest(w, "invalid session ID")
+		return uuid.Nil, false
+	}
+
+	return sessionID, true
+}
+
+// documentFormat reads ?format=, else the Content-Type of the request, JSON by default
+func documentFormat(r *http.Request) (document.Format, error) {
+	if s := r.URL.Query().Get("format"); s != "" {
+		return document.ParseFormat(s)
+	}
+
+	if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+		return document.FormatYAML, nil
+	}
+
+	return document.FormatJSON, nil
+}
+
+// pagination reads ?limit= and ?offset=, limit is bounded by maxLimit
+func pagination(r *http.Request) (int, int, error) {
+	limit, offset := defaultLimit, 0
+
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return 0, 0, errors.New("limit must be positive")
+		}
+		limit = min(n, maxLimit)
+	}
+
+	if s := r.URL.Query().Get("offset"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset cannot be negative")
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
+}
+
+func (h *HttpHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := server.UserID(r)
+	if err != nil {
+		httperr.Unauthorized(w, err.Error())
+		return
+	}
+
+	var req sessionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	if err := Validate(req); err != nil {
+		logger.Logger.Warn("validation failed", "err", err)
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	s, err := h.service.CreateSession(ctx, userID, req.toSettings())
+	if err != nil {
+		logger.Logger.Error("create session failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.CreatedJSON(w, toSessionResponse(s, h.clock.Now()))
+}
+
+func (h *HttpHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, ok := sessionIDFrom(w, r)
+	if !ok {
+		return
+	}
+
+	s, err := h.service.GetSession(ctx, sessionID)
+	if err != nil {
+		logger.Logger.Error("get session failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, toSessionResponse(s, h.clock.Now()))
+}
+
+func (h *HttpHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	sessions, err := h.service.ListSessions(ctx, limit, offset)
+	if err != nil {
+		logger.Logger.Error("list sessions failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	now := h.clock.Now()
+	resp := make([]sessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, toSessionResponse(s, now))
+	}
+
+	httpstat.OkJSON(w, resp)
+}
+
+func (h *HttpHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	templates, err := h.service.ListTemplates(ctx, limit, offset)
+	if err != nil {
+		logger.Logger.Error("list templates failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	now := h.clock.Now()
+	resp := make([]sessionResponse, 0, len(templates))
+	for _, s := range templates {
+		resp = append(resp, toSessionResponse(s, now))
+	}
+
+	httpstat.OkJSON(w, resp)
+}
+
+// CloneSession : cloning with template saves a session as a template, cloning a template creates a session from it
+func (h *HttpHandler) CloneSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req cloneRequest
+
+	// the body is optional
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	s, err := h.service.CloneSession(ctx, userID, sessionID, req.toOptions())
+	if err != nil {
+		logger.Logger.Error("clone session failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.CreatedJSON(w, toSessionResponse(s, h.clock.Now()))
+}
+
+// ImportSession creates a draft session from a JSON or YAML document, the caller is its organizer
+func (h *HttpHandler) ImportSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := server.UserID(r)
+	if err != nil {
+		httperr.Unauthorized(w, err.Error())
+		return
+	}
+
+	format, err := documentFormat(r)
+	if err != nil {
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDocumentSize))
+	if err != nil {
+		logger.Logger.Warn("read document failed", "err", err)
+		httperr.BadRequest(w, "document too large or unreadable")
+		return
+	}
+
+	doc, err := document.Parse(data, format)
+	if err != nil {
+		logger.Logger.Warn("invalid document", "err", err)
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	s, err := h.documents.ImportSession(ctx, userID, doc)
+	if err != nil {
+		logger.Logger.Error("import session failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.CreatedJSON(w, toSessionResponse(s, h.clock.Now()))
+}
+
+// ExportSession writes the session, its questions and choices as a document, ?format=json (default) | yaml
+func (h *HttpHandler) ExportSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, ok := sessionIDFrom(w, r)
+	if !ok {
+		return
+	}
+
+	format := document.FormatJSON
+	if s := r.URL.Query().Get("format"); s != "" {
+		f, err := document.ParseFormat(s)
+		if err != nil {
+			logger.Logger.Warn("invalid document format", "format", s)
+			httperr.BadRequest(w, "format must be json or yaml")
+			return
+		}
+		format = f
+	}
+
+	doc, err := h.documents.ExportSession(ctx, sessionID)
+	if err != nil {
+		logger.Logger.Error("export session failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	data, err := document.Encode(doc, format)
+	if err != nil {
+		logger.Logger.Error("encode document failed", "err", err)
+		httperr.InternalServerError(w, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("%s.%s", sessionID, format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *HttpHandler) UpdateSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req sessionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	if err := Validate(req); err != nil {
+		logger.Logger.Warn("validation failed", "err", err)
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	s, err := h.service.UpdateSession(ctx, userID, sessionID, req.toSettings())
+	if err != nil {
+		logger.Logger.Error("update session failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, toSessionResponse(s, h.clock.Now()))
+}
+
+func (h *HttpHandler) ChangeState(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req stateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	if err := ValidateState(req); err != nil {
+		logger.Logger.Warn("validation failed", "err", err)
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	s, err := h.service.ChangeState(ctx, userID, sessionID, session.State(req.State))
+	if err != nil {
+		logger.Logger.Error("change session state failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, toSessionResponse(s, h.clock.Now()))
+}
+
+func (h *HttpHandler) CloseSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.CloseSession(ctx, userID, sessionID); err != nil {
+		logger.Logger.Error("close session failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.NoContent(w, "session closed")
+}
+
+func (h *HttpHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSession(ctx, userID, sessionID); err != nil {
+		logger.Logger.Error("delete session failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.NoContent(w, "session deleted")
+}
+
+func (h *HttpHandler) ListParticipants(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, ok := sessionIDFrom(w, r)
+	if !ok {
+		return
+	}
+
+	participants, err := h.service.ListParticipants(ctx, sessionID)
+	if err != nil {
+		logger.Logger.Error("list participants failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	if participants == nil {
+		participants = uuid.UUIDs{}
+	}
+
+	httpstat.OkJSON(w, participants)
+}
+
+func (h *HttpHandler) AddParticipant(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, organizerID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req participantRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	if err := ValidateParticipant(req); err != nil {
+		logger.Logger.Warn("validation failed", "err", err)
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.service.AddParticipant(ctx, organizerID, sessionID, req.UserID, req.weight()); err != nil {
+		logger.Logger.Error("add participant failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.CreatedJSON(w, map[string]any{"user_id": req.UserID, "weight": req.weight()})
+}
+
+func (h *HttpHandler) RemoveParticipant(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, organizerID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	idStr := r.PathValue("userID")
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Logger.Warn("invalid user ID", "idStr", idStr)
+		httperr.BadRequest(w, "invalid user ID")
+		return
+	}
+
+	if err := h.service.RemoveParticipant(ctx, organizerID, sessionID, userID); err != nil {
+		logger.Logger.Error("remove participant failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.NoContent(w, "participant removed")
+}
+
+func (h *HttpHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, organizerID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req invitationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	if err := ValidateInvitation(req); err != nil {
+		logger.Logger.Warn("validation failed", "err", err)
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	inv, token, err := h.invitations.Invite(ctx, organizerID, sessionID, req.Email, req.weight(), req.ExpiresAt)
+	if err != nil {
+		logger.Logger.Error("invite failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	resp := toInvitationResponse(inv, h.clock.Now())
+	resp.Token = token
+
+	httpstat.CreatedJSON(w, resp)
+}
+
+func (h *HttpHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, organizerID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	var status invitation.Status
+	if s := r.URL.Query().Get("status"); s != "" {
+		st, err := invitation.ParseStatus(s)
+		if err != nil {
+			httperr.BadRequest(w, "status must be pending, accepted, declined or expired")
+			return
+		}
+		status = st
+	}
+
+	invitations, err := h.invitations.ListInvitations(ctx, organizerID, sessionID)
+	if err != nil {
+		logger.Logger.Error("list invitations failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	now := h.clock.Now()
+	resp := make([]invitationResponse, 0, len(invitations))
+	for _, i := range invitations {
+		if ir := toInvitationResponse(i, now); status == "" || ir.Status == status {
+			resp = append(resp, ir)
+		}
+	}
+
+	httpstat.OkJSON(w, resp)
+}
+
+// GetInvitation : the token is the credential, no user is needed
+func (h *HttpHandler) GetInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	inv, err := h.invitations.GetInvitation(ctx, r.PathValue("token"))
+	if err != nil {
+		logger.Logger.Error("get invitation failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, toInvitationResponse(inv, h.clock.Now()))
+}
+
+// AcceptInvitation links the calling user, when there is one, or the user with the email of the invitation
+func (h *HttpHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID := uuid.Nil
+	if r.Header.Get(server.UserIDHeader) != "" {
+		id, err := server.UserID(r)
+		if err != nil {
+			httperr.Unauthorized(w, err.Error())
+			return
+		}
+		userID = id
+	}
+
+	var req acceptRequest
+
+	// the body is optional
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	inv, err := h.invitations.AcceptInvitation(ctx, r.PathValue("token"), userID, req.Name)
+	if err != nil {
+		logger.Logger.Error("accept invitation failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, toInvitationResponse(inv, h.clock.Now()))
+}
+
+func (h *HttpHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	inv, err := h.invitations.DeclineInvitation(ctx, r.PathValue("token"))
+	if err != nil {
+		logger.Logger.Error("decline invitation failed", "err", err)
+		writeSessionError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, toInvitationResponse(inv, h.clock.Now()))
+}
+
+// Routes are registered with their full path, like the votes routes which share the /sessions/ prefix
+func AddRoutes(r *server.Router, h *HttpHandler) {
+	// URL: POST /sessions
+	r.Handle("POST /sessions",
+		http.HandlerFunc(h.CreateSession),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /sessions/import?format=
+	r.Handle("POST /sessions/import",
+		http.HandlerFunc(h.ImportSession),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions?limit=&offset=
+	r.Handle("GET /sessions",
+		http.HandlerFunc(h.ListSessions),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /templates?limit=&offset=
+	r.Handle("GET /templates",
+		http.HandlerFunc(h.ListTemplates),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}
+	r.Handle("GET /sessions/{sessionID}",
+		http.HandlerFunc(h.GetSession),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: PUT /sessions/{sessionID}
+	r.Handle("PUT /sessions/{sessionID}",
+		http.HandlerFunc(h.UpdateSession),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: DELETE /sessions/{sessionID}
+	r.Handle("DELETE /sessions/{sessionID}",
+		http.HandlerFunc(h.DeleteSession),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/document?format=
+	r.Handle("GET /sessions/{sessionID}/document",
+		http.HandlerFunc(h.ExportSession),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /sessions/{sessionID}/clone
+	r.Handle("POST /sessions/{sessionID}/clone",
+		http.HandlerFunc(h.CloneSession),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /sessions/{sessionID}/close
+	r.Handle("POST /sessions/{sessionID}/close",
+		http.HandlerFunc(h.CloseSession),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /sessions/{sessionID}/state
+	r.Handle("POST /sessions/{sessionID}/state",
+		http.HandlerFunc(h.ChangeState),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/participants
+	r.Handle("GET /sessions/{sessionID}/participants",
+		http.HandlerFunc(h.ListParticipants),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /sessions/{sessionID}/participants
+	r.Handle("POST /sessions/{sessionID}/participants",
+		http.HandlerFunc(h.AddParticipant),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: DELETE /sessions/{sessionID}/participants/{userID}
+	r.Handle("DELETE /sessions/{sessionID}/participants/{userID}",
+		http.HandlerFunc(h.RemoveParticipant),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /sessions/{sessionID}/invitations
+	r.Handle("POST /sessions/{sessionID}/invitations",
+		http.HandlerFunc(h.Invite),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/invitations?status=
+	r.Handle("GET /sessions/{sessionID}/invitations",
+		http.HandlerFunc(h.ListInvitations),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /invitations/{token}
+	r.Handle("GET /invitations/{token}",
+		http.HandlerFunc(h.GetInvitation),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /invitations/{token}/accept
+	r.Handle("POST /invitations/{token}/accept",
+		http.HandlerFunc(h.AcceptInvitation),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /invitations/{token}/decline
+	r.Handle("POST /invitations/{token}/decline",
+		http.HandlerFunc(h.DeclineInvitation),
+		server.Logging, server.Recovery, server.CORS,
+	)
+}
@@ -71,9 +71,20 @@ func NewSqliteUserRepository(database *sql.DB) *SqliteUserRepository {
 // ===== User CRUD =====
 
 func (r *SqliteUserRepository) CreateUser(ctx context.Context, u *user.User) error {
+	return insertUser(ctx, r.db, u)
+}
+
+// CreateUserTx creates the user in the transaction of another context, which commits it
+func (r *SqliteUserRepository) CreateUserTx(ctx context.Context, tx *sql.Tx, u *user.User) error {
+	return insertUser(ctx, tx, u)
+}
+
+func insertUser(ctx context.Context, exec interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+}, u *user.User) error {
 	dto := toUserDTO(u)
 
-	_, err := r.db.ExecContext(ctx, `
+	_, err := exec.ExecContext(ctx, `
 		INSERT INTO "user" (id, name, email, created_at)
 		VALUES (?, ?, ?, ?)
 	`, dto.ID, dto.Name, dto.Email, dto.CreatedAt)
@@ -96,7 +107,7 @@ func (r *SqliteUserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user %s not found", id)
+			return nil, fmt.Errorf("%w: %s", user.ErrNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to query user: %w", err)
 	}
@@ -115,7 +126,7 @@ func (r *SqliteUserRepository) GetUserByEmail(ctx context.Context, email string)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user with email %s not found", email)
+			return nil, fmt.Errorf("%w: %s", user.ErrNotFound, email)
 		}
 		return nil, fmt.Errorf("failed to query user: %w", err)
 	}
@@ -20,6 +20,7 @@ var (
 	ErrEmptyName     = errors.New("user name cannot be empty")
 	ErrInvalidEmail  = errors.New("invalid email format")
 	ErrInvalidUserID = errors.New("invalid user id")
+	ErrNotFound      = errors.New("user not found")
 )
 
 // Regex simple pour validation email
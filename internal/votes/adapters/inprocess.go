@@ -0,0 +1,273 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/73NN0/voting-app/internal/votes/app"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+// ======================= Sessions ==================== //
+
+type SessionCheckerInProcess struct {
+	repo  session.Repository
+	clock clock.Clock
+}
+
+var (
+	_ app.SessionChecker = (*SessionCheckerInProcess)(nil)
+	_ app.SessionCloser  = (*SessionCheckerInProcess)(nil)
+)
+
+// NewSessionCheckerInProcess : clk tells whether a session is open, the same clock as the scheduler
+func NewSessionCheckerInProcess(repo session.Repository, clk clock.Clock) *SessionCheckerInProcess {
+	if repo == nil {
+		panic(" missing session repository")
+	}
+
+	if clk == nil {
+		panic(" missing clock")
+	}
+
+	return &SessionCheckerInProcess{repo: repo, clock: clk}
+}
+
+func (c *SessionCheckerInProcess) Exists(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	_, err := c.repo.GetVoteSessionByID(ctx, sessionID)
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, session.ErrNotFound) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func (c *SessionCheckerInProcess) CountParticipants(ctx context.Context, sessionID uuid.UUID) (int, error) {
+	participants, err := c.repo.GetParticipants(ctx, sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(participants), nil
+}
+
+func (c *SessionCheckerInProcess) IsParticipant(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	return c.repo.IsParticipant(ctx, sessionID, userID)
+}
+
+func (c *SessionCheckerInProcess) IsOpen(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	s, err := c.repo.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return false, app.ErrSessionNotFound
+		}
+		return false, err
+	}
+
+	return s.IsOpenAt(c.clock.Now()), nil
+}
+
+// IsClosed : closed or archived
+func (c *SessionCheckerInProcess) IsClosed(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	s, err := c.repo.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return false, app.ErrSessionNotFound
+		}
+		return false, err
+	}
+
+	state := s.StateAt(c.clock.Now())
+	return state == session.StateClosed || state == session.StateArchived, nil
+}
+
+func (c *SessionCheckerInProcess) GetQuorum(ctx context.Context, sessionID uuid.UUID) (vote.Quorum, error) {
+	s, err := c.repo.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return vote.Quorum{}, app.ErrSessionNotFound
+		}
+		return vote.Quorum{}, err
+	}
+
+	q := s.Quorum()
+	return vote.Quorum{Kind: vote.QuorumKind(q.Kind), Value: q.Value}, nil
+}
+
+func (c *SessionCheckerInProcess) IsSecret(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	s, err := c.repo.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return false, app.ErrSessionNotFound
+		}
+		return false, err
+	}
+
+	return s.SecretBallot(), nil
+}
+
+func (c *SessionCheckerInProcess) HasLiveResults(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	s, err := c.repo.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return false, app.ErrSessionNotFound
+		}
+		return false, err
+	}
+
+	return s.LiveResults(), nil
+}
+
+func (c *SessionCheckerInProcess) GetVisibility(ctx context.Context, sessionID uuid.UUID) (vote.Visibility, error) {
+	s, err := c.repo.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return "", app.ErrSessionNotFound
+		}
+		return "", err
+	}
+
+	return vote.Visibility(s.ResultsVisibility()), nil
+}
+
+func (c *SessionCheckerInProcess) IsOrganizer(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	return c.repo.IsOrganizer(ctx, sessionID, userID)
+}
+
+func (c *SessionCheckerInProcess) GetWeight(ctx context.Context, sessionID, userID uuid.UUID) (int, error) {
+	weight, err := c.repo.GetWeight(ctx, sessionID, userID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotParticipant) {
+			return 0, app.ErrNotParticipant
+		}
+		return 0, err
+	}
+
+	return weight, nil
+}
+
+func (c *SessionCheckerInProcess) WeighParticipants(ctx context.Context, sessionID uuid.UUID) (int, error) {
+	return c.repo.WeighParticipants(ctx, sessionID)
+}
+
+func (c *SessionCheckerInProcess) Close(ctx context.Context, sessionID uuid.UUID) error {
+	s, err := c.repo.GetVoteSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return app.ErrSessionNotFound
+		}
+		return err
+	}
+
+	now := c.clock.Now()
+
+	switch state := s.StateAt(now); {
+	case state == session.StateOpen:
+		return c.repo.CloseVoteSession(ctx, sessionID, now)
+	case !state.Started():
+		return app.ErrSessionNotOpen
+	case s.State() == session.StateOpen:
+		// the end date is passed, the state follows
+		return c.repo.SetVoteSessionState(ctx, sessionID, session.StateClosed)
+	}
+
+	return nil
+}
+
+// ======================= Questions ==================== //
+
+type QuestionReaderInProcess struct {
+	questions question.Repository
+	choices   choice.Repository
+}
+
+var _ app.QuestionReader = (*QuestionReaderInProcess)(nil)
+
+func NewQuestionReaderInProcess(questions question.Repository, choices choice.Repository) *QuestionReaderInProcess {
+	if questions == nil {
+		panic(" missing question repository")
+	}
+
+	if choices == nil {
+		panic(" missing choice repository")
+	}
+
+	return &QuestionReaderInProcess{questions: questions, choices: choices}
+}
+
+func (r *QuestionReaderInProcess) GetQuestion(ctx context.Context, questionID int) (vote.Question, error) {
+	exists, err := r.questions.IsQuestionExists(ctx, questionID)
+	if err != nil {
+		return vote.Question{}, err
+	}
+
+	if !exists {
+		return vote.Question{}, app.ErrQuestionNotFound
+	}
+
+	q, err := r.questions.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		return vote.Question{}, err
+	}
+
+	choices, err := r.choices.GetChoicesByQuestionID(ctx, questionID)
+	if err != nil {
+		return vote.Question{}, err
+	}
+
+	return toVoteQuestion(q, choices), nil
+}
+
+func (r *QuestionReaderInProcess) ListSessionQuestions(ctx context.Context, sessionID uuid.UUID) ([]vote.Question, error) {
+	questions, err := r.questions.GetQuestionsBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]vote.Question, 0, len(questions))
+	for _, q := range questions {
+		choices, err := r.choices.GetChoicesByQuestionID(ctx, q.ID())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, toVoteQuestion(q, choices))
+	}
+
+	return result, nil
+}
+
+func toVoteQuestion(q question.Question, choices []choice.Choice) vote.Question {
+	vq := vote.Question{
+		ID:            q.ID(),
+		SessionID:     q.SessionID(),
+		Text:          q.Text(),
+		Kind:          vote.Kind(q.Kind()),
+		Method:        vote.Method(q.Method()),
+		AllowMultiple: q.AllowMultiple(),
+		MaxChoices:    q.MaxChoices(),
+		MaxPoints:     q.MaxPoints(),
+		Seats:         q.Seats(),
+		Threshold:     vote.Threshold(q.Threshold()),
+		TieBreak:      vote.TieBreak(q.TieBreak()),
+		Choices:       make([]vote.Choice, 0, len(choices)),
+	}
+
+	for _, c := range choices {
+		vq.Choices = append(vq.Choices, vote.Choice{
+			ID:       c.ID(),
+			Text:     c.Text(),
+			OrderNum: c.OrderNum(),
+		})
+	}
+
+	return vq
+}
@@ -0,0 +1,26 @@
+package adapters
+
+import (
+	"github.com/73NN0/voting-app/internal/common/server"
+	"github.com/73NN0/voting-app/internal/votes/app"
+	"github.com/google/uuid"
+)
+
+// LiveBroker publishes the ballot changes of a session on the topic of its id
+type LiveBroker struct {
+	broker *server.Broker
+}
+
+var _ app.LiveNotifier = (*LiveBroker)(nil)
+
+func NewLiveBroker(broker *server.Broker) *LiveBroker {
+	if broker == nil {
+		panic(" missing broker")
+	}
+
+	return &LiveBroker{broker: broker}
+}
+
+func (b *LiveBroker) BallotsChanged(sessionID uuid.UUID) {
+	b.broker.Publish(sessionID.String())
+}
@@ -0,0 +1,199 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/votes/domain/receipt"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+// ======================= DTO ==================== //
+
+// receiptDTO représente la table "user_history"
+type receiptDTO struct {
+	ID          string       // TEXT (uuid)
+	UserID      string       // TEXT (uuid)
+	SessionID   string       // TEXT (uuid)
+	Version     int          // INTEGER
+	StringSize  int          // INTEGER, size of the signed payload
+	ReceiptData []byte       // BLOB, payload || signature
+	Checksum    string       // TEXT, hex sha256 of the payload
+	CreatedAt   db.Timestamp // TEXT
+}
+
+func toReceiptDTO(r receipt.Receipt) receiptDTO {
+	return receiptDTO{
+		ID:          r.ID().String(),
+		UserID:      r.UserID().String(),
+		SessionID:   r.SessionID().String(),
+		Version:     r.Version(),
+		StringSize:  len(r.Payload()),
+		ReceiptData: r.Data(),
+		Checksum:    r.Checksum(),
+		CreatedAt:   db.Timestamp{Time: r.CreatedAt()},
+	}
+}
+
+func (dto receiptDTO) toReceipt() (receipt.Receipt, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return receipt.Receipt{}, fmt.Errorf("invalid receipt id: %w", err)
+	}
+
+	userID, err := uuid.Parse(dto.UserID)
+	if err != nil {
+		return receipt.Receipt{}, fmt.Errorf("invalid user id: %w", err)
+	}
+
+	sessionID, err := uuid.Parse(dto.SessionID)
+	if err != nil {
+		return receipt.Receipt{}, fmt.Errorf("invalid session id: %w", err)
+	}
+
+	return receipt.Rehydrate(
+		id,
+		userID,
+		sessionID,
+		dto.Version,
+		dto.StringSize,
+		dto.ReceiptData,
+		dto.Checksum,
+		dto.CreatedAt.Time,
+	)
+}
+
+// ========== Repository Implementation ==========
+
+type SqliteReceiptsRepository struct {
+	db *sql.DB
+}
+
+// Compile-time check
+var _ receipt.Repository = (*SqliteReceiptsRepository)(nil)
+
+func NewSqliteReceiptsRepository(db *sql.DB) *SqliteReceiptsRepository {
+	if db == nil {
+		panic("no db in SQL receipts repository !")
+	}
+
+	return &SqliteReceiptsRepository{db: db}
+}
+
+func (r *SqliteReceiptsRepository) SaveReceipt(ctx context.Context, rc receipt.Receipt) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		return saveReceipt(ctx, tx, rc)
+	})
+}
+
+// CastBallot writes the votes and their receipt in one transaction
+func (r *SqliteReceiptsRepository) CastBallot(ctx context.Context, rc receipt.Receipt, votes []vote.Vote) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		for _, v := range votes {
+			if err := insertVote(ctx, tx, v); err != nil {
+				return err
+			}
+		}
+		return saveReceipt(ctx, tx, rc)
+	})
+}
+
+// CastSecretBallot writes the participation of the voter of the receipt, the votes
+// without voter and the receipt in one transaction
+func (r *SqliteReceiptsRepository) CastSecretBallot(ctx context.Context, rc receipt.Receipt, votes []vote.Vote) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := insertSecretVotes(ctx, tx, rc.UserID(), votes); err != nil {
+			return err
+		}
+		return saveReceipt(ctx, tx, rc)
+	})
+}
+
+// ReplaceBallot moves the superseded votes to the history, casts the new ones
+// and replaces the receipt in one transaction
+func (r *SqliteReceiptsRepository) ReplaceBallot(ctx context.Context, rc receipt.Receipt, superseded []vote.Superseded, next []vote.Vote) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := replaceVotes(ctx, tx, superseded, next); err != nil {
+			return err
+		}
+		return saveReceipt(ctx, tx, rc)
+	})
+}
+
+// RevokeBallot moves the superseded votes to the history and replaces the receipt
+// by the one of the revocation in one transaction
+func (r *SqliteReceiptsRepository) RevokeBallot(ctx context.Context, rc receipt.Receipt, superseded []vote.Superseded) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := replaceVotes(ctx, tx, superseded, nil); err != nil {
+			return err
+		}
+		return saveReceipt(ctx, tx, rc)
+	})
+}
+
+func (r *SqliteReceiptsRepository) inTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func saveReceipt(ctx context.Context, tx *sql.Tx, rc receipt.Receipt) error {
+	dto := toReceiptDTO(rc)
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO user_history (id, user_id, session_id, version, string_size, receipt_data, checksum, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, session_id) DO UPDATE SET
+			id = excluded.id,
+			version = excluded.version,
+			string_size = excluded.string_size,
+			receipt_data = excluded.receipt_data,
+			checksum = excluded.checksum,
+			created_at = excluded.created_at
+	`, dto.ID, dto.UserID, dto.SessionID, dto.Version, dto.StringSize, dto.ReceiptData, dto.Checksum, dto.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save receipt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SqliteReceiptsRepository) GetReceipt(ctx context.Context, userID, sessionID uuid.UUID) (receipt.Receipt, error) {
+	var dto receiptDTO
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, session_id, version, string_size, receipt_data, checksum, created_at
+		FROM user_history
+		WHERE user_id = ? AND session_id = ?
+	`, userID.String(), sessionID.String()).Scan(
+		&dto.ID,
+		&dto.UserID,
+		&dto.SessionID,
+		&dto.Version,
+		&dto.StringSize,
+		&dto.ReceiptData,
+		&dto.Checksum,
+		&dto.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return receipt.Receipt{}, receipt.ErrNotFound
+		}
+		return receipt.Receipt{}, fmt.Errorf("failed to query receipt: %w", err)
+	}
+
+	return dto.toReceipt()
+}
@@ -0,0 +1,185 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/votes/domain/snapshot"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+// ======================= DTO ==================== //
+
+// snapshotDTO représente la table "result_history"
+type snapshotDTO struct {
+	ID         string       // TEXT (uuid)
+	SessionID  string       // TEXT (uuid)
+	Version    int          // INTEGER
+	StringSize int          // INTEGER, size of the document
+	ResultData []byte       // BLOB, JSON document
+	Checksum   string       // TEXT, hex sha256 of the document
+	CreatedAt  db.Timestamp // TEXT
+}
+
+func toSnapshotDTO(s snapshot.Snapshot) snapshotDTO {
+	document := s.Document()
+
+	return snapshotDTO{
+		ID:         s.ID().String(),
+		SessionID:  s.SessionID().String(),
+		Version:    s.Version(),
+		StringSize: len(document),
+		ResultData: document,
+		Checksum:   s.Checksum(),
+		CreatedAt:  db.Timestamp{Time: s.CreatedAt()},
+	}
+}
+
+func (dto snapshotDTO) toSnapshot() (snapshot.Snapshot, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return snapshot.Snapshot{}, fmt.Errorf("invalid snapshot id: %w", err)
+	}
+
+	sessionID, err := uuid.Parse(dto.SessionID)
+	if err != nil {
+		return snapshot.Snapshot{}, fmt.Errorf("invalid session id: %w", err)
+	}
+
+	return snapshot.Rehydrate(id, sessionID, dto.Version, dto.StringSize, dto.ResultData, dto.Checksum, dto.CreatedAt.Time)
+}
+
+// ========== Repository Implementation ==========
+
+type SqliteSnapshotsRepository struct {
+	db *sql.DB
+}
+
+// Compile-time check
+var _ snapshot.Repository = (*SqliteSnapshotsRepository)(nil)
+
+func NewSqliteSnapshotsRepository(db *sql.DB) *SqliteSnapshotsRepository {
+	if db == nil {
+		panic("no db in SQL snapshots repository !")
+	}
+
+	return &SqliteSnapshotsRepository{db: db}
+}
+
+// SaveSnapshot finalizes the session: the triggers of result_history freeze it from then on
+func (r *SqliteSnapshotsRepository) SaveSnapshot(ctx context.Context, s snapshot.Snapshot) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertSnapshot(ctx, tx, s); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Finalize tallies the votes read in the transaction which saves the snapshot
+func (r *SqliteSnapshotsRepository) Finalize(ctx context.Context, tally func(vote.Counter) (snapshot.Snapshot, error)) (snapshot.Snapshot, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return snapshot.Snapshot{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	s, err := tally(&SqliteVotesRepository{db: r.db, q: tx})
+	if err != nil {
+		return snapshot.Snapshot{}, err
+	}
+
+	if err := insertSnapshot(ctx, tx, s); err != nil {
+		return snapshot.Snapshot{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return snapshot.Snapshot{}, fmt.Errorf("failed to commit snapshot: %w", err)
+	}
+
+	return s, nil
+}
+
+func insertSnapshot(ctx context.Context, tx *sql.Tx, s snapshot.Snapshot) error {
+	dto := toSnapshotDTO(s)
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO result_history (id, session_id, version, string_size, result_data, checksum, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, dto.ID, dto.SessionID, dto.Version, dto.StringSize, dto.ResultData, dto.Checksum, dto.CreatedAt)
+
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return snapshot.ErrAlreadyFinalized
+		}
+		return fmt.Errorf("failed to insert result snapshot: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SqliteSnapshotsRepository) GetSnapshot(ctx context.Context, sessionID uuid.UUID) (snapshot.Snapshot, error) {
+	dto, err := r.getSnapshot(ctx, sessionID)
+	if err != nil {
+		return snapshot.Snapshot{}, err
+	}
+
+	return dto.toSnapshot()
+}
+
+func (r *SqliteSnapshotsRepository) GetStoredSnapshot(ctx context.Context, sessionID uuid.UUID) (snapshot.Stored, error) {
+	dto, err := r.getSnapshot(ctx, sessionID)
+	if err != nil {
+		return snapshot.Stored{}, err
+	}
+
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return snapshot.Stored{}, fmt.Errorf("invalid snapshot id: %w", err)
+	}
+
+	return snapshot.Stored{
+		ID:         id,
+		SessionID:  sessionID,
+		Version:    dto.Version,
+		StringSize: dto.StringSize,
+		Document:   dto.ResultData,
+		Checksum:   dto.Checksum,
+		CreatedAt:  dto.CreatedAt.Time,
+	}, nil
+}
+
+func (r *SqliteSnapshotsRepository) getSnapshot(ctx context.Context, sessionID uuid.UUID) (snapshotDTO, error) {
+	var dto snapshotDTO
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, session_id, version, string_size, result_data, checksum, created_at
+		FROM result_history
+		WHERE session_id = ?
+	`, sessionID.String()).Scan(
+		&dto.ID,
+		&dto.SessionID,
+		&dto.Version,
+		&dto.StringSize,
+		&dto.ResultData,
+		&dto.Checksum,
+		&dto.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return snapshotDTO{}, snapshot.ErrNotFound
+		}
+		return snapshotDTO{}, fmt.Errorf("failed to query result snapshot: %w", err)
+	}
+
+	return dto, nil
+}
@@ -0,0 +1,182 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/votes/app"
+	"github.com/73NN0/voting-app/internal/votes/domain/tiebreak"
+	"github.com/google/uuid"
+)
+
+// ======================= DTO ==================== //
+
+// seedDTO représente la table "tie_break_seed"
+type seedDTO struct {
+	QuestionID int          // INTEGER
+	SessionID  string       // TEXT (uuid)
+	Seed       string       // TEXT, hex
+	Commitment string       // TEXT, hex sha256 of the seed
+	CreatedAt  db.Timestamp // TEXT
+}
+
+func (dto seedDTO) toSeed() (tiebreak.Seed, error) {
+	sessionID, err := uuid.Parse(dto.SessionID)
+	if err != nil {
+		return tiebreak.Seed{}, fmt.Errorf("invalid session id: %w", err)
+	}
+
+	return tiebreak.Rehydrate(sessionID, dto.QuestionID, dto.Seed, dto.Commitment, dto.CreatedAt.Time)
+}
+
+// decisionDTO représente la table "tie_break_decision"
+type decisionDTO struct {
+	QuestionID int          // INTEGER
+	SessionID  string       // TEXT (uuid)
+	ChoiceID   int          // INTEGER
+	DecidedBy  string       // TEXT (uuid)
+	DecidedAt  db.Timestamp // TEXT
+}
+
+func (dto decisionDTO) toDecision() (tiebreak.Decision, error) {
+	sessionID, err := uuid.Parse(dto.SessionID)
+	if err != nil {
+		return tiebreak.Decision{}, fmt.Errorf("invalid session id: %w", err)
+	}
+
+	decidedBy, err := uuid.Parse(dto.DecidedBy)
+	if err != nil {
+		return tiebreak.Decision{}, fmt.Errorf("invalid organizer id: %w", err)
+	}
+
+	return tiebreak.Decision{
+		QuestionID: dto.QuestionID,
+		SessionID:  sessionID,
+		ChoiceID:   dto.ChoiceID,
+		DecidedBy:  decidedBy,
+		DecidedAt:  dto.DecidedAt.Time,
+	}, nil
+}
+
+// ========== Repository Implementation ==========
+
+type SqliteTieBreaksRepository struct {
+	db *sql.DB
+}
+
+// Compile-time check
+var _ tiebreak.Repository = (*SqliteTieBreaksRepository)(nil)
+
+func NewSqliteTieBreaksRepository(db *sql.DB) *SqliteTieBreaksRepository {
+	if db == nil {
+		panic("no db in SQL tie-breaks repository !")
+	}
+
+	return &SqliteTieBreaksRepository{db: db}
+}
+
+func (r *SqliteTieBreaksRepository) CommitSeed(ctx context.Context, s tiebreak.Seed) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO tie_break_seed (question_id, session_id, seed, commitment, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (question_id) DO NOTHING
+	`, s.QuestionID(), s.SessionID().String(), s.Value(), s.Commitment(), db.Timestamp{Time: s.CreatedAt()})
+
+	if err != nil {
+		if db.IsSessionFinalized(err) {
+			return app.ErrSessionFinalized
+		}
+		return fmt.Errorf("failed to insert tie-break seed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SqliteTieBreaksRepository) GetSeeds(ctx context.Context, sessionID uuid.UUID) ([]tiebreak.Seed, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT question_id, session_id, seed, commitment, created_at
+		FROM tie_break_seed
+		WHERE session_id = ?
+		ORDER BY question_id ASC
+	`, sessionID.String())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tie-break seeds: %w", err)
+	}
+	defer rows.Close()
+
+	var seeds []tiebreak.Seed
+	for rows.Next() {
+		var dto seedDTO
+		if err := rows.Scan(&dto.QuestionID, &dto.SessionID, &dto.Seed, &dto.Commitment, &dto.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tie-break seed row: %w", err)
+		}
+
+		s, err := dto.toSeed()
+		if err != nil {
+			return nil, err
+		}
+		seeds = append(seeds, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tie-break seed rows: %w", err)
+	}
+
+	return seeds, nil
+}
+
+func (r *SqliteTieBreaksRepository) SaveDecision(ctx context.Context, d tiebreak.Decision) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO tie_break_decision (question_id, session_id, choice_id, decided_by, decided_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, d.QuestionID, d.SessionID.String(), d.ChoiceID, d.DecidedBy.String(), db.Timestamp{Time: d.DecidedAt})
+
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return tiebreak.ErrAlreadyDecided
+		}
+		if db.IsSessionFinalized(err) {
+			return app.ErrSessionFinalized
+		}
+		return fmt.Errorf("failed to insert tie-break decision: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SqliteTieBreaksRepository) GetDecisions(ctx context.Context, sessionID uuid.UUID) ([]tiebreak.Decision, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT question_id, session_id, choice_id, decided_by, decided_at
+		FROM tie_break_decision
+		WHERE session_id = ?
+		ORDER BY question_id ASC
+	`, sessionID.String())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tie-break decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var decisions []tiebreak.Decision
+	for rows.Next() {
+		var dto decisionDTO
+		if err := rows.Scan(&dto.QuestionID, &dto.SessionID, &dto.ChoiceID, &dto.DecidedBy, &dto.DecidedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tie-break decision row: %w", err)
+		}
+
+		d, err := dto.toDecision()
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tie-break decision rows: %w", err)
+	}
+
+	return decisions, nil
+}
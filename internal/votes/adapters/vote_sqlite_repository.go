@@ -0,0 +1,648 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/votes/app"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+// ======================= DTO ==================== //
+
+// voteDTO représente la table "vote"
+type voteDTO struct {
+	ID         string       // TEXT (uuid)
+	UserID     *string      // TEXT (uuid) nullable, secret ballot
+	SessionID  string       // TEXT (uuid)
+	QuestionID int          // INTEGER
+	Weight     int          // INTEGER, shares of the voter
+	CreatedAt  db.Timestamp // TEXT nullable, secret ballot
+}
+
+// voteHistoryDTO représente la table "vote_history"
+type voteHistoryDTO struct {
+	voteDTO
+	ChoiceIDs    string       // TEXT (JSON array)
+	Ranked       bool         // INTEGER (0 or 1)
+	Points       *string      // TEXT (JSON array) nullable
+	Reason       string       // TEXT
+	ReplacedBy   *string      // TEXT nullable
+	SupersededAt db.Timestamp // TEXT
+}
+
+func toVoteDTO(v *vote.Vote) voteDTO {
+	dto := voteDTO{
+		ID:         v.ID().String(),
+		SessionID:  v.SessionID().String(),
+		QuestionID: v.QuestionID(),
+		Weight:     v.Weight(),
+		CreatedAt:  db.Timestamp{Time: v.CreatedAt()}, // a zero time is stored as NULL
+	}
+
+	if !v.Secret() {
+		userID := v.UserID().String()
+		dto.UserID = &userID
+	}
+
+	return dto
+}
+
+// voteChoices are the rows of vote_and_choice of one vote
+type voteChoices struct {
+	ids    []int // by rank when ranked
+	ranked bool
+	points []int // nil unless scored
+}
+
+func (dto voteDTO) toVote(c voteChoices) (vote.Vote, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return vote.Vote{}, fmt.Errorf("invalid vote id: %w", err)
+	}
+
+	// uuid.Nil for a secret ballot
+	var userID uuid.UUID
+	if dto.UserID != nil {
+		if userID, err = uuid.Parse(*dto.UserID); err != nil {
+			return vote.Vote{}, fmt.Errorf("invalid user id: %w", err)
+		}
+	}
+
+	sessionID, err := uuid.Parse(dto.SessionID)
+	if err != nil {
+		return vote.Vote{}, fmt.Errorf("invalid session id: %w", err)
+	}
+
+	return vote.Rehydrate(
+		id,
+		userID,
+		sessionID,
+		dto.QuestionID,
+		c.ids,
+		c.ranked,
+		c.points,
+		dto.Weight,
+		dto.CreatedAt.Time,
+	)
+}
+
+func toVoteHistoryDTO(s vote.Superseded) (voteHistoryDTO, error) {
+	v := s.Vote()
+
+	choiceIDs, err := json.Marshal(v.ChoiceIDs())
+	if err != nil {
+		return voteHistoryDTO{}, fmt.Errorf("failed to marshal choice ids: %w", err)
+	}
+
+	dto := voteHistoryDTO{
+		voteDTO:      toVoteDTO(&v),
+		ChoiceIDs:    string(choiceIDs),
+		Ranked:       v.Ranked(),
+		Reason:       string(s.Reason()),
+		SupersededAt: db.Timestamp{Time: s.SupersededAt()},
+	}
+
+	if points := v.Points(); points != nil {
+		raw, err := json.Marshal(points)
+		if err != nil {
+			return voteHistoryDTO{}, fmt.Errorf("failed to marshal points: %w", err)
+		}
+		encoded := string(raw)
+		dto.Points = &encoded
+	}
+
+	if s.ReplacedBy() != uuid.Nil {
+		replacedBy := s.ReplacedBy().String()
+		dto.ReplacedBy = &replacedBy
+	}
+
+	return dto, nil
+}
+
+func (dto voteHistoryDTO) toSuperseded() (vote.Superseded, error) {
+	c := voteChoices{ranked: dto.Ranked}
+	if err := json.Unmarshal([]byte(dto.ChoiceIDs), &c.ids); err != nil {
+		return vote.Superseded{}, fmt.Errorf("invalid choice ids: %w", err)
+	}
+
+	if dto.Points != nil {
+		if err := json.Unmarshal([]byte(*dto.Points), &c.points); err != nil {
+			return vote.Superseded{}, fmt.Errorf("invalid points: %w", err)
+		}
+	}
+
+	v, err := dto.toVote(c)
+	if err != nil {
+		return vote.Superseded{}, err
+	}
+
+	var replacedBy uuid.UUID
+	if dto.ReplacedBy != nil {
+		if replacedBy, err = uuid.Parse(*dto.ReplacedBy); err != nil {
+			return vote.Superseded{}, fmt.Errorf("invalid replaced_by id: %w", err)
+		}
+	}
+
+	return vote.RehydrateSuperseded(v, vote.Reason(dto.Reason), replacedBy, dto.SupersededAt.Time)
+}
+
+// ========== Repository Implementation ==========
+
+type SqliteVotesRepository struct {
+	db *sql.DB
+	q  querier // db, or the transaction of a finalization
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Compile-time check
+var _ vote.Repository = (*SqliteVotesRepository)(nil)
+
+func NewSqliteVotesRepository(db *sql.DB) *SqliteVotesRepository {
+	if db == nil {
+		panic("no db in SQL votes repository !")
+	}
+
+	return &SqliteVotesRepository{db: db, q: db}
+}
+
+// CastVote writes the vote and its choices in one transaction
+func (r *SqliteVotesRepository) CastVote(ctx context.Context, v vote.Vote) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertVote(ctx, tx, v); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CastBallot writes every vote of the ballot in one transaction
+func (r *SqliteVotesRepository) CastBallot(ctx context.Context, votes []vote.Vote) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, v := range votes {
+		if err := insertVote(ctx, tx, v); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CastSecretBallot writes the participation of userID and the votes without voter in one transaction
+func (r *SqliteVotesRepository) CastSecretBallot(ctx context.Context, userID uuid.UUID, votes []vote.Vote) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertSecretVotes(ctx, tx, userID, votes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertSecretVotes(ctx context.Context, tx *sql.Tx, userID uuid.UUID, votes []vote.Vote) error {
+	for _, v := range votes {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO vote_participation (question_id, user_id, session_id, weight)
+			VALUES (?, ?, ?, ?)
+		`, v.QuestionID(), userID.String(), v.SessionID().String(), v.Weight()); err != nil {
+			if db.IsUniqueViolation(err) {
+				return vote.ErrAlreadyVoted
+			}
+			if db.IsSessionFinalized(err) {
+				return app.ErrSessionFinalized
+			}
+			if db.IsSessionClosed(err) {
+				return app.ErrSessionClosed
+			}
+			return fmt.Errorf("failed to insert participation: %w", err)
+		}
+
+		if err := insertVote(ctx, tx, v.Anonymous()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func insertVote(ctx context.Context, tx *sql.Tx, v vote.Vote) error {
+	dto := toVoteDTO(&v)
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO vote (id, user_id, session_id, question_id, weight, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, dto.ID, dto.UserID, dto.SessionID, dto.QuestionID, dto.Weight, dto.CreatedAt)
+
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return vote.ErrAlreadyVoted
+		}
+		if db.IsSessionFinalized(err) {
+			return app.ErrSessionFinalized
+		}
+		if db.IsSessionClosed(err) {
+			return app.ErrSessionClosed
+		}
+		return fmt.Errorf("failed to insert vote: %w", err)
+	}
+
+	points := v.Points()
+	for i, choiceID := range v.ChoiceIDs() {
+		// rank and points stay NULL when the vote is not ranked or scored
+		var rank, point *int
+		if v.Ranked() {
+			position := i + 1
+			rank = &position
+		}
+		if points != nil {
+			point = &points[i]
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO vote_and_choice (vote_id, choice_id, rank, points)
+			VALUES (?, ?, ?, ?)
+		`, dto.ID, choiceID, rank, point); err != nil {
+			return fmt.Errorf("failed to insert vote choice %d: %w", choiceID, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *SqliteVotesRepository) GetVoteByID(ctx context.Context, id uuid.UUID) (vote.Vote, error) {
+	return r.getVote(ctx, `
+		SELECT id, user_id, session_id, question_id, weight, created_at
+		FROM vote
+		WHERE id = ?
+	`, id.String())
+}
+
+func (r *SqliteVotesRepository) GetUserVote(ctx context.Context, userID uuid.UUID, questionID int) (vote.Vote, error) {
+	return r.getVote(ctx, `
+		SELECT id, user_id, session_id, question_id, weight, created_at
+		FROM vote
+		WHERE user_id = ? AND question_id = ?
+	`, userID.String(), questionID)
+}
+
+func (r *SqliteVotesRepository) getVote(ctx context.Context, query string, args ...any) (vote.Vote, error) {
+	var dto voteDTO
+
+	err := r.q.QueryRowContext(ctx, query, args...).Scan(
+		&dto.ID,
+		&dto.UserID,
+		&dto.SessionID,
+		&dto.QuestionID,
+		&dto.Weight,
+		&dto.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return vote.Vote{}, vote.ErrNotFound
+		}
+		return vote.Vote{}, fmt.Errorf("failed to query vote: %w", err)
+	}
+
+	c, err := r.getChoices(ctx, dto.ID)
+	if err != nil {
+		return vote.Vote{}, err
+	}
+
+	return dto.toVote(c)
+}
+
+// GetVotesByQuestionID returns the votes by date, then by id: secret ballots have no date
+func (r *SqliteVotesRepository) GetVotesByQuestionID(ctx context.Context, questionID int) ([]vote.Vote, error) {
+	votes, err := r.listVotes(ctx, `
+		SELECT id, user_id, session_id, question_id, weight, created_at
+		FROM vote
+		WHERE question_id = ?
+		ORDER BY id ASC -- never fall back on rowid
+	`, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// sorted here, RFC3339Nano strings do not sort lexically
+	slices.SortStableFunc(votes, func(a, b vote.Vote) int {
+		return a.CreatedAt().Compare(b.CreatedAt())
+	})
+
+	return votes, nil
+}
+
+func (r *SqliteVotesRepository) GetVotesBySessionID(ctx context.Context, sessionID uuid.UUID) ([]vote.Vote, error) {
+	return r.listVotes(ctx, `
+		SELECT id, user_id, session_id, question_id, weight, created_at
+		FROM vote
+		WHERE session_id = ?
+		ORDER BY id ASC
+	`, sessionID.String())
+}
+
+func (r *SqliteVotesRepository) GetUserVotesBySessionID(ctx context.Context, userID, sessionID uuid.UUID) ([]vote.Vote, error) {
+	return r.listVotes(ctx, `
+		SELECT id, user_id, session_id, question_id, weight, created_at
+		FROM vote
+		WHERE user_id = ? AND session_id = ?
+		ORDER BY question_id ASC
+	`, userID.String(), sessionID.String())
+}
+
+func (r *SqliteVotesRepository) listVotes(ctx context.Context, query string, args ...any) ([]vote.Vote, error) {
+	rows, err := r.q.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query votes: %w", err)
+	}
+	defer rows.Close()
+
+	var dtos []voteDTO
+	for rows.Next() {
+		var dto voteDTO
+		err := rows.Scan(&dto.ID, &dto.UserID, &dto.SessionID, &dto.QuestionID, &dto.Weight, &dto.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vote row: %w", err)
+		}
+		dtos = append(dtos, dto)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vote rows: %w", err)
+	}
+
+	// choices are fetched once rows are closed: with :memory: a second pooled
+	// connection would open another (empty) database
+	rows.Close()
+
+	votes := make([]vote.Vote, 0, len(dtos))
+	for _, dto := range dtos {
+		c, err := r.getChoices(ctx, dto.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := dto.toVote(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal vote: %w", err)
+		}
+		votes = append(votes, v)
+	}
+
+	return votes, nil
+}
+
+func (r *SqliteVotesRepository) HasVoted(ctx context.Context, userID uuid.UUID, questionID int) (bool, error) {
+	var dummy int
+
+	if err := r.q.QueryRowContext(ctx, `
+		SELECT 1 FROM vote WHERE user_id = ? AND question_id = ?
+		UNION ALL
+		SELECT 1 FROM vote_participation WHERE user_id = ? AND question_id = ?
+		LIMIT 1
+	`, userID.String(), questionID, userID.String(), questionID).Scan(&dummy); err != nil {
+
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to check vote existence : %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *SqliteVotesRepository) HasVotedInSession(ctx context.Context, userID, sessionID uuid.UUID) (bool, error) {
+	var dummy int
+
+	if err := r.q.QueryRowContext(ctx, `
+		SELECT 1 FROM vote WHERE user_id = ? AND session_id = ?
+		UNION ALL
+		SELECT 1 FROM vote_participation WHERE user_id = ? AND session_id = ?
+		LIMIT 1
+	`, userID.String(), sessionID.String(), userID.String(), sessionID.String()).Scan(&dummy); err != nil {
+
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to check session participation : %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *SqliteVotesRepository) CountVoters(ctx context.Context, sessionID uuid.UUID) (int, error) {
+	var count int
+
+	// votes of a secret ballot have no user_id, their voters are in vote_participation
+	err := r.q.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT user_id FROM vote WHERE session_id = ? AND user_id IS NOT NULL
+			UNION
+			SELECT user_id FROM vote_participation WHERE session_id = ?
+		)
+	`, sessionID.String(), sessionID.String()).Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count voters: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *SqliteVotesRepository) WeighVoters(ctx context.Context, sessionID uuid.UUID) (int, error) {
+	var total int
+
+	// every vote of a voter carries the same weight
+	err := r.q.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(weight), 0) FROM (
+			SELECT user_id, MAX(weight) AS weight FROM vote
+			WHERE session_id = ? AND user_id IS NOT NULL
+			GROUP BY user_id
+			UNION
+			SELECT user_id, MAX(weight) AS weight FROM vote_participation
+			WHERE session_id = ?
+			GROUP BY user_id
+		)
+	`, sessionID.String(), sessionID.String()).Scan(&total)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to weigh voters: %w", err)
+	}
+
+	return total, nil
+}
+
+// getChoices returns the choices by rank, ranked is false when no rank is stored
+// and points stay nil when none is stored
+func (r *SqliteVotesRepository) getChoices(ctx context.Context, voteID string) (voteChoices, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT choice_id, rank, points
+		FROM vote_and_choice
+		WHERE vote_id = ?
+		ORDER BY rank ASC, choice_id ASC
+	`, voteID)
+
+	if err != nil {
+		return voteChoices{}, fmt.Errorf("failed to query vote choices: %w", err)
+	}
+	defer rows.Close()
+
+	var c voteChoices
+	for rows.Next() {
+		var id int
+		var rank, points sql.NullInt64
+		if err := rows.Scan(&id, &rank, &points); err != nil {
+			return voteChoices{}, fmt.Errorf("failed to scan vote choice: %w", err)
+		}
+		c.ids = append(c.ids, id)
+		c.ranked = c.ranked || rank.Valid
+		if points.Valid {
+			c.points = append(c.points, int(points.Int64))
+		}
+	}
+
+	return c, rows.Err()
+}
+
+// ===== History =====
+
+func (r *SqliteVotesRepository) ReplaceVotes(ctx context.Context, superseded []vote.Superseded, next []vote.Vote) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceVotes(ctx, tx, superseded, next); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func replaceVotes(ctx context.Context, tx *sql.Tx, superseded []vote.Superseded, next []vote.Vote) error {
+	for _, s := range superseded {
+		if err := archiveVote(ctx, tx, s); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range next {
+		if err := insertVote(ctx, tx, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *SqliteVotesRepository) RevokeVotes(ctx context.Context, superseded []vote.Superseded) error {
+	return r.ReplaceVotes(ctx, superseded, nil)
+}
+
+// archiveVote copies the vote in vote_history then removes it from the counted votes
+func archiveVote(ctx context.Context, tx *sql.Tx, s vote.Superseded) error {
+	dto, err := toVoteHistoryDTO(s)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO vote_history (id, user_id, session_id, question_id, choice_ids, ranked, points, weight, reason, replaced_by, created_at, superseded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, dto.ID, dto.UserID, dto.SessionID, dto.QuestionID, dto.ChoiceIDs, dto.Ranked, dto.Points, dto.Weight, dto.Reason, dto.ReplacedBy, dto.CreatedAt, dto.SupersededAt); err != nil {
+		return fmt.Errorf("failed to archive vote %s: %w", dto.ID, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vote_and_choice WHERE vote_id = ?`, dto.ID); err != nil {
+		if db.IsSessionFinalized(err) {
+			return app.ErrSessionFinalized
+		}
+		return fmt.Errorf("failed to delete vote choices %s: %w", dto.ID, err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM vote WHERE id = ?`, dto.ID)
+	if db.IsSessionFinalized(err) {
+		return app.ErrSessionFinalized
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete vote %s: %w", dto.ID, err)
+	}
+
+	// the vote was already superseded by a concurrent request
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return vote.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SqliteVotesRepository) GetVoteHistory(ctx context.Context, userID, sessionID uuid.UUID) ([]vote.Superseded, error) {
+	rows, err := r.q.QueryContext(ctx, `
+		SELECT id, user_id, session_id, question_id, choice_ids, ranked, points, weight, reason, replaced_by, created_at, superseded_at
+		FROM vote_history
+		WHERE user_id = ? AND session_id = ?
+		ORDER BY rowid ASC -- insertion order, RFC3339Nano strings do not sort lexically
+	`, userID.String(), sessionID.String())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vote history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []vote.Superseded
+	for rows.Next() {
+		var dto voteHistoryDTO
+		err := rows.Scan(
+			&dto.ID,
+			&dto.UserID,
+			&dto.SessionID,
+			&dto.QuestionID,
+			&dto.ChoiceIDs,
+			&dto.Ranked,
+			&dto.Points,
+			&dto.Weight,
+			&dto.Reason,
+			&dto.ReplacedBy,
+			&dto.CreatedAt,
+			&dto.SupersededAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vote history row: %w", err)
+		}
+
+		s, err := dto.toSuperseded()
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal vote history: %w", err)
+		}
+		history = append(history, s)
+	}
+
+	return history, rows.Err()
+}
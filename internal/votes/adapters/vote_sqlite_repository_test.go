@@ -0,0 +1,828 @@
+package adapters_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/votes/adapters"
+	"github.com/73NN0/voting-app/internal/votes/app"
+	"github.com/73NN0/voting-app/internal/votes/domain/receipt"
+	"github.com/73NN0/voting-app/internal/votes/domain/snapshot"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+func mustNewVote(t *testing.T, userID, sessionID uuid.UUID, questionID int, choiceIDs []int) vote.Vote {
+	t.Helper()
+	v, err := vote.NewVote(userID, sessionID, questionID, choiceIDs)
+	if err != nil {
+		t.Fatalf("mustNewVote failed: %v", err)
+	}
+	return v
+}
+
+func TestVoteRepository_CastAndGet(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Un vote avec deux choix
+	v := mustNewVote(t, uuid.New(), uuid.New(), 1, []int{3, 7})
+
+	// WHEN: On le sauvegarde
+	if err := repo.CastVote(ctx, v); err != nil {
+		t.Fatalf("CastVote failed: %v", err)
+	}
+
+	// THEN: On peut le récupérer avec ses choix
+	fetched, err := repo.GetUserVote(ctx, v.UserID(), 1)
+	if err != nil {
+		t.Fatalf("GetUserVote failed: %v", err)
+	}
+
+	if fetched.ID() != v.ID() {
+		t.Errorf("id: got %v, want %v", fetched.ID(), v.ID())
+	}
+
+	if !slices.Equal(fetched.ChoiceIDs(), []int{3, 7}) {
+		t.Errorf("choices: got %v, want [3 7]", fetched.ChoiceIDs())
+	}
+
+	voted, err := repo.HasVoted(ctx, v.UserID(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !voted {
+		t.Error("user should have voted")
+	}
+}
+
+func TestVoteRepository_RankedVoteKeepsOrder(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Un vote classé dont l'ordre n'est pas celui des ids
+	v, err := vote.NewRankedVote(uuid.New(), uuid.New(), 1, []int{7, 2, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: On le sauvegarde puis on le remplace
+	if err := repo.CastVote(ctx, v); err != nil {
+		t.Fatalf("CastVote failed: %v", err)
+	}
+
+	next, superseded, err := v.ReplaceWith([]int{5, 7}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.ReplaceVotes(ctx, []vote.Superseded{superseded}, []vote.Vote{next}); err != nil {
+		t.Fatalf("ReplaceVotes failed: %v", err)
+	}
+
+	// THEN: Le rang de chaque choix est conservé, historique compris
+	fetched, err := repo.GetVoteByID(ctx, next.ID())
+	if err != nil {
+		t.Fatalf("GetVoteByID failed: %v", err)
+	}
+
+	if !fetched.Ranked() || !slices.Equal(fetched.ChoiceIDs(), []int{5, 7}) {
+		t.Errorf("got ranked=%v %v, want ranked [5 7]", fetched.Ranked(), fetched.ChoiceIDs())
+	}
+
+	history, err := repo.GetVoteHistory(ctx, v.UserID(), v.SessionID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 1 {
+		t.Fatalf("got %d history entries, want 1", len(history))
+	}
+
+	old := history[0].Vote()
+	if !old.Ranked() || !slices.Equal(old.ChoiceIDs(), []int{7, 2, 5}) {
+		t.Errorf("history: got ranked=%v %v, want ranked [7 2 5]", old.Ranked(), old.ChoiceIDs())
+	}
+}
+
+func TestVoteRepository_ScoredVoteKeepsPoints(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Un vote noté, sauvegardé puis retiré
+	v, err := vote.NewScoredVote(uuid.New(), uuid.New(), 1, []int{4, 2}, []int{0, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.CastVote(ctx, v); err != nil {
+		t.Fatalf("CastVote failed: %v", err)
+	}
+
+	fetched, err := repo.GetVoteByID(ctx, v.ID())
+	if err != nil {
+		t.Fatalf("GetVoteByID failed: %v", err)
+	}
+
+	if err := repo.RevokeVotes(ctx, []vote.Superseded{fetched.Revoke()}); err != nil {
+		t.Fatalf("RevokeVotes failed: %v", err)
+	}
+
+	history, err := repo.GetVoteHistory(ctx, v.UserID(), v.SessionID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: Chaque choix garde ses points, une note de 0 comprise
+	for _, got := range []vote.Vote{fetched, history[0].Vote()} {
+		if !slices.Equal(got.ChoiceIDs(), []int{2, 4}) || !slices.Equal(got.Points(), []int{5, 0}) {
+			t.Errorf("got %v with points %v, want [2 4] with [5 0]", got.ChoiceIDs(), got.Points())
+		}
+	}
+}
+
+func TestVoteRepository_UniqueVotePerQuestion(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Un user qui a déjà voté
+	userID, sessionID := uuid.New(), uuid.New()
+	if err := repo.CastVote(ctx, mustNewVote(t, userID, sessionID, 1, []int{1})); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: Il vote une deuxième fois pour la même question
+	err = repo.CastVote(ctx, mustNewVote(t, userID, sessionID, 1, []int{2}))
+
+	// THEN: UNIQUE (user_id, question_id)
+	if !errors.Is(err, vote.ErrAlreadyVoted) {
+		t.Errorf("expected %v, got %v", vote.ErrAlreadyVoted, err)
+	}
+
+	votes, err := repo.GetVotesByQuestionID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(votes) != 1 {
+		t.Fatalf("expected 1 vote, got %d", len(votes))
+	}
+}
+
+func TestVoteRepository_GetVotesByQuestionIDSortsByDate(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN deux votes dont les dates, en texte, ne se trient pas dans l'ordre chronologique
+	sessionID := uuid.New()
+	first := mustNewVote(t, uuid.New(), sessionID, 1, []int{1})
+	second := mustNewVote(t, uuid.New(), sessionID, 1, []int{2})
+	dates := []string{"2026-01-01T10:00:00Z", "2026-01-01T10:00:00.5Z"}
+	for i, v := range []vote.Vote{first, second} {
+		if err := repo.CastVote(ctx, v); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := database.Exec(`UPDATE vote SET created_at = ? WHERE id = ?`, dates[i], v.ID().String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// WHEN on liste les votes de la question
+	votes, err := repo.GetVotesByQuestionID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN ils sont dans l'ordre des dates
+	if len(votes) != 2 || votes[0].ID() != first.ID() || votes[1].ID() != second.ID() {
+		t.Errorf("votes are not sorted by date: %v", votes)
+	}
+}
+
+func TestVoteRepository_CastBallotIsAtomic(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Un user qui a déjà voté pour la question 2
+	userID, sessionID := uuid.New(), uuid.New()
+	if err := repo.CastVote(ctx, mustNewVote(t, userID, sessionID, 2, []int{5})); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: Il envoie un bulletin complet (questions 1 et 2)
+	err = repo.CastBallot(ctx, []vote.Vote{
+		mustNewVote(t, userID, sessionID, 1, []int{1}),
+		mustNewVote(t, userID, sessionID, 2, []int{6}),
+	})
+
+	// THEN: Rien n'est enregistré pour la question 1
+	if !errors.Is(err, vote.ErrAlreadyVoted) {
+		t.Fatalf("expected %v, got %v", vote.ErrAlreadyVoted, err)
+	}
+
+	votes, err := repo.GetUserVotesBySessionID(ctx, userID, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(votes) != 1 || votes[0].QuestionID() != 2 {
+		t.Errorf("ballot should have been rolled back, got %d votes", len(votes))
+	}
+}
+
+func TestVoteRepository_ReplaceAndRevokeKeepHistory(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Un vote enregistré
+	userID, sessionID := uuid.New(), uuid.New()
+	first := mustNewVote(t, userID, sessionID, 1, []int{1})
+	if err := repo.CastVote(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: Il est remplacé puis retiré
+	second, changed, err := first.ReplaceWith([]int{2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.ReplaceVotes(ctx, []vote.Superseded{changed}, []vote.Vote{second}); err != nil {
+		t.Fatalf("ReplaceVotes failed: %v", err)
+	}
+
+	current, err := repo.GetUserVote(ctx, userID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(current.ChoiceIDs(), []int{2}) {
+		t.Errorf("current vote: got %v, want [2]", current.ChoiceIDs())
+	}
+
+	if err := repo.RevokeVotes(ctx, []vote.Superseded{current.Revoke()}); err != nil {
+		t.Fatalf("RevokeVotes failed: %v", err)
+	}
+
+	// THEN: Plus aucun vote compté, mais l'historique garde les deux
+	if _, err := repo.GetUserVote(ctx, userID, 1); !errors.Is(err, vote.ErrNotFound) {
+		t.Errorf("expected %v, got %v", vote.ErrNotFound, err)
+	}
+
+	history, err := repo.GetVoteHistory(ctx, userID, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 2 {
+		t.Fatalf("expected 2 superseded votes, got %d", len(history))
+	}
+
+	if history[0].Reason() != vote.ReasonChanged || history[0].ReplacedBy() != second.ID() {
+		t.Errorf("first entry should be changed into %v", second.ID())
+	}
+
+	if history[1].Reason() != vote.ReasonRevoked || !slices.Equal(history[1].Vote().ChoiceIDs(), []int{2}) {
+		t.Errorf("second entry should be the revoked vote")
+	}
+}
+
+func TestVoteRepository_CountVoters(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Deux votants, dont un qui a répondu à deux questions
+	alice, bob, sessionID := uuid.New(), uuid.New(), uuid.New()
+	for _, v := range []vote.Vote{
+		mustNewVote(t, alice, sessionID, 1, []int{1}),
+		mustNewVote(t, alice, sessionID, 2, []int{3}),
+		mustNewVote(t, bob, sessionID, 1, []int{2}),
+		mustNewVote(t, bob, uuid.New(), 5, []int{9}), // autre session
+	} {
+		if err := repo.CastVote(ctx, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// WHEN: On compte les votants de la session
+	count, err := repo.CountVoters(ctx, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: Chaque votant compte une fois
+	if count != 2 {
+		t.Errorf("expected 2 voters, got %d", count)
+	}
+}
+
+func TestVoteRepository_WeighVoters(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	weighted := func(v vote.Vote, weight int) vote.Vote {
+		t.Helper()
+		w, err := v.WithWeight(weight)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+
+	// GIVEN: Alice (3 parts) répond à deux questions, Bob (2 parts) vote en secret
+	alice, bob, sessionID := uuid.New(), uuid.New(), uuid.New()
+	for _, v := range []vote.Vote{
+		weighted(mustNewVote(t, alice, sessionID, 1, []int{1}), 3),
+		weighted(mustNewVote(t, alice, sessionID, 2, []int{3}), 3),
+	} {
+		if err := repo.CastVote(ctx, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	secret := []vote.Vote{weighted(mustNewVote(t, bob, sessionID, 1, []int{2}), 2).Anonymous()}
+	if err := repo.CastSecretBallot(ctx, bob, secret); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: On relit les votes et on pèse les votants
+	votes, err := repo.GetVotesByQuestionID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total, err := repo.WeighVoters(ctx, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: Chaque vote garde son poids et chaque votant compte une fois son poids
+	weights := make([]int, 0, len(votes))
+	for _, v := range votes {
+		weights = append(weights, v.Weight())
+	}
+	slices.Sort(weights)
+
+	if !slices.Equal(weights, []int{2, 3}) {
+		t.Errorf("weights: got %v, want [2 3]", weights)
+	}
+
+	if total != 5 {
+		t.Errorf("voters weight: got %d, want 5", total)
+	}
+}
+
+func TestVoteRepository_SecretBallotHasNoVoter(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Un bulletin secret sur deux questions
+	userID, sessionID := uuid.New(), uuid.New()
+	ballot := []vote.Vote{
+		mustNewVote(t, userID, sessionID, 1, []int{3}),
+		mustNewVote(t, userID, sessionID, 2, []int{5}),
+	}
+
+	// WHEN: On le sauvegarde
+	if err := repo.CastSecretBallot(ctx, userID, ballot); err != nil {
+		t.Fatalf("CastSecretBallot failed: %v", err)
+	}
+
+	// THEN: Les votes sont comptés mais ne portent ni votant ni date
+	votes, err := repo.GetVotesByQuestionID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(votes) != 1 || !votes[0].Secret() || !votes[0].CreatedAt().IsZero() {
+		t.Fatalf("got %+v, want one vote without voter nor date", votes)
+	}
+
+	var linked int
+	if err := database.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM vote WHERE user_id IS NOT NULL OR created_at IS NOT NULL
+	`).Scan(&linked); err != nil {
+		t.Fatal(err)
+	}
+
+	if linked != 0 {
+		t.Errorf("%d votes are linked to their voter", linked)
+	}
+
+	// AND: Seule la participation est connue
+	voted, err := repo.HasVoted(ctx, userID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !voted {
+		t.Error("user should have voted")
+	}
+
+	voters, err := repo.CountVoters(ctx, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if voters != 1 {
+		t.Errorf("voters: got %d, want 1", voters)
+	}
+
+	mine, err := repo.GetUserVotesBySessionID(ctx, userID, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mine) != 0 {
+		t.Errorf("found %d votes of the user, want none", len(mine))
+	}
+
+	// AND: Un second bulletin du même votant est refusé
+	again := []vote.Vote{mustNewVote(t, userID, sessionID, 1, []int{4})}
+	if err := repo.CastSecretBallot(ctx, userID, again); !errors.Is(err, vote.ErrAlreadyVoted) {
+		t.Errorf("expected %v, got %v", vote.ErrAlreadyVoted, err)
+	}
+}
+
+func TestReceiptRepository_KeepsLastReceipt(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteReceiptsRepository(database)
+	ctx := context.Background()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userID, sessionID := uuid.New(), uuid.New()
+
+	// GIVEN: Un premier reçu puis celui du bulletin modifié
+	first, err := receipt.Issue(key, userID, sessionID, false, []vote.Vote{mustNewVote(t, userID, sessionID, 1, []int{3})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := receipt.Issue(key, userID, sessionID, false, []vote.Vote{mustNewVote(t, userID, sessionID, 1, []int{4})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: On les sauvegarde l'un après l'autre
+	for _, rc := range []receipt.Receipt{first, last} {
+		if err := repo.SaveReceipt(ctx, rc); err != nil {
+			t.Fatalf("SaveReceipt failed: %v", err)
+		}
+	}
+
+	// THEN: Seul le dernier reste, toujours vérifiable
+	fetched, err := repo.GetReceipt(ctx, userID, sessionID)
+	if err != nil {
+		t.Fatalf("GetReceipt failed: %v", err)
+	}
+
+	if fetched.ID() != last.ID() {
+		t.Errorf("id: got %v, want %v", fetched.ID(), last.ID())
+	}
+
+	if _, err := receipt.Verify(key.Public().(ed25519.PublicKey), fetched.Payload(), fetched.Signature()); err != nil {
+		t.Errorf("stored receipt no longer verifies: %v", err)
+	}
+
+	if _, err := repo.GetReceipt(ctx, uuid.New(), sessionID); !errors.Is(err, receipt.ErrNotFound) {
+		t.Errorf("expected %v, got %v", receipt.ErrNotFound, err)
+	}
+}
+
+func TestReceiptRepository_CastBallotWithReceipt(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteReceiptsRepository(database)
+	votes := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userID, sessionID := uuid.New(), uuid.New()
+	issue := func(v vote.Vote) receipt.Receipt {
+		t.Helper()
+		rc, err := receipt.Issue(key, userID, sessionID, false, []vote.Vote{v})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rc
+	}
+
+	// GIVEN: Un bulletin déposé avec son reçu
+	first := mustNewVote(t, userID, sessionID, 1, []int{3})
+	cast := issue(first)
+	if err := repo.CastBallot(ctx, cast, []vote.Vote{first}); err != nil {
+		t.Fatalf("CastBallot failed: %v", err)
+	}
+
+	// WHEN: Un second dépôt pour la même question échoue
+	again := mustNewVote(t, userID, sessionID, 1, []int{4})
+	if err := repo.CastBallot(ctx, issue(again), []vote.Vote{again}); !errors.Is(err, vote.ErrAlreadyVoted) {
+		t.Fatalf("expected %v, got %v", vote.ErrAlreadyVoted, err)
+	}
+
+	// THEN: Le reçu reste celui du bulletin compté
+	fetched, err := repo.GetReceipt(ctx, userID, sessionID)
+	if err != nil {
+		t.Fatalf("GetReceipt failed: %v", err)
+	}
+	if fetched.ID() != cast.ID() {
+		t.Errorf("receipt id: got %v, want %v", fetched.ID(), cast.ID())
+	}
+
+	// AND: Un vote remplacé remplace aussi le reçu
+	next, superseded, err := first.ReplaceWith([]int{4}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaced := issue(next)
+	if err := repo.ReplaceBallot(ctx, replaced, []vote.Superseded{superseded}, []vote.Vote{next}); err != nil {
+		t.Fatalf("ReplaceBallot failed: %v", err)
+	}
+	if fetched, err = repo.GetReceipt(ctx, userID, sessionID); err != nil || fetched.ID() != replaced.ID() {
+		t.Errorf("receipt after replace: got %v (%v), want %v", fetched.ID(), err, replaced.ID())
+	}
+	if v, err := votes.GetUserVote(ctx, userID, 1); err != nil || v.ID() != next.ID() {
+		t.Errorf("vote after replace: got %v (%v), want %v", v.ID(), err, next.ID())
+	}
+}
+
+func TestSnapshotRepository_FreezesSession(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	votes := adapters.NewSqliteVotesRepository(database)
+	snapshots := adapters.NewSqliteSnapshotsRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Une session avec un vote puis finalisée
+	sessionID := uuid.New()
+	v := mustNewVote(t, uuid.New(), sessionID, 1, []int{3})
+	if err := votes.CastVote(ctx, v); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := snapshot.New(sessionID, []byte(`{"version":1}`), time.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := snapshots.SaveSnapshot(ctx, snap); err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+
+	// THEN: Le résultat se relit tel quel
+	fetched, err := snapshots.GetSnapshot(ctx, sessionID)
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+
+	if fetched.Checksum() != snap.Checksum() {
+		t.Errorf("checksum: got %s, want %s", fetched.Checksum(), snap.Checksum())
+	}
+
+	// AND: Ni les votes ni le résultat ne peuvent plus changer
+	late := mustNewVote(t, uuid.New(), sessionID, 1, []int{4})
+	if err := votes.CastVote(ctx, late); !errors.Is(err, app.ErrSessionFinalized) {
+		t.Errorf("cast: expected %v, got %v", app.ErrSessionFinalized, err)
+	}
+
+	if err := votes.RevokeVotes(ctx, []vote.Superseded{v.Revoke()}); !errors.Is(err, app.ErrSessionFinalized) {
+		t.Errorf("revoke: expected %v, got %v", app.ErrSessionFinalized, err)
+	}
+
+	if err := snapshots.SaveSnapshot(ctx, snap); !errors.Is(err, snapshot.ErrAlreadyFinalized) {
+		t.Errorf("second snapshot: expected %v, got %v", snapshot.ErrAlreadyFinalized, err)
+	}
+
+	if _, err := database.ExecContext(ctx, `UPDATE result_history SET result_data = '{}'`); err == nil {
+		t.Error("result snapshot was modified")
+	}
+}
+
+func TestVoteRepository_ClosedSessionRefusesBallots(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := adapters.NewSqliteVotesRepository(database)
+	ctx := context.Background()
+
+	// GIVEN: Une session close, pas encore finalisée
+	sessionID := uuid.New()
+	if _, err := database.ExecContext(ctx, `INSERT INTO vote_session (id, title, state) VALUES (?, 'AG', 'closed')`, sessionID.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: Un bulletin vérifié avant la clôture arrive après
+	err = repo.CastVote(ctx, mustNewVote(t, uuid.New(), sessionID, 1, []int{3}))
+
+	// THEN: Il est refusé, qu'il soit secret ou non
+	if !errors.Is(err, app.ErrSessionClosed) {
+		t.Errorf("cast: expected %v, got %v", app.ErrSessionClosed, err)
+	}
+
+	userID := uuid.New()
+	secret := mustNewVote(t, userID, sessionID, 1, []int{3})
+	if err := repo.CastSecretBallot(ctx, userID, []vote.Vote{secret}); !errors.Is(err, app.ErrSessionClosed) {
+		t.Errorf("secret cast: expected %v, got %v", app.ErrSessionClosed, err)
+	}
+}
+
+func TestSnapshotRepository_FinalizeCountsInTransaction(t *testing.T) {
+	database, cleanup, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	votes := adapters.NewSqliteVotesRepository(database)
+	snapshots := adapters.NewSqliteSnapshotsRepository(database)
+	ctx := context.Background()
+
+	sessionID := uuid.New()
+	if err := votes.CastVote(ctx, mustNewVote(t, uuid.New(), sessionID, 1, []int{3})); err != nil {
+		t.Fatal(err)
+	}
+
+	tally := func(counter vote.Counter) (snapshot.Snapshot, error) {
+		counted, err := counter.GetVotesBySessionID(ctx, sessionID)
+		if err != nil {
+			return snapshot.Snapshot{}, err
+		}
+		return snapshot.New(sessionID, []byte(fmt.Sprintf(`{"votes":%d}`, len(counted))), time.Now().UTC())
+	}
+
+	// GIVEN: Un dépouillement qui échoue
+	failed := errors.New("tally failed")
+	if _, err := snapshots.Finalize(ctx, func(vote.Counter) (snapshot.Snapshot, error) { return snapshot.Snapshot{}, failed }); !errors.Is(err, failed) {
+		t.Fatalf("expected %v, got %v", failed, err)
+	}
+
+	// THEN: Rien n'est finalisé
+	if _, err := snapshots.GetSnapshot(ctx, sessionID); !errors.Is(err, snapshot.ErrNotFound) {
+		t.Fatalf("expected %v, got %v", snapshot.ErrNotFound, err)
+	}
+
+	// WHEN: On finalise avec les votes lus dans la transaction
+	snap, err := snapshots.Finalize(ctx, tally)
+	if err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+
+	// THEN: Le résultat stocké compte le vote
+	if got := string(snap.Document()); got != `{"votes":1}` {
+		t.Errorf("document: got %s", got)
+	}
+	if _, err := snapshots.Finalize(ctx, tally); !errors.Is(err, snapshot.ErrAlreadyFinalized) {
+		t.Errorf("second finalize: expected %v, got %v", snapshot.ErrAlreadyFinalized, err)
+	}
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/bulletin"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+// BulletinBoard lists the hash of every counted ballot of a session, sorted
+type BulletinBoard struct {
+	SessionID uuid.UUID       `json:"session_id"`
+	Root      bulletin.Hash   `json:"root"`
+	Ballots   []bulletin.Hash `json:"ballots"`
+}
+
+// InclusionProof lets a voter recompute the root of the board from their ballot
+type InclusionProof struct {
+	VoteID   uuid.UUID       `json:"vote_id"`
+	LeafData string          `json:"leaf_data"`
+	Leaf     bulletin.Hash   `json:"leaf"`
+	Proof    []bulletin.Step `json:"proof"`
+}
+
+// Bulletin builds the board of the session from the counted votes,
+// its root is the bulletin_root of the results.
+func (s *ResultsService) Bulletin(ctx context.Context, sessionID uuid.UUID) (BulletinBoard, error) {
+	board, err := s.board(ctx, sessionID)
+	if err != nil {
+		return BulletinBoard{}, err
+	}
+
+	return BulletinBoard{
+		SessionID: sessionID,
+		Root:      board.Root(),
+		Ballots:   board.Leaves(),
+	}, nil
+}
+
+// InclusionProof returns bulletin.ErrNotOnBoard when the vote is not counted in the session
+func (s *ResultsService) InclusionProof(ctx context.Context, sessionID, voteID uuid.UUID) (InclusionProof, error) {
+	v, err := s.votes.GetVoteByID(ctx, voteID)
+	if err != nil {
+		if errors.Is(err, vote.ErrNotFound) {
+			return InclusionProof{}, bulletin.ErrNotOnBoard
+		}
+		return InclusionProof{}, err
+	}
+
+	if v.SessionID() != sessionID {
+		return InclusionProof{}, bulletin.ErrNotOnBoard
+	}
+
+	board, err := s.board(ctx, sessionID)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+
+	leaf := bulletin.Leaf(v)
+	proof, err := board.Proof(leaf)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+
+	return InclusionProof{
+		VoteID:   voteID,
+		LeafData: bulletin.LeafData(v),
+		Leaf:     leaf,
+		Proof:    proof,
+	}, nil
+}
+
+func (s *ResultsService) board(ctx context.Context, sessionID uuid.UUID) (bulletin.Board, error) {
+	votes, err := s.sessionVotes(ctx, sessionID)
+	if err != nil {
+		return bulletin.Board{}, err
+	}
+
+	return bulletin.Build(votes), nil
+}
+
+// sessionVotes returns ErrSessionNotFound rather than no vote for an unknown session
+func (s *ResultsService) sessionVotes(ctx context.Context, sessionID uuid.UUID) ([]vote.Vote, error) {
+	if _, err := s.sessions.GetQuorum(ctx, sessionID); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	return s.votes.GetVotesBySessionID(ctx, sessionID)
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/bulletin"
+	"github.com/73NN0/voting-app/internal/votes/domain/export"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+var ErrQuestionRequired = errors.New("blt export needs a question")
+
+// BallotsExport is the JSON document of the exported ballots
+type BallotsExport struct {
+	SessionID    uuid.UUID       `json:"session_id"`
+	BulletinRoot bulletin.Hash   `json:"bulletin_root"`
+	Ballots      []export.Ballot `json:"ballots"`
+}
+
+// ExportResults writes the results of the session as csv or json,
+// blt only carries ballots and returns export.ErrUnsupported.
+func (s *ResultsService) ExportResults(ctx context.Context, sessionID uuid.UUID, format export.Format, w io.Writer) error {
+	if format == export.FormatBLT {
+		return export.ErrUnsupported
+	}
+
+	result, err := s.SessionResults(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if format == export.FormatJSON {
+		return json.NewEncoder(w).Encode(result)
+	}
+
+	return writeResultsCSV(w, result)
+}
+
+// ExportBallots writes the anonymized ballots of the session, blt needs the id of a ranked question
+func (s *ResultsService) ExportBallots(ctx context.Context, sessionID uuid.UUID, format export.Format, questionID int, w io.Writer) error {
+	votes, err := s.sessionVotes(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	questions, err := s.questions.ListSessionQuestions(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("list session questions: %w", err)
+	}
+
+	ballots := export.Ballots(votes)
+
+	switch format {
+	case export.FormatJSON:
+		return json.NewEncoder(w).Encode(BallotsExport{
+			SessionID:    sessionID,
+			BulletinRoot: bulletin.Build(votes).Root(),
+			Ballots:      ballots,
+		})
+	case export.FormatBLT:
+		q, err := sessionQuestion(questions, questionID)
+		if err != nil {
+			return err
+		}
+		return export.WriteBLT(w, q, ballots)
+	default:
+		return export.WriteBallotsCSV(w, questions, ballots)
+	}
+}
+
+func sessionQuestion(questions []vote.Question, questionID int) (vote.Question, error) {
+	if questionID == 0 {
+		return vote.Question{}, ErrQuestionRequired
+	}
+
+	for _, q := range questions {
+		if q.ID == questionID {
+			return q, nil
+		}
+	}
+
+	return vote.Question{}, ErrQuestionNotFound
+}
+
+// writeResultsCSV writes one line per choice of each question
+func writeResultsCSV(w io.Writer, result SessionResult) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"question_id", "question", "kind", "method", "status", "choice_id", "choice", "votes", "headcount", "percentage", "points", "winner"})
+
+	for _, q := range result.Questions {
+		points := make(map[int]int, len(q.Scores))
+		for _, sc := range q.Scores {
+			points[sc.ChoiceID] = sc.Points
+		}
+
+		for _, c := range q.Choices {
+			p := ""
+			if v, ok := points[c.ChoiceID]; ok {
+				p = strconv.Itoa(v)
+			}
+
+			cw.Write([]string{
+				strconv.Itoa(q.QuestionID),
+				q.Text,
+				string(q.Kind),
+				string(q.Method),
+				string(q.Status),
+				strconv.Itoa(c.ChoiceID),
+				c.Text,
+				strconv.Itoa(c.Votes),
+				strconv.Itoa(c.Headcount),
+				strconv.FormatFloat(c.Percentage, 'f', 2, 64),
+				p,
+				strconv.FormatBool(slices.Contains(q.Winners, c.ChoiceID)),
+			})
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/snapshot"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+var ErrSessionFinalized = errors.New("vote session is finalized")
+
+// SessionCloser gives the sessions context a way to end a session.
+// Close returns ErrSessionNotFound when the session doesn't exist, ErrSessionNotOpen
+// when it never opened, and leaves the end date of a session already closed.
+type SessionCloser interface {
+	Close(ctx context.Context, sessionID uuid.UUID) error
+}
+
+// FinalResult is the document stored in the snapshot of a finalized session
+type FinalResult struct {
+	Version     int           `json:"version"`
+	SessionID   uuid.UUID     `json:"session_id"`
+	FinalizedAt time.Time     `json:"finalized_at"`
+	Result      SessionResult `json:"result"`
+}
+
+// FinalizeSession closes the session, tallies it and stores the result snapshot.
+// Once the snapshot is stored the questions, choices and votes of the session are frozen.
+// It returns ErrNotOrganizer when organizerID does not organize the session, and
+// ErrTiePending while a tie waits for an organizer, the session stays closed.
+func (s *ResultsService) FinalizeSession(ctx context.Context, organizerID, sessionID uuid.UUID) (snapshot.Snapshot, error) {
+	organizer, err := s.sessions.IsOrganizer(ctx, sessionID, organizerID)
+	if err != nil {
+		return snapshot.Snapshot{}, fmt.Errorf("is organizer: %w", err)
+	}
+
+	if !organizer {
+		return snapshot.Snapshot{}, ErrNotOrganizer
+	}
+
+	return s.Finalize(ctx, sessionID)
+}
+
+// Finalize is FinalizeSession without the organizer check, for the scheduler ending the
+// sessions on time
+func (s *ResultsService) Finalize(ctx context.Context, sessionID uuid.UUID) (snapshot.Snapshot, error) {
+	_, err := s.snapshots.GetSnapshot(ctx, sessionID)
+	if err == nil {
+		return snapshot.Snapshot{}, snapshot.ErrAlreadyFinalized
+	}
+
+	if !errors.Is(err, snapshot.ErrNotFound) {
+		return snapshot.Snapshot{}, err
+	}
+
+	// no ballot can be cast once the session is closed, the triggers of vote refuse them
+	if err := s.closer.Close(ctx, sessionID); err != nil {
+		if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrSessionNotOpen) {
+			return snapshot.Snapshot{}, err
+		}
+		return snapshot.Snapshot{}, fmt.Errorf("close session: %w", err)
+	}
+
+	// the votes are read in the transaction of the snapshot: none can be missing from it
+	return s.snapshots.Finalize(ctx, func(votes vote.Counter) (snapshot.Snapshot, error) {
+		result, err := s.tallySession(ctx, votes, sessionID)
+		if err != nil {
+			return snapshot.Snapshot{}, err
+		}
+
+		// the organizers decide once the session is closed, then finalize again
+		for _, qr := range result.Questions {
+			if qr.Status == vote.StatusTied && qr.TieBreak.Rule == vote.TieBreakOrganizer {
+				return snapshot.Snapshot{}, ErrTiePending
+			}
+		}
+
+		finalizedAt := s.clock.Now()
+
+		document, err := json.Marshal(FinalResult{
+			Version:     snapshot.Version,
+			SessionID:   sessionID,
+			FinalizedAt: finalizedAt,
+			Result:      result,
+		})
+		if err != nil {
+			return snapshot.Snapshot{}, fmt.Errorf("marshal result: %w", err)
+		}
+
+		return snapshot.New(sessionID, document, finalizedAt)
+	})
+}
+
+// GetSnapshot returns the stored result of a finalized session, snapshot.ErrNotFound otherwise
+func (s *ResultsService) GetSnapshot(ctx context.Context, sessionID uuid.UUID) (snapshot.Snapshot, error) {
+	return s.snapshots.GetSnapshot(ctx, sessionID)
+}
+
+// GetFinalResult decodes the snapshot of a finalized session
+func (s *ResultsService) GetFinalResult(ctx context.Context, sessionID uuid.UUID) (FinalResult, error) {
+	snap, err := s.snapshots.GetSnapshot(ctx, sessionID)
+	if err != nil {
+		return FinalResult{}, err
+	}
+
+	var final FinalResult
+	if err := json.Unmarshal(snap.Document(), &final); err != nil {
+		return FinalResult{}, fmt.Errorf("decode result snapshot: %w", err)
+	}
+
+	return final, nil
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// LiveNotifier is told when the counted ballots of a session change
+type LiveNotifier interface {
+	BallotsChanged(sessionID uuid.UUID)
+}
+
+// LiveUpdate is pushed to the live stream of a session, the running tallies
+// only come with it when the session shows live results and the viewer may see them.
+type LiveUpdate struct {
+	SessionID    uuid.UUID      `json:"session_id"`
+	Participants int            `json:"participants"`
+	Voters       int            `json:"voters"`
+	Turnout      float64        `json:"turnout"`
+	Open         bool           `json:"open"`
+	Results      *SessionResult `json:"results,omitempty"`
+}
+
+// LiveUpdate : viewerID is uuid.Nil for an anonymous caller
+func (s *ResultsService) LiveUpdate(ctx context.Context, sessionID, viewerID uuid.UUID) (LiveUpdate, error) {
+	live, err := s.sessions.HasLiveResults(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return LiveUpdate{}, err
+		}
+		return LiveUpdate{}, fmt.Errorf("get live results: %w", err)
+	}
+
+	open, err := s.sessions.IsOpen(ctx, sessionID)
+	if err != nil {
+		return LiveUpdate{}, fmt.Errorf("is session open: %w", err)
+	}
+
+	if live {
+		if live, err = s.resultsVisible(ctx, sessionID, viewerID); err != nil {
+			return LiveUpdate{}, err
+		}
+	}
+
+	if live {
+		result, err := s.SessionResults(ctx, sessionID)
+		if err != nil {
+			return LiveUpdate{}, err
+		}
+
+		return LiveUpdate{
+			SessionID:    sessionID,
+			Participants: result.Participants,
+			Voters:       result.Voters,
+			Turnout:      result.Turnout,
+			Open:         open,
+			Results:      &result,
+		}, nil
+	}
+
+	participants, err := s.sessions.CountParticipants(ctx, sessionID)
+	if err != nil {
+		return LiveUpdate{}, fmt.Errorf("count participants: %w", err)
+	}
+
+	voters, err := s.votes.CountVoters(ctx, sessionID)
+	if err != nil {
+		return LiveUpdate{}, err
+	}
+
+	return LiveUpdate{
+		SessionID:    sessionID,
+		Participants: participants,
+		Voters:       voters,
+		Turnout:      turnout(voters, participants),
+		Open:         open,
+	}, nil
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Discrepancy is a value of the result that differs between the snapshot and the recount,
+// Path looks like questions[0].choices[1].votes and the values are JSON.
+type Discrepancy struct {
+	Path      string          `json:"path"`
+	Stored    json.RawMessage `json:"stored,omitempty"`
+	Recounted json.RawMessage `json:"recounted,omitempty"`
+}
+
+// RecountReport compares the snapshot of a finalized session with its votes counted again
+type RecountReport struct {
+	SessionID     uuid.UUID     `json:"session_id"`
+	SnapshotID    uuid.UUID     `json:"snapshot_id"`
+	FinalizedAt   time.Time     `json:"finalized_at"`
+	Checksum      string        `json:"checksum"`
+	Integrity     string        `json:"integrity,omitempty"` // why the stored document fails its checksum
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+// Matches tells if the snapshot is intact and the recount gives the same result
+func (r RecountReport) Matches() bool {
+	return r.Integrity == "" && len(r.Discrepancies) == 0
+}
+
+// Recount tallies the stored votes of a finalized session again and compares the result
+// to its snapshot, which is read even when it no longer matches its checksum.
+// The votes of a finalized session are frozen, the participants are not:
+// a participant added since shows up in participants, turnout and quorum.
+func (s *ResultsService) Recount(ctx context.Context, sessionID uuid.UUID) (RecountReport, error) {
+	stored, err := s.snapshots.GetStoredSnapshot(ctx, sessionID)
+	if err != nil {
+		return RecountReport{}, err
+	}
+
+	report := RecountReport{
+		SessionID:     sessionID,
+		SnapshotID:    stored.ID,
+		FinalizedAt:   stored.CreatedAt,
+		Checksum:      stored.Checksum,
+		Discrepancies: []Discrepancy{},
+	}
+
+	if err := stored.Verify(); err != nil {
+		report.Integrity = err.Error()
+	}
+
+	recounted, err := s.tallySession(ctx, s.votes, sessionID)
+	if err != nil {
+		return RecountReport{}, err
+	}
+
+	var final struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(stored.Document, &final); err != nil {
+		report.Integrity = fmt.Sprintf("unreadable document: %v", err)
+		return report, nil
+	}
+
+	current, err := json.Marshal(recounted)
+	if err != nil {
+		return RecountReport{}, fmt.Errorf("marshal recount: %w", err)
+	}
+
+	var a, b any
+	if err := json.Unmarshal(final.Result, &a); err != nil {
+		report.Integrity = fmt.Sprintf("unreadable result: %v", err)
+		return report, nil
+	}
+	if err := json.Unmarshal(current, &b); err != nil {
+		return RecountReport{}, fmt.Errorf("decode recount: %w", err)
+	}
+
+	diff("", a, b, &report.Discrepancies)
+	return report, nil
+}
+
+// diff walks both JSON values in a stable order, objects by key and arrays by index
+func diff(path string, stored, recounted any, out *[]Discrepancy) {
+	switch a := stored.(type) {
+	case map[string]any:
+		if b, ok := recounted.(map[string]any); ok {
+			keys := slices.Collect(maps.Keys(a))
+			for k := range b {
+				if _, ok := a[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			slices.Sort(keys)
+
+			for _, k := range keys {
+				diff(jsonPath(path, k), a[k], b[k], out)
+			}
+			return
+		}
+	case []any:
+		if b, ok := recounted.([]any); ok {
+			for i := range max(len(a), len(b)) {
+				var x, y any
+				if i < len(a) {
+					x = a[i]
+				}
+				if i < len(b) {
+					y = b[i]
+				}
+				diff(fmt.Sprintf("%s[%d]", path, i), x, y, out)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(stored, recounted) {
+		*out = append(*out, Discrepancy{Path: path, Stored: raw(stored), Recounted: raw(recounted)})
+	}
+}
+
+func jsonPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// raw is nil for a value missing on one side
+func raw(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, _ := json.Marshal(v)
+	return data
+}
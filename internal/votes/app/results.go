@@ -0,0 +1,338 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/votes/domain/bulletin"
+	"github.com/73NN0/voting-app/internal/votes/domain/snapshot"
+	"github.com/73NN0/voting-app/internal/votes/domain/tally"
+	"github.com/73NN0/voting-app/internal/votes/domain/tiebreak"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+// ChoiceResult : Votes and Percentage are weighted, Headcount is the number of ballots
+type ChoiceResult struct {
+	ChoiceID   int     `json:"choice_id"`
+	Text       string  `json:"text"`
+	Votes      int     `json:"votes"`
+	Headcount  int     `json:"headcount"`
+	Percentage float64 `json:"percentage"`
+}
+
+// QuestionResult : for a ranked question, Choices are the first preferences and the
+// details of the method are filled : Rounds for irv, Pairwise with StrongestPaths
+// for schulze or with Pairs for ranked_pairs, Seats, Quota and Transfers for stv
+// where Winners are the elected choices. For the sum method, Choices count the
+// ballots scoring each choice and Scores hold the points.
+// Ballots is the number of ballots and Weight their total weight.
+// A tie broken by the rule of the question leaves its evidence in TieBreak and its winner in Winners.
+type QuestionResult struct {
+	QuestionID     int                  `json:"question_id"`
+	Text           string               `json:"text"`
+	Kind           vote.Kind            `json:"kind"`
+	Method         vote.Method          `json:"method"`
+	Ballots        int                  `json:"ballots"`
+	Weight         int                  `json:"weight"`
+	Choices        []ChoiceResult       `json:"choices"`
+	Winners        []int                `json:"winners"`
+	Rounds         []tally.IRVRound     `json:"rounds,omitempty"`
+	Pairwise       *tally.Matrix        `json:"pairwise,omitempty"`
+	StrongestPaths *tally.Matrix        `json:"strongest_paths,omitempty"`
+	Pairs          []tally.Pair         `json:"pairs,omitempty"`
+	Seats          int                  `json:"seats,omitempty"`
+	Quota          int                  `json:"quota,omitempty"`
+	Transfers      []tally.STVRound     `json:"transfers,omitempty"`
+	Scores         []tally.Score        `json:"scores,omitempty"`
+	TieBreak       *tiebreak.Resolution `json:"tie_break,omitempty"`
+	Threshold      vote.Threshold       `json:"threshold"`
+	Status         vote.Status          `json:"status"`
+}
+
+type QuorumResult struct {
+	Kind     vote.QuorumKind `json:"kind"`
+	Value    int             `json:"value"`
+	Required int             `json:"required"` // voters needed
+	Reached  bool            `json:"reached"`
+}
+
+// SessionResult : the quorum counts voters, the weighted fields count their shares
+type SessionResult struct {
+	SessionID          uuid.UUID        `json:"session_id"`
+	Participants       int              `json:"participants"`
+	Voters             int              `json:"voters"`
+	Turnout            float64          `json:"turnout"` // percentage of participants who voted
+	ParticipantsWeight int              `json:"participants_weight"`
+	VotersWeight       int              `json:"voters_weight"`
+	WeightedTurnout    float64          `json:"weighted_turnout"` // percentage of the weight which voted
+	Quorum             QuorumResult     `json:"quorum"`
+	BulletinRoot       bulletin.Hash    `json:"bulletin_root"` // root of the board of the counted ballots
+	Questions          []QuestionResult `json:"questions"`
+}
+
+type ResultsService struct {
+	votes     vote.Repository
+	snapshots snapshot.Repository
+	tiebreaks tiebreak.Repository
+	questions QuestionReader
+	sessions  SessionChecker
+	closer    SessionCloser
+	clock     clock.Clock
+}
+
+// NewResultsService : clk dates the finalizations, the same clock as the session checker
+func NewResultsService(voteRepository vote.Repository, snapshotRepository snapshot.Repository, tieBreakRepository tiebreak.Repository, questions QuestionReader, sessions SessionChecker, closer SessionCloser, clk clock.Clock) *ResultsService {
+	if voteRepository == nil {
+		panic("missing vote repository")
+	}
+
+	if snapshotRepository == nil {
+		panic("missing snapshot repository")
+	}
+
+	if tieBreakRepository == nil {
+		panic("missing tie-break repository")
+	}
+
+	if closer == nil {
+		panic("no Session closing")
+	}
+
+	if questions == nil {
+		panic("no Question access")
+	}
+
+	if sessions == nil {
+		panic("no Session access")
+	}
+
+	if clk == nil {
+		panic("missing clock")
+	}
+
+	return &ResultsService{
+		votes:     voteRepository,
+		snapshots: snapshotRepository,
+		tiebreaks: tieBreakRepository,
+		questions: questions,
+		sessions:  sessions,
+		closer:    closer,
+		clock:     clk,
+	}
+}
+
+// SessionResults tallies every question of the session from the current votes,
+// a finalized session returns the result of its snapshot.
+// Every question fails with quorum_not_reached when the session quorum is not reached.
+func (s *ResultsService) SessionResults(ctx context.Context, sessionID uuid.UUID) (SessionResult, error) {
+	final, err := s.GetFinalResult(ctx, sessionID)
+	if err == nil {
+		return final.Result, nil
+	}
+
+	if !errors.Is(err, snapshot.ErrNotFound) {
+		return SessionResult{}, err
+	}
+
+	return s.tallySession(ctx, s.votes, sessionID)
+}
+
+// tallySession counts the votes read from votes, the transaction of the snapshot when finalizing
+func (s *ResultsService) tallySession(ctx context.Context, votes vote.Counter, sessionID uuid.UUID) (SessionResult, error) {
+	quorum, err := s.sessions.GetQuorum(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return SessionResult{}, err
+		}
+		return SessionResult{}, fmt.Errorf("get quorum: %w", err)
+	}
+
+	participants, err := s.sessions.CountParticipants(ctx, sessionID)
+	if err != nil {
+		return SessionResult{}, fmt.Errorf("count participants: %w", err)
+	}
+
+	voters, err := votes.CountVoters(ctx, sessionID)
+	if err != nil {
+		return SessionResult{}, err
+	}
+
+	participantsWeight, err := s.sessions.WeighParticipants(ctx, sessionID)
+	if err != nil {
+		return SessionResult{}, fmt.Errorf("weigh participants: %w", err)
+	}
+
+	votersWeight, err := votes.WeighVoters(ctx, sessionID)
+	if err != nil {
+		return SessionResult{}, err
+	}
+
+	questions, err := s.questions.ListSessionQuestions(ctx, sessionID)
+	if err != nil {
+		return SessionResult{}, fmt.Errorf("list session questions: %w", err)
+	}
+
+	counted, err := votes.GetVotesBySessionID(ctx, sessionID)
+	if err != nil {
+		return SessionResult{}, err
+	}
+
+	evidence, err := s.tieBreakEvidence(ctx, sessionID)
+	if err != nil {
+		return SessionResult{}, err
+	}
+
+	result := SessionResult{
+		SessionID:          sessionID,
+		Participants:       participants,
+		Voters:             voters,
+		Turnout:            turnout(voters, participants),
+		ParticipantsWeight: participantsWeight,
+		VotersWeight:       votersWeight,
+		WeightedTurnout:    turnout(votersWeight, participantsWeight),
+		Quorum: QuorumResult{
+			Kind:     quorum.Kind,
+			Value:    quorum.Value,
+			Required: quorum.Required(participants),
+			Reached:  quorum.Reached(voters, participants),
+		},
+		BulletinRoot: bulletin.Build(counted).Root(),
+		Questions:    make([]QuestionResult, 0, len(questions)),
+	}
+
+	for _, q := range questions {
+		qr, err := s.questionResult(ctx, votes, q)
+		if err != nil {
+			return SessionResult{}, err
+		}
+
+		breakTie(&qr, q, evidence[q.ID])
+		qr.Status = status(qr, q, participantsWeight, result.Quorum.Reached)
+		result.Questions = append(result.Questions, qr)
+	}
+
+	return result, nil
+}
+
+func (s *ResultsService) questionResult(ctx context.Context, counter vote.Counter, q vote.Question) (QuestionResult, error) {
+	votes, err := counter.GetVotesByQuestionID(ctx, q.ID)
+	if err != nil {
+		return QuestionResult{}, err
+	}
+
+	ballots := make([]tally.Ballot, 0, len(votes))
+	for _, v := range votes {
+		ballots = append(ballots, tally.Ballot{ChoiceIDs: v.ChoiceIDs(), Points: v.Points(), Weight: v.Weight()})
+	}
+
+	choiceIDs := make([]int, 0, len(q.Choices))
+	texts := make(map[int]string, len(q.Choices))
+	for _, c := range q.Choices {
+		choiceIDs = append(choiceIDs, c.ID)
+		texts[c.ID] = c.Text
+	}
+
+	qr := QuestionResult{
+		QuestionID: q.ID,
+		Text:       q.Text,
+		Kind:       q.Kind,
+		Method:     q.Method,
+		Ballots:    len(ballots),
+		Weight:     tally.Weigh(ballots),
+		Threshold:  q.Threshold,
+	}
+
+	counts := tally.FirstPreferences(choiceIDs, ballots)
+
+	switch q.Method {
+	case vote.MethodIRV:
+		irv := tally.InstantRunoff(choiceIDs, ballots)
+		qr.Winners = irv.Winners
+		qr.Rounds = irv.Rounds
+	case vote.MethodSchulze:
+		schulze := tally.Schulze(choiceIDs, ballots)
+		qr.Winners = schulze.Winners
+		qr.Pairwise = &schulze.Pairwise
+		qr.StrongestPaths = &schulze.StrongestPaths
+	case vote.MethodRankedPairs:
+		rp := tally.RankedPairs(choiceIDs, ballots)
+		qr.Winners = rp.Winners
+		qr.Pairwise = &rp.Pairwise
+		qr.Pairs = rp.Pairs
+	case vote.MethodSTV:
+		stv := tally.STV(choiceIDs, ballots, q.Seats)
+		qr.Winners = stv.Elected
+		qr.Seats = stv.Seats
+		qr.Quota = stv.Quota
+		qr.Transfers = stv.Rounds
+	case vote.MethodSum:
+		sum := tally.Sum(choiceIDs, ballots)
+		qr.Winners = sum.Winners
+		qr.Scores = sum.Scores
+		counts = tally.Plurality(choiceIDs, ballots).Counts
+	default:
+		plurality := tally.Plurality(choiceIDs, ballots)
+		qr.Winners = plurality.Winners
+		counts = plurality.Counts
+	}
+
+	qr.Choices = make([]ChoiceResult, 0, len(counts))
+	for _, c := range counts {
+		qr.Choices = append(qr.Choices, ChoiceResult{
+			ChoiceID:   c.ChoiceID,
+			Text:       texts[c.ChoiceID],
+			Votes:      c.Votes,
+			Headcount:  c.Headcount,
+			Percentage: c.Percentage,
+		})
+	}
+
+	return qr, nil
+}
+
+// status : without threshold a question passes when the method fills every seat without tie,
+// with a threshold the single winner must pass it, on the weight of the ballots and of the
+// participants. A tie waiting for its tie-break is tied.
+// Only plurality questions take a threshold: their counts are the votes of the winner,
+// the first preferences of a ranked question are not.
+func status(qr QuestionResult, q vote.Question, participantsWeight int, quorumReached bool) vote.Status {
+	if !quorumReached {
+		return vote.StatusQuorumNotReached
+	}
+
+	if qr.Ballots > 0 && qr.TieBreak != nil && qr.TieBreak.Pending {
+		return vote.StatusTied
+	}
+
+	seats := max(q.Seats, 1)
+	if qr.Ballots == 0 || len(qr.Winners) != seats {
+		return vote.StatusFailed
+	}
+
+	if q.Threshold == vote.ThresholdNone || q.Threshold == "" || q.Method != vote.MethodPlurality {
+		return vote.StatusPassed
+	}
+
+	votes := 0
+	for _, c := range qr.Choices {
+		if c.ChoiceID == qr.Winners[0] {
+			votes = c.Votes
+		}
+	}
+
+	if q.Threshold.Passes(votes, qr.Weight, participantsWeight) {
+		return vote.StatusPassed
+	}
+	return vote.StatusFailed
+}
+
+func turnout(voters, participants int) float64 {
+	if participants == 0 {
+		return 0
+	}
+	return math.Round(float64(voters)*10000/float64(participants)) / 100
+}
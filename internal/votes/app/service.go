@@ -0,0 +1,420 @@
+package app
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/receipt"
+	"github.com/73NN0/voting-app/internal/votes/domain/tiebreak"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrSessionNotFound  = errors.New("vote session not found")
+	ErrSessionClosed    = errors.New("vote session is closed")
+	ErrSessionNotOpen   = errors.New("vote session is not open yet")
+	ErrNotParticipant   = errors.New("user is not a participant of this vote session")
+	ErrSecretBallot     = errors.New("votes of a secret ballot cannot be read or changed")
+)
+
+// SessionChecker gives access to the sessions context.
+// IsOpen and IsClosed return ErrSessionNotFound when the session doesn't exist,
+// a session being prepared is neither open nor closed.
+type SessionChecker interface {
+	Exists(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	IsParticipant(ctx context.Context, sessionID, userID uuid.UUID) (bool, error)
+	IsOpen(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	IsClosed(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	CountParticipants(ctx context.Context, sessionID uuid.UUID) (int, error)
+	// GetQuorum returns ErrSessionNotFound when the session doesn't exist
+	GetQuorum(ctx context.Context, sessionID uuid.UUID) (vote.Quorum, error)
+	// IsSecret returns ErrSessionNotFound when the session doesn't exist
+	IsSecret(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	// HasLiveResults returns ErrSessionNotFound when the session doesn't exist
+	HasLiveResults(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	// GetVisibility returns ErrSessionNotFound when the session doesn't exist
+	GetVisibility(ctx context.Context, sessionID uuid.UUID) (vote.Visibility, error)
+	IsOrganizer(ctx context.Context, sessionID, userID uuid.UUID) (bool, error)
+	// GetWeight returns ErrNotParticipant when the user is not a participant of the session
+	GetWeight(ctx context.Context, sessionID, userID uuid.UUID) (int, error)
+	WeighParticipants(ctx context.Context, sessionID uuid.UUID) (int, error)
+}
+
+// QuestionReader gives access to the questions context.
+// It returns ErrQuestionNotFound when the question doesn't exist.
+type QuestionReader interface {
+	GetQuestion(ctx context.Context, questionID int) (vote.Question, error)
+	ListSessionQuestions(ctx context.Context, sessionID uuid.UUID) ([]vote.Question, error)
+}
+
+type Service struct {
+	votes      vote.Repository
+	receipts   receipt.Repository
+	tiebreaks  tiebreak.Repository
+	signingKey ed25519.PrivateKey
+	questions  QuestionReader
+	sessions   SessionChecker
+	live       LiveNotifier
+}
+
+// NewService : signingKey signs the receipts of the ballots
+func NewService(voteRepository vote.Repository, receiptRepository receipt.Repository, tieBreakRepository tiebreak.Repository, signingKey ed25519.PrivateKey, questions QuestionReader, sessions SessionChecker, live LiveNotifier) *Service {
+	if voteRepository == nil {
+		panic("missing vote repository")
+	}
+
+	if receiptRepository == nil {
+		panic("missing receipt repository")
+	}
+
+	if tieBreakRepository == nil {
+		panic("missing tie-break repository")
+	}
+
+	if len(signingKey) != ed25519.PrivateKeySize {
+		panic("invalid receipt signing key")
+	}
+
+	if questions == nil {
+		panic("no Question access")
+	}
+
+	if sessions == nil {
+		panic("no Session access")
+	}
+
+	if live == nil {
+		panic("no live notifier")
+	}
+
+	return &Service{
+		votes:      voteRepository,
+		receipts:   receiptRepository,
+		tiebreaks:  tieBreakRepository,
+		signingKey: signingKey,
+		questions:  questions,
+		sessions:   sessions,
+		live:       live,
+	}
+}
+
+// CastVote records the ballot of userID for a question and returns the vote id with the
+// signed receipt of this vote, which replaces the previous receipt of userID in the session
+func (s *Service) CastVote(ctx context.Context, userID uuid.UUID, answer vote.Answer) (uuid.UUID, receipt.Receipt, error) {
+	q, err := s.questions.GetQuestion(ctx, answer.QuestionID)
+	if err != nil {
+		return uuid.Nil, receipt.Receipt{}, err
+	}
+
+	if err := s.checkCanVote(ctx, q.SessionID, userID); err != nil {
+		return uuid.Nil, receipt.Receipt{}, err
+	}
+
+	v, err := vote.NewVoteFor(userID, q, answer)
+	if err != nil {
+		return uuid.Nil, receipt.Receipt{}, err
+	}
+
+	weighted, err := s.weigh(ctx, q.SessionID, userID, []vote.Vote{v})
+	if err != nil {
+		return uuid.Nil, receipt.Receipt{}, err
+	}
+
+	secret, err := s.isSecret(ctx, q.SessionID)
+	if err != nil {
+		return uuid.Nil, receipt.Receipt{}, err
+	}
+
+	if err := s.commitSeeds(ctx, q); err != nil {
+		return uuid.Nil, receipt.Receipt{}, err
+	}
+
+	rc, err := s.castBallot(ctx, userID, q.SessionID, secret, weighted)
+	if err != nil {
+		return uuid.Nil, receipt.Receipt{}, err
+	}
+	s.live.BallotsChanged(q.SessionID)
+
+	return weighted[0].ID(), rc, nil
+}
+
+// SubmitBallot records the answers of userID to every question of the session at once
+// and returns the signed receipt of the ballot.
+// In a secret ballot session the votes are stored, and returned, without their voter.
+func (s *Service) SubmitBallot(ctx context.Context, userID, sessionID uuid.UUID, answers []vote.Answer) ([]vote.Vote, receipt.Receipt, error) {
+	if err := s.checkCanVote(ctx, sessionID, userID); err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	questions, err := s.questions.ListSessionQuestions(ctx, sessionID)
+	if err != nil {
+		return nil, receipt.Receipt{}, fmt.Errorf("list session questions: %w", err)
+	}
+
+	votes, err := vote.NewBallot(userID, sessionID, questions, answers)
+	if err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	if votes, err = s.weigh(ctx, sessionID, userID, votes); err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	secret, err := s.isSecret(ctx, sessionID)
+	if err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	if err := s.commitSeeds(ctx, questions...); err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	if secret {
+		for i, v := range votes {
+			votes[i] = v.Anonymous()
+		}
+	}
+
+	rc, err := s.castBallot(ctx, userID, sessionID, secret, votes)
+	if err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+	s.live.BallotsChanged(sessionID)
+
+	return votes, rc, nil
+}
+
+// ReplaceBallot changes the ballot of userID while the session is open.
+// Previous votes are kept in the history and no longer counted.
+// A secret ballot cannot be found back, so it cannot be replaced.
+// The receipt of the new ballot replaces the previous one.
+func (s *Service) ReplaceBallot(ctx context.Context, userID, sessionID uuid.UUID, answers []vote.Answer) ([]vote.Vote, receipt.Receipt, error) {
+	if err := s.checkCanVote(ctx, sessionID, userID); err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	if err := s.checkNotSecret(ctx, sessionID); err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	questions, err := s.questions.ListSessionQuestions(ctx, sessionID)
+	if err != nil {
+		return nil, receipt.Receipt{}, fmt.Errorf("list session questions: %w", err)
+	}
+
+	current, err := s.votes.GetUserVotesBySessionID(ctx, userID, sessionID)
+	if err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	next, superseded, err := vote.ReplaceBallot(current, userID, sessionID, questions, answers)
+	if err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	if next, err = s.weigh(ctx, sessionID, userID, next); err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	if err := s.commitSeeds(ctx, questions...); err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	rc, err := s.issueReceipt(userID, sessionID, false, next)
+	if err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+
+	if err := s.receipts.ReplaceBallot(ctx, rc, superseded, next); err != nil {
+		return nil, receipt.Receipt{}, err
+	}
+	s.live.BallotsChanged(sessionID)
+
+	return next, rc, nil
+}
+
+// RevokeBallot withdraws the ballot of userID while the session is open.
+// The receipt of the ballot is replaced by a receipt without entries.
+func (s *Service) RevokeBallot(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if err := s.checkCanVote(ctx, sessionID, userID); err != nil {
+		return err
+	}
+
+	if err := s.checkNotSecret(ctx, sessionID); err != nil {
+		return err
+	}
+
+	current, err := s.votes.GetUserVotesBySessionID(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	superseded, err := vote.RevokeBallot(current)
+	if err != nil {
+		return err
+	}
+
+	rc, err := s.issueReceipt(userID, sessionID, false, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.receipts.RevokeBallot(ctx, rc, superseded); err != nil {
+		return err
+	}
+	s.live.BallotsChanged(sessionID)
+
+	return nil
+}
+
+func (s *Service) GetBallotHistory(ctx context.Context, userID, sessionID uuid.UUID) ([]vote.Superseded, error) {
+	return s.votes.GetVoteHistory(ctx, userID, sessionID)
+}
+
+// GetBallot returns the votes of userID in the session
+func (s *Service) GetBallot(ctx context.Context, userID, sessionID uuid.UUID) ([]vote.Vote, error) {
+	if err := s.checkNotSecret(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	return s.votes.GetUserVotesBySessionID(ctx, userID, sessionID)
+}
+
+// GetReceipt returns the receipt of the last ballot of userID in the session
+func (s *Service) GetReceipt(ctx context.Context, userID, sessionID uuid.UUID) (receipt.Receipt, error) {
+	return s.receipts.GetReceipt(ctx, userID, sessionID)
+}
+
+// PublicKey verifies the receipts signed by the service
+func (s *Service) PublicKey() ed25519.PublicKey {
+	return s.signingKey.Public().(ed25519.PublicKey)
+}
+
+// VerifyReceipt checks that payload was signed by this service
+func (s *Service) VerifyReceipt(payload, signature []byte) (receipt.Payload, error) {
+	return receipt.Verify(s.PublicKey(), payload, signature)
+}
+
+func (s *Service) GetVoteByID(ctx context.Context, voteID uuid.UUID) (vote.Vote, error) {
+	return s.votes.GetVoteByID(ctx, voteID)
+}
+
+func (s *Service) GetUserVote(ctx context.Context, userID uuid.UUID, questionID int) (vote.Vote, error) {
+	return s.votes.GetUserVote(ctx, userID, questionID)
+}
+
+func (s *Service) ListVotesByQuestionID(ctx context.Context, questionID int) ([]vote.Vote, error) {
+	return s.votes.GetVotesByQuestionID(ctx, questionID)
+}
+
+// checkCanVote : the session must be open and the user one of its participants
+func (s *Service) checkCanVote(ctx context.Context, sessionID, userID uuid.UUID) error {
+	open, err := s.sessions.IsOpen(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("check session: %w", err)
+	}
+
+	if !open {
+		return s.notOpen(ctx, sessionID)
+	}
+
+	ok, err := s.sessions.IsParticipant(ctx, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("check participant: %w", err)
+	}
+
+	if !ok {
+		return ErrNotParticipant
+	}
+
+	return nil
+}
+
+// notOpen tells a session being prepared from a closed one
+func (s *Service) notOpen(ctx context.Context, sessionID uuid.UUID) error {
+	closed, err := s.sessions.IsClosed(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("check session: %w", err)
+	}
+
+	if closed {
+		return ErrSessionClosed
+	}
+
+	return ErrSessionNotOpen
+}
+
+// weigh gives the votes the current weight of userID in the session
+func (s *Service) weigh(ctx context.Context, sessionID, userID uuid.UUID, votes []vote.Vote) ([]vote.Vote, error) {
+	weight, err := s.sessions.GetWeight(ctx, sessionID, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotParticipant) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get weight: %w", err)
+	}
+
+	weighted := make([]vote.Vote, 0, len(votes))
+	for _, v := range votes {
+		w, err := v.WithWeight(weight)
+		if err != nil {
+			return nil, err
+		}
+		weighted = append(weighted, w)
+	}
+
+	return weighted, nil
+}
+
+// castBallot stores the votes with their receipt: a ballot is never cast without it
+func (s *Service) castBallot(ctx context.Context, userID, sessionID uuid.UUID, secret bool, votes []vote.Vote) (receipt.Receipt, error) {
+	rc, err := s.issueReceipt(userID, sessionID, secret, votes)
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+
+	if secret {
+		err = s.receipts.CastSecretBallot(ctx, rc, votes)
+	} else {
+		err = s.receipts.CastBallot(ctx, rc, votes)
+	}
+
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+
+	return rc, nil
+}
+
+func (s *Service) issueReceipt(userID, sessionID uuid.UUID, secret bool, votes []vote.Vote) (receipt.Receipt, error) {
+	rc, err := receipt.Issue(s.signingKey, userID, sessionID, secret, votes)
+	if err != nil {
+		return receipt.Receipt{}, fmt.Errorf("issue receipt: %w", err)
+	}
+	return rc, nil
+}
+
+func (s *Service) isSecret(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	secret, err := s.sessions.IsSecret(ctx, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("check secret ballot: %w", err)
+	}
+	return secret, nil
+}
+
+func (s *Service) checkNotSecret(ctx context.Context, sessionID uuid.UUID) error {
+	secret, err := s.isSecret(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if secret {
+		return ErrSecretBallot
+	}
+
+	return nil
+}
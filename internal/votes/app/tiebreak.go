@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/snapshot"
+	"github.com/73NN0/voting-app/internal/votes/domain/tiebreak"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionOpen  = errors.New("vote session is still open")
+	ErrNotOrganizer = errors.New("user is not an organizer of this vote session")
+	ErrTiePending   = errors.New("a tie is waiting for the decision of an organizer")
+)
+
+// TieBreakSeed is the published commitment of a question with the random rule,
+// Seed is only given once the session is closed
+type TieBreakSeed struct {
+	QuestionID  int       `json:"question_id"`
+	Commitment  string    `json:"commitment"`
+	Seed        string    `json:"seed,omitempty"`
+	CommittedAt time.Time `json:"committed_at"`
+}
+
+// commitSeeds draws the seed of the questions with the random rule which have none yet,
+// before their first ballot is stored
+func (s *Service) commitSeeds(ctx context.Context, questions ...vote.Question) error {
+	for _, q := range questions {
+		if q.TieBreak != vote.TieBreakRandom {
+			continue
+		}
+
+		seed, err := tiebreak.NewSeed(q.SessionID, q.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.tiebreaks.CommitSeed(ctx, seed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TieBreakSeeds lists the commitments of the session
+func (s *ResultsService) TieBreakSeeds(ctx context.Context, sessionID uuid.UUID) ([]TieBreakSeed, error) {
+	closed, err := s.sessions.IsClosed(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("is session closed: %w", err)
+	}
+
+	seeds, err := s.tiebreaks.GetSeeds(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TieBreakSeed, 0, len(seeds))
+	for _, seed := range seeds {
+		ts := TieBreakSeed{
+			QuestionID:  seed.QuestionID(),
+			Commitment:  seed.Commitment(),
+			CommittedAt: seed.CreatedAt(),
+		}
+		if closed {
+			ts.Seed = seed.Value()
+		}
+		result = append(result, ts)
+	}
+
+	return result, nil
+}
+
+// DecideTie records the choice of organizerID for a tied question with the organizer rule.
+// The session must be closed and not finalized yet.
+func (s *ResultsService) DecideTie(ctx context.Context, sessionID uuid.UUID, questionID int, organizerID uuid.UUID, choiceID int) (tiebreak.Decision, error) {
+	q, err := s.questions.GetQuestion(ctx, questionID)
+	if err != nil {
+		return tiebreak.Decision{}, err
+	}
+
+	if q.SessionID != sessionID {
+		return tiebreak.Decision{}, ErrQuestionNotFound
+	}
+
+	organizer, err := s.sessions.IsOrganizer(ctx, sessionID, organizerID)
+	if err != nil {
+		return tiebreak.Decision{}, fmt.Errorf("is organizer: %w", err)
+	}
+
+	if !organizer {
+		return tiebreak.Decision{}, ErrNotOrganizer
+	}
+
+	closed, err := s.sessions.IsClosed(ctx, sessionID)
+	if err != nil {
+		return tiebreak.Decision{}, fmt.Errorf("is session closed: %w", err)
+	}
+
+	if !closed {
+		return tiebreak.Decision{}, ErrSessionOpen
+	}
+
+	_, err = s.snapshots.GetSnapshot(ctx, sessionID)
+	if err == nil {
+		return tiebreak.Decision{}, ErrSessionFinalized
+	}
+
+	if !errors.Is(err, snapshot.ErrNotFound) {
+		return tiebreak.Decision{}, err
+	}
+
+	qr, err := s.questionResult(ctx, s.votes, q)
+	if err != nil {
+		return tiebreak.Decision{}, err
+	}
+
+	var tied []int
+	if qr.Ballots > 0 {
+		tied = qr.Winners
+	}
+
+	d, err := tiebreak.NewDecision(q, tied, choiceID, organizerID)
+	if err != nil {
+		return tiebreak.Decision{}, err
+	}
+
+	if err := s.tiebreaks.SaveDecision(ctx, d); err != nil {
+		return tiebreak.Decision{}, err
+	}
+
+	return d, nil
+}
+
+// tieBreakEvidence gathers the seeds and decisions of the session by question
+func (s *ResultsService) tieBreakEvidence(ctx context.Context, sessionID uuid.UUID) (map[int]tiebreak.Evidence, error) {
+	closed, err := s.sessions.IsClosed(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("is session closed: %w", err)
+	}
+
+	seeds, err := s.tiebreaks.GetSeeds(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions, err := s.tiebreaks.GetDecisions(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence := make(map[int]tiebreak.Evidence)
+	for _, seed := range seeds {
+		e := evidence[seed.QuestionID()]
+		e.Seed = &seed
+		e.Revealed = closed
+		evidence[seed.QuestionID()] = e
+	}
+
+	for _, d := range decisions {
+		e := evidence[d.QuestionID]
+		e.Decision = &d
+		evidence[d.QuestionID] = e
+	}
+
+	return evidence, nil
+}
+
+// breakTie applies the rule of a single seat question tied between its winners,
+// the winner of the tie-break becomes the only winner
+func breakTie(qr *QuestionResult, q vote.Question, evidence tiebreak.Evidence) {
+	if q.Method == vote.MethodSTV || q.TieBreak == vote.TieBreakNone || q.TieBreak == "" {
+		return
+	}
+
+	if qr.Ballots == 0 || len(qr.Winners) < 2 {
+		return
+	}
+
+	resolution := tiebreak.Resolve(q, qr.Winners, evidence)
+	qr.TieBreak = &resolution
+
+	if !resolution.Pending {
+		qr.Winners = []int{resolution.Winner}
+	}
+}
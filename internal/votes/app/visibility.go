@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+var ErrResultsHidden = errors.New("results are not visible")
+
+// CheckResultsVisible returns ErrResultsHidden when viewerID may not see the results,
+// the final result or the ballots of the session. viewerID is uuid.Nil for an anonymous caller.
+func (s *ResultsService) CheckResultsVisible(ctx context.Context, sessionID, viewerID uuid.UUID) error {
+	visible, err := s.resultsVisible(ctx, sessionID, viewerID)
+	if err != nil {
+		return err
+	}
+
+	if !visible {
+		return ErrResultsHidden
+	}
+
+	return nil
+}
+
+func (s *ResultsService) resultsVisible(ctx context.Context, sessionID, viewerID uuid.UUID) (bool, error) {
+	visibility, err := s.sessions.GetVisibility(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return false, err
+		}
+		return false, fmt.Errorf("get visibility: %w", err)
+	}
+
+	closed, err := s.sessions.IsClosed(ctx, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("is session closed: %w", err)
+	}
+
+	var viewer vote.Viewer
+	if viewerID != uuid.Nil && (visibility == vote.VisibilityOrganizers || visibility == vote.VisibilityVoters) {
+		if viewer, err = s.viewer(ctx, sessionID, viewerID); err != nil {
+			return false, err
+		}
+	}
+
+	return visibility.Allows(viewer, !closed), nil
+}
+
+func (s *ResultsService) viewer(ctx context.Context, sessionID, userID uuid.UUID) (vote.Viewer, error) {
+	organizer, err := s.sessions.IsOrganizer(ctx, sessionID, userID)
+	if err != nil {
+		return vote.Viewer{}, fmt.Errorf("is organizer: %w", err)
+	}
+
+	participant, err := s.sessions.IsParticipant(ctx, sessionID, userID)
+	if err != nil {
+		return vote.Viewer{}, fmt.Errorf("is participant: %w", err)
+	}
+
+	voted, err := s.votes.HasVotedInSession(ctx, userID, sessionID)
+	if err != nil {
+		return vote.Viewer{}, err
+	}
+
+	return vote.Viewer{Organizer: organizer, Participant: participant, Voted: voted}, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/common/db"
+	questions "github.com/73NN0/voting-app/internal/questions/adapters"
+	sessions "github.com/73NN0/voting-app/internal/sessions/adapters"
+	"github.com/73NN0/voting-app/internal/votes/adapters"
+	"github.com/73NN0/voting-app/internal/votes/app"
+)
+
+// openResults gives the subcommands the results of the database at dsn, close it with cleanup
+func openResults(dsn string) (*app.ResultsService, func(), error) {
+	database, cleanup, err := db.OpenSQLite(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = db.InitializeSchemas(database); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	sessionsChecker := adapters.NewSessionCheckerInProcess(sessions.NewSqliteSessionRepository(database), clock.System{})
+	results := app.NewResultsService(
+		adapters.NewSqliteVotesRepository(database),
+		adapters.NewSqliteSnapshotsRepository(database),
+		adapters.NewSqliteTieBreaksRepository(database),
+		adapters.NewQuestionReaderInProcess(
+			questions.NewSqliteQuestionsRepository(database),
+			questions.NewSqliteChoicesRepositoy(database),
+		),
+		sessionsChecker,
+		sessionsChecker,
+		clock.System{},
+	)
+
+	return results, cleanup, nil
+}
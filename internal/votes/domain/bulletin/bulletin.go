@@ -0,0 +1,171 @@
+package bulletin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+)
+
+// The bulletin board publishes one hash per counted ballot in a Merkle tree per session,
+// the root is published with the results. Leaves are sorted by hash, not by the order the
+// votes were cast, so the board tells nothing about who voted first.
+//
+// Hashes follow RFC 6962: leaf = sha256(0x00 || data), node = sha256(0x01 || left || right),
+// an odd node is promoted to the next level as is.
+
+var (
+	ErrNotOnBoard  = errors.New("ballot is not on the bulletin board")
+	ErrInvalidHash = errors.New("invalid hash")
+)
+
+type Hash [sha256.Size]byte
+
+func (h Hash) String() string { return hex.EncodeToString(h[:]) }
+
+func (h Hash) MarshalText() ([]byte, error) { return []byte(h.String()), nil }
+
+func (h *Hash) UnmarshalText(text []byte) error {
+	parsed, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = parsed
+	return nil
+}
+
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	raw, err := hex.DecodeString(s)
+	if err != nil || len(raw) != len(h) {
+		return Hash{}, ErrInvalidHash
+	}
+	copy(h[:], raw)
+	return h, nil
+}
+
+// LeafData is the line voters hash to find their ballot on the board:
+// "v1:<vote id>:<question id>:<choice ids>:<ranked|unranked>:<points>"
+// with choice ids in preference order and lists comma separated.
+// A weighted vote is "v2:<same fields>:<weight>", votes of weight 1 keep the v1 line.
+func LeafData(v vote.Vote) string {
+	ranked := "unranked"
+	if v.Ranked() {
+		ranked = "ranked"
+	}
+
+	if v.Weight() > 1 {
+		return fmt.Sprintf("v2:%s:%d:%s:%s:%s:%d", v.ID(), v.QuestionID(), join(v.ChoiceIDs()), ranked, join(v.Points()), v.Weight())
+	}
+
+	return fmt.Sprintf("v1:%s:%d:%s:%s:%s", v.ID(), v.QuestionID(), join(v.ChoiceIDs()), ranked, join(v.Points()))
+}
+
+func Leaf(v vote.Vote) Hash {
+	return sha256.Sum256(append([]byte{0x00}, LeafData(v)...))
+}
+
+func node(left, right Hash) Hash {
+	data := make([]byte, 0, 1+2*len(left))
+	data = append(data, 0x01)
+	data = append(data, left[:]...)
+	data = append(data, right[:]...)
+	return sha256.Sum256(data)
+}
+
+// Board is the Merkle tree of the counted ballots of a session
+type Board struct {
+	levels [][]Hash // levels[0] are the sorted leaves, the last level is the root
+}
+
+// Step is a sibling on the path from a leaf to the root
+type Step struct {
+	Hash Hash `json:"hash"`
+	Left bool `json:"left"` // the sibling goes on the left of the running hash
+}
+
+func Build(votes []vote.Vote) Board {
+	leaves := make([]Hash, 0, len(votes))
+	for _, v := range votes {
+		leaves = append(leaves, Leaf(v))
+	}
+	slices.SortFunc(leaves, func(a, b Hash) int { return bytes.Compare(a[:], b[:]) })
+
+	levels := [][]Hash{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([]Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, node(level[i], level[i+1]))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+
+	return Board{levels: levels}
+}
+
+// Root of an empty board is the hash of nothing
+func (b Board) Root() Hash {
+	top := b.levels[len(b.levels)-1]
+	if len(top) == 0 {
+		return sha256.Sum256(nil)
+	}
+	return top[0]
+}
+
+func (b Board) Leaves() []Hash {
+	return slices.Clone(b.levels[0])
+}
+
+// Proof returns the path of the leaf to the root
+func (b Board) Proof(leaf Hash) ([]Step, error) {
+	i, found := slices.BinarySearchFunc(b.levels[0], leaf, func(a, b Hash) int { return bytes.Compare(a[:], b[:]) })
+	if !found {
+		return nil, ErrNotOnBoard
+	}
+
+	var proof []Step
+	for _, level := range b.levels[:len(b.levels)-1] {
+		switch {
+		case i%2 == 1:
+			proof = append(proof, Step{Hash: level[i-1], Left: true})
+		case i+1 < len(level):
+			proof = append(proof, Step{Hash: level[i+1]})
+		}
+		// a promoted odd node has no sibling at this level
+		i /= 2
+	}
+
+	return proof, nil
+}
+
+// Verify tells if the proof leads from leaf to root
+func Verify(leaf Hash, proof []Step, root Hash) bool {
+	h := leaf
+	for _, s := range proof {
+		if s.Left {
+			h = node(s.Hash, h)
+		} else {
+			h = node(h, s.Hash)
+		}
+	}
+	return h == root
+}
+
+func join(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
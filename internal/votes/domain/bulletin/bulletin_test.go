@@ -0,0 +1,113 @@
+package bulletin_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/bulletin"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+func mustVotes(t *testing.T, n int) []vote.Vote {
+	t.Helper()
+	sessionID := uuid.New()
+	votes := make([]vote.Vote, 0, n)
+	for i := range n {
+		v, err := vote.NewRankedVote(uuid.New(), sessionID, 1, []int{i%3 + 1, (i+1)%3 + 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		votes = append(votes, v)
+	}
+	return votes
+}
+
+func TestBoard_EveryBallotHasAProof(t *testing.T) {
+	for n := 1; n <= 7; n++ {
+		t.Run(fmt.Sprintf("%d bulletins", n), func(t *testing.T) {
+			// GIVEN un tableau construit avec n bulletins
+			votes := mustVotes(t, n)
+			board := bulletin.Build(votes)
+
+			if got := len(board.Leaves()); got != n {
+				t.Fatalf("expected %d leaves, got %d", n, got)
+			}
+
+			for _, v := range votes {
+				// WHEN un votant demande la preuve de son bulletin
+				proof, err := board.Proof(bulletin.Leaf(v))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				// THEN elle mène à la racine publiée
+				if !bulletin.Verify(bulletin.Leaf(v), proof, board.Root()) {
+					t.Errorf("proof of %s doesn't lead to the root", v.ID())
+				}
+			}
+		})
+	}
+}
+
+func TestBoard_DetectsTampering(t *testing.T) {
+	votes := mustVotes(t, 5)
+	board := bulletin.Build(votes)
+	root := board.Root()
+
+	proof, err := board.Proof(bulletin.Leaf(votes[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// GIVEN le même bulletin avec un autre classement
+	changed, err := vote.Rehydrate(votes[0].ID(), votes[0].UserID(), votes[0].SessionID(), 1, []int{2, 1}, true, nil, 1, votes[0].CreatedAt())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN la preuve ne mène plus à la racine
+	if bulletin.Verify(bulletin.Leaf(changed), proof, root) {
+		t.Error("changed ballot verified against the root")
+	}
+
+	// AND un bulletin modifié change la racine
+	if bulletin.Build(append(votes[1:], changed)).Root() == root {
+		t.Error("root didn't change with the ballot")
+	}
+
+	// AND un bulletin absent n'a pas de preuve
+	if _, err := board.Proof(bulletin.Leaf(changed)); !errors.Is(err, bulletin.ErrNotOnBoard) {
+		t.Errorf("expected %v, got %v", bulletin.ErrNotOnBoard, err)
+	}
+}
+
+func TestBoard_OrderDoesNotMatter(t *testing.T) {
+	votes := mustVotes(t, 4)
+	reversed := []vote.Vote{votes[3], votes[2], votes[1], votes[0]}
+
+	if bulletin.Build(votes).Root() != bulletin.Build(reversed).Root() {
+		t.Error("root depends on the order the ballots were cast")
+	}
+
+	if got, want := bulletin.Build(nil).Root(), bulletin.Hash(sha256.Sum256(nil)); got != want {
+		t.Errorf("empty board: expected %s, got %s", want, got)
+	}
+}
+
+func TestParseHash(t *testing.T) {
+	h := bulletin.Leaf(mustVotes(t, 1)[0])
+
+	got, err := bulletin.ParseHash(h.String())
+	if err != nil || got != h {
+		t.Fatalf("round trip: got %s, %v", got, err)
+	}
+
+	for _, s := range []string{"", "zz", h.String()[:10]} {
+		if _, err := bulletin.ParseHash(s); !errors.Is(err, bulletin.ErrInvalidHash) {
+			t.Errorf("%q: expected %v, got %v", s, bulletin.ErrInvalidHash, err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatBLT  Format = "blt" // OpenSTV ballot file, ranked ballots of one question
+)
+
+var (
+	ErrUnknownFormat    = errors.New("unknown export format")
+	ErrUnsupported      = errors.New("format not supported for this export")
+	ErrNotRanked        = errors.New("blt export needs a ranked question")
+	ErrUnknownCandidate = errors.New("ballot ranks a choice of another question")
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatCSV, FormatJSON, FormatBLT:
+		return f, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// ContentType of the downloaded file
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSON:
+		return "application/json"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Ballot is an exported vote: neither the voter nor the date leave the app
+type Ballot struct {
+	VoteID     uuid.UUID `json:"vote_id"`
+	QuestionID int       `json:"question_id"`
+	Ranked     bool      `json:"ranked"`
+	ChoiceIDs  []int     `json:"choice_ids"` // preference order when ranked
+	Points     []int     `json:"points,omitempty"`
+	Weight     int       `json:"weight"`
+}
+
+func Ballots(votes []vote.Vote) []Ballot {
+	ballots := make([]Ballot, 0, len(votes))
+	for _, v := range votes {
+		ballots = append(ballots, Ballot{
+			VoteID:     v.ID(),
+			QuestionID: v.QuestionID(),
+			Ranked:     v.Ranked(),
+			ChoiceIDs:  v.ChoiceIDs(),
+			Points:     v.Points(),
+			Weight:     v.Weight(),
+		})
+	}
+	return ballots
+}
+
+// WriteBallotsCSV writes one line per choice of each ballot, position is the rank
+// for a ranked ballot and points stay empty unless the ballot is scored.
+// The weight of the ballot is repeated on each line.
+func WriteBallotsCSV(w io.Writer, questions []vote.Question, ballots []Ballot) error {
+	texts := make(map[int]string)
+	for _, q := range questions {
+		for _, c := range q.Choices {
+			texts[c.ID] = c.Text
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"vote_id", "question_id", "position", "choice_id", "choice", "points", "weight"})
+
+	for _, b := range ballots {
+		for i, id := range b.ChoiceIDs {
+			points := ""
+			if i < len(b.Points) {
+				points = strconv.Itoa(b.Points[i])
+			}
+
+			cw.Write([]string{
+				b.VoteID.String(),
+				strconv.Itoa(b.QuestionID),
+				strconv.Itoa(i + 1),
+				strconv.Itoa(id),
+				texts[id],
+				points,
+				strconv.Itoa(max(b.Weight, 1)),
+			})
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteBLT writes the ranked ballots of q in the OpenSTV/BLT format:
+//
+//	<candidates> <seats>
+//	<weight> <candidate> <candidate> ... 0   (one line per ballot)
+//	0
+//	"<candidate name>"                (one line per candidate)
+//	"<title>"
+//
+// Candidates are numbered from 1 in the order of the choices of the question.
+func WriteBLT(w io.Writer, q vote.Question, ballots []Ballot) error {
+	if q.Kind != vote.KindRanked {
+		return ErrNotRanked
+	}
+
+	candidates := make(map[int]int, len(q.Choices))
+	for i, c := range q.Choices {
+		candidates[c.ID] = i + 1
+	}
+
+	seats := 1
+	if q.Method == vote.MethodSTV && q.Seats > 0 {
+		seats = q.Seats
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %d\n", len(q.Choices), seats)
+
+	for _, ballot := range ballots {
+		if ballot.QuestionID != q.ID {
+			continue
+		}
+
+		b.WriteString(strconv.Itoa(max(ballot.Weight, 1)))
+		for _, id := range ballot.ChoiceIDs {
+			n, ok := candidates[id]
+			if !ok {
+				return fmt.Errorf("%w: choice %d", ErrUnknownCandidate, id)
+			}
+			fmt.Fprintf(&b, " %d", n)
+		}
+		b.WriteString(" 0\n")
+	}
+
+	b.WriteString("0\n")
+	for _, c := range q.Choices {
+		fmt.Fprintf(&b, "%s\n", quote(c.Text))
+	}
+	fmt.Fprintf(&b, "%s\n", quote(q.Text))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// quote : the format has no escape, a double quote in a name becomes a simple one
+func quote(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
@@ -0,0 +1,149 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/export"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+func rankedQuestion() vote.Question {
+	return vote.Question{
+		ID:     2,
+		Text:   `Le "meilleur" ?`,
+		Kind:   vote.KindRanked,
+		Method: vote.MethodSTV,
+		Seats:  2,
+		Choices: []vote.Choice{
+			{ID: 10, Text: "x", OrderNum: 1},
+			{ID: 11, Text: "y", OrderNum: 2},
+			{ID: 12, Text: "z", OrderNum: 3},
+		},
+	}
+}
+
+func mustRanked(t *testing.T, questionID int, choiceIDs ...int) vote.Vote {
+	t.Helper()
+	v, err := vote.NewRankedVote(uuid.New(), uuid.New(), questionID, choiceIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestBallots_AreAnonymous(t *testing.T) {
+	v := mustRanked(t, 2, 12, 10)
+
+	b := export.Ballots([]vote.Vote{v})
+
+	if len(b) != 1 || b[0].VoteID != v.ID() || !b[0].Ranked || b[0].QuestionID != 2 {
+		t.Fatalf("unexpected ballots %+v", b)
+	}
+}
+
+func TestWriteBLT(t *testing.T) {
+	q := rankedQuestion()
+
+	// GIVEN deux bulletins classés et un bulletin d'une autre question
+	ballots := export.Ballots([]vote.Vote{
+		mustRanked(t, 2, 12, 10),
+		mustRanked(t, 2, 11),
+		mustRanked(t, 3, 99),
+	})
+
+	// WHEN on les exporte en BLT
+	var buf bytes.Buffer
+	if err := export.WriteBLT(&buf, q, ballots); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN les candidats sont numérotés dans l'ordre des choix
+	want := "3 2\n" +
+		"1 3 1 0\n" +
+		"1 2 0\n" +
+		"0\n" +
+		"\"x\"\n\"y\"\n\"z\"\n" +
+		"\"Le 'meilleur' ?\"\n"
+
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteBLT_Errors(t *testing.T) {
+	plurality := rankedQuestion()
+	plurality.Kind = vote.KindChoice
+
+	tests := []struct {
+		name    string
+		q       vote.Question
+		ballots []export.Ballot
+		wantErr error
+	}{
+		{"question non classée", plurality, nil, export.ErrNotRanked},
+		{"choix inconnu", rankedQuestion(), export.Ballots([]vote.Vote{mustRanked(t, 2, 10, 42)}), export.ErrUnknownCandidate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := export.WriteBLT(&bytes.Buffer{}, tt.q, tt.ballots)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWriteBallotsCSV(t *testing.T) {
+	scored, err := vote.NewScoredVote(uuid.New(), uuid.New(), 3, []int{20, 21}, []int{4, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scored, err = scored.WithWeight(3); err != nil {
+		t.Fatal(err)
+	}
+	questions := []vote.Question{
+		rankedQuestion(),
+		{ID: 3, Kind: vote.KindCumulative, Choices: []vote.Choice{{ID: 20, Text: "p"}, {ID: 21, Text: "q"}}},
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteBallotsCSV(&buf, questions, export.Ballots([]vote.Vote{mustRanked(t, 2, 11, 10), scored})); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// une ligne d'entête et une ligne par choix de chaque bulletin, avec son poids
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records, got %d", len(records))
+	}
+
+	if got := strings.Join(records[1][2:], ","); got != "1,11,y,,1" {
+		t.Errorf("ranked line: got %q", got)
+	}
+
+	if got := strings.Join(records[4][2:], ","); got != "2,21,q,1,3" {
+		t.Errorf("scored line: got %q", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"csv", "JSON", "blt"} {
+		if _, err := export.ParseFormat(s); err != nil {
+			t.Errorf("%s: unexpected error %v", s, err)
+		}
+	}
+
+	if _, err := export.ParseFormat("xml"); !errors.Is(err, export.ErrUnknownFormat) {
+		t.Errorf("expected %v, got %v", export.ErrUnknownFormat, err)
+	}
+}
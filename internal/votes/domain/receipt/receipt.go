@@ -0,0 +1,189 @@
+package receipt
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+// A receipt is what the server acknowledged when a ballot was cast, signed with ed25519
+// so the voter can prove it later. It is stored as receipt_data = payload || signature,
+// string_size being the size of the payload.
+// A receipt without entries acknowledges that the ballot was revoked.
+
+// Version of the payload format, bumped when Payload changes
+const Version = 1
+
+var (
+	ErrNotFound           = errors.New("receipt not found")
+	ErrInvalidSignature   = errors.New("receipt signature does not match")
+	ErrUnsupportedVersion = errors.New("unsupported receipt version")
+	ErrCorrupted          = errors.New("stored receipt does not match its checksum")
+	ErrInvalidKey         = errors.New("invalid ed25519 key")
+)
+
+// Payload is the signed content of a receipt
+type Payload struct {
+	Version   int       `json:"version"`
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"session_id"`
+	// Secret : the entries only tell which questions were answered,
+	// a receipt of a secret ballot must not prove what was voted
+	Secret   bool      `json:"secret"`
+	Entries  []Entry   `json:"entries"` // empty once the ballot is revoked
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// Revoked : the receipt acknowledges that the ballot was withdrawn, no vote is counted
+func (p Payload) Revoked() bool {
+	return len(p.Entries) == 0
+}
+
+type Entry struct {
+	QuestionID int       `json:"question_id"`
+	VoteID     uuid.UUID `json:"vote_id,omitzero"`
+	ChoiceIDs  []int     `json:"choice_ids,omitempty"` // preference order when ranked
+	Points     []int     `json:"points,omitempty"`
+}
+
+type Receipt struct {
+	id        uuid.UUID
+	userID    uuid.UUID
+	sessionID uuid.UUID
+	version   int
+	payload   []byte // signed JSON
+	signature []byte
+	createdAt time.Time
+}
+
+// Getters
+func (r Receipt) ID() uuid.UUID        { return r.id }
+func (r Receipt) UserID() uuid.UUID    { return r.userID }
+func (r Receipt) SessionID() uuid.UUID { return r.sessionID }
+func (r Receipt) Version() int         { return r.version }
+func (r Receipt) Payload() []byte      { return bytes.Clone(r.payload) }
+func (r Receipt) Signature() []byte    { return bytes.Clone(r.signature) }
+func (r Receipt) CreatedAt() time.Time { return r.createdAt }
+
+// Data is what is stored in receipt_data
+func (r Receipt) Data() []byte {
+	return append(bytes.Clone(r.payload), r.signature...)
+}
+
+// Checksum is the hex sha256 of the payload
+func (r Receipt) Checksum() string {
+	return checksum(r.payload)
+}
+
+// Issue signs the receipt of the votes of userID in the session
+func Issue(key ed25519.PrivateKey, userID, sessionID uuid.UUID, secret bool, votes []vote.Vote) (Receipt, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return Receipt{}, ErrInvalidKey
+	}
+
+	p := Payload{
+		Version:   Version,
+		ID:        uuid.New(),
+		UserID:    userID,
+		SessionID: sessionID,
+		Secret:    secret,
+		Entries:   make([]Entry, 0, len(votes)),
+		IssuedAt:  time.Now().UTC(),
+	}
+
+	for _, v := range votes {
+		e := Entry{QuestionID: v.QuestionID()}
+		if !secret {
+			e.VoteID = v.ID()
+			e.ChoiceIDs = v.ChoiceIDs()
+			e.Points = v.Points()
+		}
+		p.Entries = append(p.Entries, e)
+	}
+
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return Receipt{}, fmt.Errorf("failed to marshal receipt: %w", err)
+	}
+
+	return Receipt{
+		id:        p.ID,
+		userID:    userID,
+		sessionID: sessionID,
+		version:   Version,
+		payload:   payload,
+		signature: ed25519.Sign(key, payload),
+		createdAt: p.IssuedAt,
+	}, nil
+}
+
+// Verify checks the signature of a payload and decodes it
+func Verify(publicKey ed25519.PublicKey, payload, signature []byte) (Payload, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return Payload{}, ErrInvalidKey
+	}
+
+	if !ed25519.Verify(publicKey, payload, signature) {
+		return Payload{}, ErrInvalidSignature
+	}
+
+	var p Payload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return Payload{}, fmt.Errorf("invalid receipt payload: %w", err)
+	}
+
+	if p.Version != Version {
+		return Payload{}, ErrUnsupportedVersion
+	}
+
+	return p, nil
+}
+
+// Rehydrate splits receipt_data at stringSize and checks it against the stored checksum
+func Rehydrate(
+	id uuid.UUID,
+	userID uuid.UUID,
+	sessionID uuid.UUID,
+	version int,
+	stringSize int,
+	data []byte,
+	sum string,
+	createdAt time.Time,
+) (Receipt, error) {
+	if version != Version {
+		return Receipt{}, ErrUnsupportedVersion
+	}
+
+	if stringSize < 0 || len(data) != stringSize+ed25519.SignatureSize {
+		return Receipt{}, ErrCorrupted
+	}
+
+	payload := data[:stringSize]
+	if checksum(payload) != sum {
+		return Receipt{}, ErrCorrupted
+	}
+
+	return Receipt{
+		id:        id,
+		userID:    userID,
+		sessionID: sessionID,
+		version:   version,
+		payload:   bytes.Clone(payload),
+		signature: bytes.Clone(data[stringSize:]),
+		createdAt: createdAt,
+	}, nil
+}
+
+func checksum(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,133 @@
+package receipt_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/receipt"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+func mustKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func mustBallot(t *testing.T, userID, sessionID uuid.UUID) []vote.Vote {
+	t.Helper()
+	v1, err := vote.NewVote(userID, sessionID, 1, []int{3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := vote.NewRankedVote(userID, sessionID, 2, []int{6, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []vote.Vote{v1, v2}
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	key := mustKey(t)
+	userID, sessionID := uuid.New(), uuid.New()
+	ballot := mustBallot(t, userID, sessionID)
+
+	// GIVEN un reçu signé pour un bulletin
+	rc, err := receipt.Issue(key, userID, sessionID, false, ballot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on le vérifie avec la clé publique du serveur
+	p, err := receipt.Verify(key.Public().(ed25519.PublicKey), rc.Payload(), rc.Signature())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN il atteste des votes enregistrés
+	if p.UserID != userID || p.SessionID != sessionID || p.ID != rc.ID() || len(p.Entries) != 2 {
+		t.Fatalf("unexpected payload %+v", p)
+	}
+
+	if p.Entries[1].VoteID != ballot[1].ID() || !slices.Equal(p.Entries[1].ChoiceIDs, []int{6, 5}) {
+		t.Errorf("entry: got %+v", p.Entries[1])
+	}
+
+	// AND un payload modifié ou une autre clé sont refusés
+	tampered := bytes.Replace(rc.Payload(), []byte(`[6,5]`), []byte(`[5,6]`), 1)
+	if _, err := receipt.Verify(key.Public().(ed25519.PublicKey), tampered, rc.Signature()); !errors.Is(err, receipt.ErrInvalidSignature) {
+		t.Errorf("tampered payload: expected %v, got %v", receipt.ErrInvalidSignature, err)
+	}
+
+	other := mustKey(t).Public().(ed25519.PublicKey)
+	if _, err := receipt.Verify(other, rc.Payload(), rc.Signature()); !errors.Is(err, receipt.ErrInvalidSignature) {
+		t.Errorf("other key: expected %v, got %v", receipt.ErrInvalidSignature, err)
+	}
+}
+
+func TestIssue_SecretBallotHidesVotes(t *testing.T) {
+	key := mustKey(t)
+	userID, sessionID := uuid.New(), uuid.New()
+
+	rc, err := receipt.Issue(key, userID, sessionID, true, mustBallot(t, userID, sessionID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := receipt.Verify(key.Public().(ed25519.PublicKey), rc.Payload(), rc.Signature())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range p.Entries {
+		if e.VoteID != uuid.Nil || e.ChoiceIDs != nil || e.Points != nil {
+			t.Errorf("secret receipt tells the vote: %+v", e)
+		}
+	}
+}
+
+func TestRehydrate_ChecksData(t *testing.T) {
+	key := mustKey(t)
+	userID, sessionID := uuid.New(), uuid.New()
+
+	rc, err := receipt.Issue(key, userID, sessionID, false, mustBallot(t, userID, sessionID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size := len(rc.Payload())
+
+	tests := []struct {
+		name     string
+		size     int
+		data     []byte
+		checksum string
+		wantErr  error
+	}{
+		{"intact", size, rc.Data(), rc.Checksum(), nil},
+		{"taille fausse", size - 1, rc.Data(), rc.Checksum(), receipt.ErrCorrupted},
+		{"checksum faux", size, rc.Data(), "00", receipt.ErrCorrupted},
+		{"signature tronquee", size, rc.Data()[:size+10], rc.Checksum(), receipt.ErrCorrupted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := receipt.Rehydrate(rc.ID(), userID, sessionID, receipt.Version, tt.size, tt.data, tt.checksum, rc.CreatedAt())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if err == nil && !bytes.Equal(got.Signature(), rc.Signature()) {
+				t.Error("signature changed through the round trip")
+			}
+		})
+	}
+}
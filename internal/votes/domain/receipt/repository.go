@@ -0,0 +1,24 @@
+package receipt
+
+import (
+	"context"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// SaveReceipt keeps one receipt per voter and session, a new ballot replaces the previous receipt
+	SaveReceipt(context.Context, Receipt) error
+	// CastBallot stores the votes and their receipt, or none of them.
+	// It returns vote.ErrAlreadyVoted like vote.Repository.CastBallot.
+	CastBallot(context.Context, Receipt, []vote.Vote) error
+	// CastSecretBallot records the participation of the voter of the receipt,
+	// stores the votes without voter and the receipt, or none of them
+	CastSecretBallot(context.Context, Receipt, []vote.Vote) error
+	// ReplaceBallot supersedes the votes, casts the new ones and replaces the receipt atomically
+	ReplaceBallot(context.Context, Receipt, []vote.Superseded, []vote.Vote) error
+	// RevokeBallot supersedes the votes and replaces the receipt by the one of the revocation atomically
+	RevokeBallot(context.Context, Receipt, []vote.Superseded) error
+	GetReceipt(context.Context, uuid.UUID /* user id */, uuid.UUID /* session id */) (Receipt, error)
+}
@@ -0,0 +1,20 @@
+package snapshot
+
+import (
+	"context"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// SaveSnapshot returns ErrAlreadyFinalized when the session already has one
+	SaveSnapshot(context.Context, Snapshot) error
+	// Finalize reads the votes with tally and saves the snapshot it returns in one transaction,
+	// so the snapshot holds every vote stored before it.
+	// It returns ErrAlreadyFinalized like SaveSnapshot.
+	Finalize(context.Context, func(vote.Counter) (Snapshot, error)) (Snapshot, error)
+	GetSnapshot(context.Context, uuid.UUID /* session id */) (Snapshot, error)
+	// GetStoredSnapshot returns the row without checking it, see Stored.Verify
+	GetStoredSnapshot(context.Context, uuid.UUID /* session id */) (Stored, error)
+}
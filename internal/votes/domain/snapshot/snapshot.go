@@ -0,0 +1,105 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A snapshot is the result document of a finalized session, stored once in result_history.
+// The document is built by the app layer, the snapshot only keeps it with its checksum.
+
+// Version of the result document, bumped when its format changes
+const Version = 1
+
+var (
+	ErrNotFound           = errors.New("result snapshot not found")
+	ErrAlreadyFinalized   = errors.New("vote session is already finalized")
+	ErrEmptyDocument      = errors.New("result document cannot be empty")
+	ErrCorrupted          = errors.New("stored result does not match its checksum")
+	ErrUnsupportedVersion = errors.New("unsupported result version")
+)
+
+type Snapshot struct {
+	id        uuid.UUID
+	sessionID uuid.UUID
+	version   int
+	document  []byte // JSON
+	createdAt time.Time
+}
+
+// Getters
+func (s Snapshot) ID() uuid.UUID        { return s.id }
+func (s Snapshot) SessionID() uuid.UUID { return s.sessionID }
+func (s Snapshot) Version() int         { return s.version }
+func (s Snapshot) Document() []byte     { return bytes.Clone(s.document) }
+func (s Snapshot) CreatedAt() time.Time { return s.createdAt }
+
+// Checksum is the hex sha256 of the document
+func (s Snapshot) Checksum() string {
+	return checksum(s.document)
+}
+
+func New(sessionID uuid.UUID, document []byte, finalizedAt time.Time) (Snapshot, error) {
+	if len(document) == 0 {
+		return Snapshot{}, ErrEmptyDocument
+	}
+
+	return Snapshot{
+		id:        uuid.New(),
+		sessionID: sessionID,
+		version:   Version,
+		document:  bytes.Clone(document),
+		createdAt: finalizedAt,
+	}, nil
+}
+
+// Stored is a row of result_history as it is, even when it no longer matches its checksum.
+// A recount reads it to report what is wrong rather than refusing it.
+type Stored struct {
+	ID         uuid.UUID
+	SessionID  uuid.UUID
+	Version    int
+	StringSize int
+	Document   []byte
+	Checksum   string
+	CreatedAt  time.Time
+}
+
+// Verify returns ErrUnsupportedVersion or ErrCorrupted
+func (s Stored) Verify() error {
+	if s.Version != Version {
+		return ErrUnsupportedVersion
+	}
+
+	if len(s.Document) != s.StringSize || checksum(s.Document) != s.Checksum {
+		return ErrCorrupted
+	}
+
+	return nil
+}
+
+// Rehydrate checks the stored document against its size and checksum
+func Rehydrate(id, sessionID uuid.UUID, version, stringSize int, document []byte, sum string, createdAt time.Time) (Snapshot, error) {
+	stored := Stored{Version: version, StringSize: stringSize, Document: document, Checksum: sum}
+	if err := stored.Verify(); err != nil {
+		return Snapshot{}, err
+	}
+
+	return Snapshot{
+		id:        id,
+		sessionID: sessionID,
+		version:   version,
+		document:  bytes.Clone(document),
+		createdAt: createdAt,
+	}, nil
+}
+
+func checksum(document []byte) string {
+	sum := sha256.Sum256(document)
+	return hex.EncodeToString(sum[:])
+}
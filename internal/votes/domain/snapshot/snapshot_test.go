@@ -0,0 +1,76 @@
+package snapshot_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/snapshot"
+	"github.com/google/uuid"
+)
+
+func TestRehydrate_ChecksDocument(t *testing.T) {
+	snap, err := snapshot.New(uuid.New(), []byte(`{"version":1,"result":{}}`), time.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := snap.Document()
+
+	tests := []struct {
+		name     string
+		version  int
+		size     int
+		document []byte
+		checksum string
+		wantErr  error
+	}{
+		{"intact", snapshot.Version, len(document), document, snap.Checksum(), nil},
+		{"document modifie", snapshot.Version, len(document), []byte(`{"version":1,"result":[]}`), snap.Checksum(), snapshot.ErrCorrupted},
+		{"taille fausse", snapshot.Version, len(document) + 1, document, snap.Checksum(), snapshot.ErrCorrupted},
+		{"version inconnue", 2, len(document), document, snap.Checksum(), snapshot.ErrUnsupportedVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := snapshot.Rehydrate(snap.ID(), snap.SessionID(), tt.version, tt.size, tt.document, tt.checksum, snap.CreatedAt())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := snapshot.New(uuid.New(), nil, time.Now()); !errors.Is(err, snapshot.ErrEmptyDocument) {
+		t.Errorf("expected %v, got %v", snapshot.ErrEmptyDocument, err)
+	}
+}
+
+func TestStored_Verify(t *testing.T) {
+	snap, err := snapshot.New(uuid.New(), []byte(`{"version":1,"result":{"voters":3}}`), time.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// GIVEN la ligne stockée d'un résultat
+	stored := snapshot.Stored{
+		ID:         snap.ID(),
+		SessionID:  snap.SessionID(),
+		Version:    snap.Version(),
+		StringSize: len(snap.Document()),
+		Document:   snap.Document(),
+		Checksum:   snap.Checksum(),
+		CreatedAt:  snap.CreatedAt(),
+	}
+
+	if err := stored.Verify(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// WHEN son document est modifié à taille égale
+	stored.Document = []byte(`{"version":1,"result":{"voters":4}}`)
+
+	// THEN elle reste lisible mais sa vérification échoue
+	if err := stored.Verify(); !errors.Is(err, snapshot.ErrCorrupted) {
+		t.Errorf("expected %v, got %v", snapshot.ErrCorrupted, err)
+	}
+}
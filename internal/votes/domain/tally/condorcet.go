@@ -0,0 +1,238 @@
+package tally
+
+import "sort"
+
+// Matrix is indexed like ChoiceIDs : Cells[i][j] is about ChoiceIDs[i] against ChoiceIDs[j]
+type Matrix struct {
+	ChoiceIDs []int   `json:"choice_ids"`
+	Cells     [][]int `json:"cells"`
+}
+
+func newMatrix(choiceIDs []int) Matrix {
+	cells := make([][]int, len(choiceIDs))
+	for i := range cells {
+		cells[i] = make([]int, len(choiceIDs))
+	}
+	return Matrix{ChoiceIDs: choiceIDs, Cells: cells}
+}
+
+// Pairwise counts for each pair of choices the weight of the ballots ranking one above the other.
+// A ranked choice is preferred to every unranked choice, unranked choices are equal.
+// Unknown choices are ignored.
+func Pairwise(choiceIDs []int, ballots []Ballot) Matrix {
+	m := newMatrix(choiceIDs)
+
+	index := make(map[int]int, len(choiceIDs))
+	for i, id := range choiceIDs {
+		index[id] = i
+	}
+
+	for _, b := range ballots {
+		ranked := make([]bool, len(choiceIDs))
+		for _, id := range b.ChoiceIDs {
+			i, ok := index[id]
+			if !ok {
+				continue
+			}
+
+			// i is above every choice not ranked yet
+			for j := range choiceIDs {
+				if j != i && !ranked[j] {
+					m.Cells[i][j] += b.weight()
+				}
+			}
+			ranked[i] = true
+		}
+	}
+
+	return m
+}
+
+type SchulzeResult struct {
+	Ballots        int    `json:"ballots"`
+	Weight         int    `json:"weight"`
+	Pairwise       Matrix `json:"pairwise"`
+	StrongestPaths Matrix `json:"strongest_paths"`
+	Winners        []int  `json:"winners"` // several winners means a tie
+}
+
+// Schulze : the strength of a path is its weakest pairwise win, a choice wins when
+// its strongest path to every other choice is at least as strong as the way back.
+func Schulze(choiceIDs []int, ballots []Ballot) SchulzeResult {
+	d := Pairwise(choiceIDs, ballots)
+	p := newMatrix(choiceIDs)
+	n := len(choiceIDs)
+
+	for i := range n {
+		for j := range n {
+			if i != j && d.Cells[i][j] > d.Cells[j][i] {
+				p.Cells[i][j] = d.Cells[i][j]
+			}
+		}
+	}
+
+	for k := range n {
+		for i := range n {
+			if i == k {
+				continue
+			}
+			for j := range n {
+				if j == i || j == k {
+					continue
+				}
+				p.Cells[i][j] = max(p.Cells[i][j], min(p.Cells[i][k], p.Cells[k][j]))
+			}
+		}
+	}
+
+	result := SchulzeResult{
+		Ballots:        len(ballots),
+		Weight:         Weigh(ballots),
+		Pairwise:       d,
+		StrongestPaths: p,
+		Winners:        []int{},
+	}
+
+	if len(ballots) == 0 {
+		return result
+	}
+
+	for i, id := range choiceIDs {
+		wins := true
+		for j := range n {
+			if j != i && p.Cells[i][j] < p.Cells[j][i] {
+				wins = false
+				break
+			}
+		}
+		if wins {
+			result.Winners = append(result.Winners, id)
+		}
+	}
+
+	return result
+}
+
+// Pair is a pairwise win of Winner over Loser
+type Pair struct {
+	Winner  int  `json:"winner"`
+	Loser   int  `json:"loser"`
+	Votes   int  `json:"votes"`   // weight of the ballots preferring Winner
+	Against int  `json:"against"` // weight of the ballots preferring Loser
+	Locked  bool `json:"locked"`  // false when it would create a cycle
+}
+
+type RankedPairsResult struct {
+	Ballots  int    `json:"ballots"`
+	Weight   int    `json:"weight"`
+	Pairwise Matrix `json:"pairwise"`
+	Pairs    []Pair `json:"pairs"` // in locking order
+	Winners  []int  `json:"winners"`
+}
+
+// RankedPairs (Tideman) locks the pairwise wins from the strongest to the weakest,
+// skipping those that would create a cycle. The winners are the choices nobody is locked over.
+// Wins are sorted by votes, then by the fewest votes against, then by the order of choiceIDs.
+func RankedPairs(choiceIDs []int, ballots []Ballot) RankedPairsResult {
+	d := Pairwise(choiceIDs, ballots)
+	n := len(choiceIDs)
+
+	type indexed struct {
+		Pair
+		i, j int
+	}
+
+	var wins []indexed
+	for i := range n {
+		for j := range n {
+			if i != j && d.Cells[i][j] > d.Cells[j][i] {
+				wins = append(wins, indexed{
+					Pair: Pair{
+						Winner:  choiceIDs[i],
+						Loser:   choiceIDs[j],
+						Votes:   d.Cells[i][j],
+						Against: d.Cells[j][i],
+					},
+					i: i,
+					j: j,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(wins, func(a, b int) bool {
+		if wins[a].Votes != wins[b].Votes {
+			return wins[a].Votes > wins[b].Votes
+		}
+		return wins[a].Against < wins[b].Against
+	})
+
+	// locked[i][j] : i is locked over j
+	locked := make([][]bool, n)
+	for i := range locked {
+		locked[i] = make([]bool, n)
+	}
+
+	result := RankedPairsResult{
+		Ballots:  len(ballots),
+		Weight:   Weigh(ballots),
+		Pairwise: d,
+		Pairs:    make([]Pair, 0, len(wins)),
+		Winners:  []int{},
+	}
+
+	for _, w := range wins {
+		// locking i over j makes a cycle when j already reaches i
+		if !reaches(locked, w.j, w.i) {
+			locked[w.i][w.j] = true
+			w.Locked = true
+		}
+		result.Pairs = append(result.Pairs, w.Pair)
+	}
+
+	if len(ballots) == 0 {
+		return result
+	}
+
+	for j, id := range choiceIDs {
+		beaten := false
+		for i := range n {
+			if locked[i][j] {
+				beaten = true
+				break
+			}
+		}
+		if !beaten {
+			result.Winners = append(result.Winners, id)
+		}
+	}
+
+	return result
+}
+
+// reaches tells if there is a path of locked pairs from i to j
+func reaches(locked [][]bool, from, to int) bool {
+	seen := make([]bool, len(locked))
+	stack := []int{from}
+
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if i == to {
+			return true
+		}
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+
+		for j, ok := range locked[i] {
+			if ok && !seen[j] {
+				stack = append(stack, j)
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,157 @@
+package tally_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/tally"
+)
+
+// repeat builds n identical ballots
+func repeat(n int, ranking ...int) []tally.Ballot {
+	b := make([]tally.Ballot, n)
+	for i := range b {
+		b[i] = tally.Ballot{ChoiceIDs: ranking}
+	}
+	return b
+}
+
+// A=1 B=2 C=3 D=4 E=5 : exemple de 45 électeurs de la méthode Schulze
+func schulzeExample() []tally.Ballot {
+	return slices.Concat(
+		repeat(5, 1, 3, 2, 5, 4),
+		repeat(5, 1, 4, 5, 3, 2),
+		repeat(8, 2, 5, 4, 1, 3),
+		repeat(3, 3, 1, 2, 5, 4),
+		repeat(7, 3, 1, 5, 2, 4),
+		repeat(2, 3, 2, 1, 4, 5),
+		repeat(7, 4, 3, 5, 2, 1),
+		repeat(8, 5, 2, 1, 4, 3),
+	)
+}
+
+func TestPairwise(t *testing.T) {
+	// GIVEN : un bulletin qui ne classe que 2, puis 3
+	b := []tally.Ballot{{ChoiceIDs: []int{2, 3}}, {ChoiceIDs: []int{1, 99}}}
+
+	// WHEN
+	m := tally.Pairwise([]int{1, 2, 3}, b)
+
+	// THEN : un choix classé bat tous les choix non classés, les non classés sont à égalité
+	want := [][]int{
+		{0, 1, 1},
+		{1, 0, 1},
+		{1, 0, 0},
+	}
+
+	for i := range want {
+		if !slices.Equal(m.Cells[i], want[i]) {
+			t.Errorf("row %d: got %v, want %v", i, m.Cells[i], want[i])
+		}
+	}
+}
+
+func TestSchulze(t *testing.T) {
+	choices := []int{1, 2, 3, 4, 5}
+
+	// WHEN
+	got := tally.Schulze(choices, schulzeExample())
+
+	// THEN
+	wantPairwise := [][]int{
+		{0, 20, 26, 30, 22},
+		{25, 0, 16, 33, 18},
+		{19, 29, 0, 17, 24},
+		{15, 12, 28, 0, 14},
+		{23, 27, 21, 31, 0},
+	}
+	wantPaths := [][]int{
+		{0, 28, 28, 30, 24},
+		{25, 0, 28, 33, 24},
+		{25, 29, 0, 29, 24},
+		{25, 28, 28, 0, 24},
+		{25, 28, 28, 31, 0},
+	}
+
+	for i := range choices {
+		if !slices.Equal(got.Pairwise.Cells[i], wantPairwise[i]) {
+			t.Errorf("pairwise row %d: got %v, want %v", i, got.Pairwise.Cells[i], wantPairwise[i])
+		}
+		if !slices.Equal(got.StrongestPaths.Cells[i], wantPaths[i]) {
+			t.Errorf("paths row %d: got %v, want %v", i, got.StrongestPaths.Cells[i], wantPaths[i])
+		}
+	}
+
+	if !slices.Equal(got.Winners, []int{5}) {
+		t.Errorf("Winners = %v, want [5]", got.Winners)
+	}
+}
+
+func TestRankedPairs(t *testing.T) {
+	choices := []int{1, 2, 3, 4, 5}
+
+	// WHEN
+	got := tally.RankedPairs(choices, schulzeExample())
+
+	// THEN : D>C, B>A et E>A sont ignorées car elles créeraient un cycle,
+	// A gagne là où Schulze élit E
+	if !slices.Equal(got.Winners, []int{1}) {
+		t.Errorf("Winners = %v, want [1]", got.Winners)
+	}
+
+	skipped := map[[2]int]bool{}
+	for _, p := range got.Pairs {
+		if !p.Locked {
+			skipped[[2]int{p.Winner, p.Loser}] = true
+		}
+	}
+
+	for _, pair := range [][2]int{{4, 3}, {2, 1}, {5, 1}} {
+		if !skipped[pair] {
+			t.Errorf("pair %v should not be locked", pair)
+		}
+	}
+
+	if len(got.Pairs) != 10 {
+		t.Errorf("got %d pairs, want 10", len(got.Pairs))
+	}
+
+	if got.Pairs[0].Winner != 2 || got.Pairs[0].Loser != 4 || got.Pairs[0].Votes != 33 {
+		t.Errorf("strongest pair: got %+v, want 2 over 4 with 33 votes", got.Pairs[0])
+	}
+}
+
+func TestCondorcet_Ties(t *testing.T) {
+	choices := []int{1, 2, 3}
+
+	tests := []struct {
+		name    string
+		ballots []tally.Ballot
+		want    []int
+	}{
+		{
+			name:    "aucun bulletin",
+			ballots: nil,
+			want:    []int{},
+		},
+		{
+			name: "egalite parfaite",
+			ballots: slices.Concat(
+				repeat(1, 1, 2, 3),
+				repeat(1, 3, 2, 1),
+			),
+			want: []int{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tally.Schulze(choices, tt.ballots); !slices.Equal(got.Winners, tt.want) {
+				t.Errorf("Schulze winners = %v, want %v", got.Winners, tt.want)
+			}
+			if got := tally.RankedPairs(choices, tt.ballots); !slices.Equal(got.Winners, tt.want) {
+				t.Errorf("RankedPairs winners = %v, want %v", got.Winners, tt.want)
+			}
+		})
+	}
+}
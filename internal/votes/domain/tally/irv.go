@@ -0,0 +1,148 @@
+package tally
+
+// IRVRound : counts of the continuing choices, each ballot goes with its weight to its highest
+// ranked continuing choice. A ballot without any continuing choice is exhausted.
+type IRVRound struct {
+	Round      int     `json:"round"`
+	Counts     []Count `json:"counts"`               // percentages of the weight of the active ballots
+	Exhausted  int     `json:"exhausted"`            // weight of the exhausted ballots
+	Eliminated int     `json:"eliminated,omitempty"` // 0 on the last round
+}
+
+type IRVResult struct {
+	Ballots int        `json:"ballots"`
+	Weight  int        `json:"weight"`
+	Rounds  []IRVRound `json:"rounds"`
+	Winners []int      `json:"winners"` // several winners means a tie
+}
+
+// InstantRunoff eliminates the last choice round after round until one choice
+// holds more than half of the weight of the active ballots. Ballots list choices by preference.
+//
+// Ties for the last place are broken by the counts of the previous rounds, going backwards,
+// then the choice placed last in choiceIDs is eliminated.
+// When every continuing choice has the same count they all win.
+func InstantRunoff(choiceIDs []int, ballots []Ballot) IRVResult {
+	weight := Weigh(ballots)
+
+	result := IRVResult{
+		Ballots: len(ballots),
+		Weight:  weight,
+		Rounds:  []IRVRound{},
+		Winners: []int{},
+	}
+
+	continuing := make(map[int]bool, len(choiceIDs))
+	for _, id := range choiceIDs {
+		continuing[id] = true
+	}
+
+	// history[r][id] is the count of id at round r
+	var history []map[int]int
+
+	for round := 1; len(continuing) > 0; round++ {
+		votes := make(map[int]int, len(continuing))
+		heads := make(map[int]int, len(continuing))
+		exhausted := 0
+		for _, b := range ballots {
+			if id, ok := topChoice(b, continuing); ok {
+				votes[id] += b.weight()
+				heads[id]++
+			} else {
+				exhausted += b.weight()
+			}
+		}
+		history = append(history, votes)
+
+		active := weight - exhausted
+		r := IRVRound{
+			Round:     round,
+			Counts:    make([]Count, 0, len(continuing)),
+			Exhausted: exhausted,
+		}
+
+		// remaining keeps the order of choiceIDs
+		remaining := make([]int, 0, len(continuing))
+		for _, id := range choiceIDs {
+			if !continuing[id] {
+				continue
+			}
+			remaining = append(remaining, id)
+			r.Counts = append(r.Counts, Count{
+				ChoiceID:   id,
+				Votes:      votes[id],
+				Headcount:  heads[id],
+				Percentage: percentage(votes[id], active),
+			})
+		}
+
+		if active == 0 {
+			result.Rounds = append(result.Rounds, r)
+			return result
+		}
+
+		for _, id := range remaining {
+			if votes[id]*2 > active {
+				result.Rounds = append(result.Rounds, r)
+				result.Winners = []int{id}
+				return result
+			}
+		}
+
+		if allTied(remaining, votes) {
+			result.Rounds = append(result.Rounds, r)
+			result.Winners = remaining
+			return result
+		}
+
+		last := lowest(remaining, history)
+		r.Eliminated = last[len(last)-1]
+		delete(continuing, r.Eliminated)
+		result.Rounds = append(result.Rounds, r)
+	}
+
+	return result
+}
+
+// topChoice returns the highest ranked continuing choice of the ballot
+func topChoice(b Ballot, continuing map[int]bool) (int, bool) {
+	for _, id := range b.ChoiceIDs {
+		if continuing[id] {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func allTied[T int | float64](ids []int, votes map[int]T) bool {
+	for _, id := range ids[1:] {
+		if votes[id] != votes[ids[0]] {
+			return false
+		}
+	}
+	return true
+}
+
+// lowest returns the choices with the fewest votes in the current round, narrowed with the
+// previous rounds as long as they differ. The order of ids is kept.
+func lowest[T int | float64](ids []int, history []map[int]T) []int {
+	candidates := ids
+	for r := len(history) - 1; r >= 0 && len(candidates) > 1; r-- {
+		min := history[r][candidates[0]]
+		for _, id := range candidates[1:] {
+			if history[r][id] < min {
+				min = history[r][id]
+			}
+		}
+
+		next := make([]int, 0, len(candidates))
+		for _, id := range candidates {
+			if history[r][id] == min {
+				next = append(next, id)
+			}
+		}
+		candidates = next
+	}
+
+	return candidates
+}
@@ -0,0 +1,161 @@
+package tally_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/tally"
+)
+
+func ballots(rankings ...[]int) []tally.Ballot {
+	b := make([]tally.Ballot, 0, len(rankings))
+	for _, r := range rankings {
+		b = append(b, tally.Ballot{ChoiceIDs: r})
+	}
+	return b
+}
+
+func TestInstantRunoff(t *testing.T) {
+	choices := []int{1, 2, 3}
+
+	tests := []struct {
+		name           string
+		ballots        []tally.Ballot
+		wantWinners    []int
+		wantEliminated []int
+		wantExhausted  []int
+	}{
+		{
+			name:           "aucun bulletin",
+			ballots:        nil,
+			wantWinners:    []int{},
+			wantEliminated: []int{0},
+			wantExhausted:  []int{0},
+		},
+		{
+			name: "majorite au premier tour",
+			ballots: ballots(
+				[]int{1, 2},
+				[]int{1, 3},
+				[]int{2, 1},
+			),
+			wantWinners:    []int{1},
+			wantEliminated: []int{0},
+			wantExhausted:  []int{0},
+		},
+		{
+			name: "report des voix du dernier",
+			// 1er tour : 1=2, 2=2, 3=1 -> 3 eliminee, son bulletin passe a 2
+			ballots: ballots(
+				[]int{1},
+				[]int{1},
+				[]int{2},
+				[]int{2},
+				[]int{3, 2},
+			),
+			wantWinners:    []int{2},
+			wantEliminated: []int{3, 0},
+			wantExhausted:  []int{0, 0},
+		},
+		{
+			name: "bulletin epuise",
+			// 1er tour : 1=3 sur 6, pas de majorite -> 3 eliminee, son bulletin n'a pas d'autre choix
+			// 2e tour : 1=3 sur 5 bulletins actifs
+			ballots: ballots(
+				[]int{1},
+				[]int{1},
+				[]int{2},
+				[]int{2},
+				[]int{3},
+				[]int{1, 2},
+			),
+			wantWinners:    []int{1},
+			wantEliminated: []int{3, 0},
+			wantExhausted:  []int{0, 1},
+		},
+		{
+			name: "egalite parfaite",
+			ballots: ballots(
+				[]int{1},
+				[]int{2},
+				[]int{3},
+			),
+			wantWinners:    []int{1, 2, 3},
+			wantEliminated: []int{0},
+			wantExhausted:  []int{0},
+		},
+		{
+			name: "egalite au dernier rang au premier tour",
+			// 1er tour : 1=3, 2=2, 3=2 -> 3 eliminee (placee apres 2), un bulletin reporte sur 1, l'autre epuise
+			ballots: ballots(
+				[]int{1},
+				[]int{1},
+				[]int{1},
+				[]int{2},
+				[]int{2},
+				[]int{3},
+				[]int{3, 1},
+			),
+			wantWinners:    []int{1},
+			wantEliminated: []int{3, 0},
+			wantExhausted:  []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			got := tally.InstantRunoff(choices, tt.ballots)
+
+			// THEN
+			if got.Ballots != len(tt.ballots) {
+				t.Errorf("Ballots = %d, want %d", got.Ballots, len(tt.ballots))
+			}
+
+			if !slices.Equal(got.Winners, tt.wantWinners) {
+				t.Errorf("Winners = %v, want %v", got.Winners, tt.wantWinners)
+			}
+
+			if len(got.Rounds) != len(tt.wantEliminated) {
+				t.Fatalf("got %d rounds, want %d", len(got.Rounds), len(tt.wantEliminated))
+			}
+
+			for i, r := range got.Rounds {
+				if r.Round != i+1 {
+					t.Errorf("round %d numbered %d", i+1, r.Round)
+				}
+				if r.Eliminated != tt.wantEliminated[i] {
+					t.Errorf("round %d eliminated %d, want %d", i+1, r.Eliminated, tt.wantEliminated[i])
+				}
+				if r.Exhausted != tt.wantExhausted[i] {
+					t.Errorf("round %d exhausted %d, want %d", i+1, r.Exhausted, tt.wantExhausted[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInstantRunoff_TieBreakUsesPreviousRounds(t *testing.T) {
+	// GIVEN : 4 est eliminee au 1er tour, son report met 2 et 3 a egalite,
+	// 3 avait moins de voix au 1er tour : c'est elle qui part
+	choices := []int{1, 2, 3, 4}
+	b := ballots(
+		[]int{1}, []int{1}, []int{1}, []int{1},
+		[]int{2}, []int{2},
+		[]int{3}, []int{3}, []int{3},
+		[]int{4, 2},
+	)
+	// 1er tour : 1=4, 2=2, 3=3, 4=1 -> 4 eliminee
+	// 2e tour : 1=4, 2=3, 3=3 -> egalite, au 1er tour 2 avait 2 et 3 avait 3 -> 2 eliminee
+
+	// WHEN
+	got := tally.InstantRunoff(choices, b)
+
+	// THEN
+	if len(got.Rounds) < 2 {
+		t.Fatalf("got %d rounds, want at least 2", len(got.Rounds))
+	}
+	if got.Rounds[1].Eliminated != 2 {
+		t.Errorf("round 2 eliminated %d, want 2", got.Rounds[1].Eliminated)
+	}
+}
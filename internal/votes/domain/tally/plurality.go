@@ -0,0 +1,59 @@
+package tally
+
+// PluralityResult : each selected choice gets the weight of the ballot, the most voted choices win.
+// With multiple selection, percentages are computed on the weight of the ballots and may sum above 100.
+type PluralityResult struct {
+	Ballots int     `json:"ballots"`
+	Weight  int     `json:"weight"`
+	Counts  []Count `json:"counts"`
+	Winners []int   `json:"winners"` // several winners means a tie
+}
+
+// Plurality counts ballots for choiceIDs, counts keep the order of choiceIDs.
+// Selections of unknown choices are ignored.
+func Plurality(choiceIDs []int, ballots []Ballot) PluralityResult {
+	votes := make(map[int]int, len(choiceIDs))
+	heads := make(map[int]int, len(choiceIDs))
+	for _, id := range choiceIDs {
+		votes[id] = 0
+	}
+
+	for _, b := range ballots {
+		for _, id := range b.ChoiceIDs {
+			if _, ok := votes[id]; ok {
+				votes[id] += b.weight()
+				heads[id]++
+			}
+		}
+	}
+
+	weight := Weigh(ballots)
+
+	result := PluralityResult{
+		Ballots: len(ballots),
+		Weight:  weight,
+		Counts:  make([]Count, 0, len(choiceIDs)),
+		Winners: []int{},
+	}
+
+	best := 0
+	for _, id := range choiceIDs {
+		result.Counts = append(result.Counts, Count{
+			ChoiceID:   id,
+			Votes:      votes[id],
+			Headcount:  heads[id],
+			Percentage: percentage(votes[id], weight),
+		})
+
+		switch {
+		case votes[id] == 0:
+		case votes[id] > best:
+			best = votes[id]
+			result.Winners = []int{id}
+		case votes[id] == best:
+			result.Winners = append(result.Winners, id)
+		}
+	}
+
+	return result
+}
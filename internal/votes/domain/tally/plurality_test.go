@@ -0,0 +1,81 @@
+package tally_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/tally"
+)
+
+func TestPlurality(t *testing.T) {
+	choices := []int{1, 2, 3}
+
+	tests := []struct {
+		name        string
+		ballots     []tally.Ballot
+		wantVotes   []int
+		wantPercent []float64
+		wantWinners []int
+	}{
+		{
+			name:        "aucun bulletin",
+			ballots:     nil,
+			wantVotes:   []int{0, 0, 0},
+			wantPercent: []float64{0, 0, 0},
+			wantWinners: []int{},
+		},
+		{
+			name: "un gagnant",
+			ballots: []tally.Ballot{
+				{ChoiceIDs: []int{1}},
+				{ChoiceIDs: []int{2}},
+				{ChoiceIDs: []int{2}},
+			},
+			wantVotes:   []int{1, 2, 0},
+			wantPercent: []float64{33.33, 66.67, 0},
+			wantWinners: []int{2},
+		},
+		{
+			name: "egalite",
+			ballots: []tally.Ballot{
+				{ChoiceIDs: []int{1}},
+				{ChoiceIDs: []int{3}},
+			},
+			wantVotes:   []int{1, 0, 1},
+			wantPercent: []float64{50, 0, 50},
+			wantWinners: []int{1, 3},
+		},
+		{
+			name: "choix multiples et choix inconnu",
+			ballots: []tally.Ballot{
+				{ChoiceIDs: []int{1, 2}},
+				{ChoiceIDs: []int{1, 99}},
+			},
+			wantVotes:   []int{2, 1, 0},
+			wantPercent: []float64{100, 50, 0},
+			wantWinners: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tally.Plurality(choices, tt.ballots)
+
+			for i, c := range result.Counts {
+				if c.ChoiceID != choices[i] {
+					t.Errorf("counts should keep choices order, got %d at %d", c.ChoiceID, i)
+				}
+				if c.Votes != tt.wantVotes[i] {
+					t.Errorf("choice %d: got %d votes, want %d", c.ChoiceID, c.Votes, tt.wantVotes[i])
+				}
+				if c.Percentage != tt.wantPercent[i] {
+					t.Errorf("choice %d: got %v%%, want %v%%", c.ChoiceID, c.Percentage, tt.wantPercent[i])
+				}
+			}
+
+			if !slices.Equal(result.Winners, tt.wantWinners) {
+				t.Errorf("winners: got %v, want %v", result.Winners, tt.wantWinners)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package tally
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+type STVAction string
+
+const (
+	// ActionSurplus : the votes above the quota of an elected choice go to the next preferences
+	ActionSurplus STVAction = "surplus"
+	// ActionElimination : the last choice is eliminated, its ballots go to the next preferences
+	ActionElimination STVAction = "elimination"
+	// ActionFill : there are no more choices than seats left, they are all elected
+	ActionFill STVAction = "fill"
+)
+
+type STVCount struct {
+	ChoiceID int     `json:"choice_id"`
+	Votes    float64 `json:"votes"`
+}
+
+// Transfer : votes moved to a choice, To is 0 when the ballots are exhausted
+type Transfer struct {
+	To    int     `json:"to"`
+	Votes float64 `json:"votes"`
+}
+
+// STVRound : Counts are the elected and continuing choices at the start of the round,
+// then choices reaching the quota are elected and one action is done.
+type STVRound struct {
+	Round         int        `json:"round"`
+	Counts        []STVCount `json:"counts"`
+	Exhausted     float64    `json:"exhausted"`
+	Elected       []int      `json:"elected,omitempty"`
+	Action        STVAction  `json:"action,omitempty"` // empty once every seat is filled
+	From          int        `json:"from,omitempty"`
+	TransferValue float64    `json:"transfer_value,omitempty"` // surplus only
+	Transfers     []Transfer `json:"transfers,omitempty"`
+}
+
+type STVResult struct {
+	Ballots int        `json:"ballots"`
+	Weight  int        `json:"weight"`
+	Seats   int        `json:"seats"`
+	Quota   int        `json:"quota"`
+	Rounds  []STVRound `json:"rounds"`
+	Elected []int      `json:"elected"` // in order of election
+}
+
+// stvBallot is a ballot being counted, pile is the choice holding it (0 when exhausted)
+type stvBallot struct {
+	choiceIDs []int
+	weight    float64
+	pile      int
+}
+
+// STV elects seats choices with the Droop quota of the weight of the ballots,
+// a ballot starts with the weight of its voter. Surpluses are transferred with the
+// Gregory method: every ballot of the elected choice moves on with its weight multiplied
+// by surplus / votes. Ties for the last place are broken like InstantRunoff.
+// Votes in the log are rounded to 4 decimals, the count itself is not.
+func STV(choiceIDs []int, ballots []Ballot, seats int) STVResult {
+	result := STVResult{
+		Ballots: len(ballots),
+		Weight:  Weigh(ballots),
+		Seats:   seats,
+		Rounds:  []STVRound{},
+		Elected: []int{},
+	}
+
+	hopeful := make(map[int]bool, len(choiceIDs))
+	for _, id := range choiceIDs {
+		hopeful[id] = true
+	}
+	elected := make(map[int]bool, seats)
+
+	// kept[id] : votes of an elected choice once its surplus is transferred
+	kept := make(map[int]float64, seats)
+
+	piles := make([]*stvBallot, 0, len(ballots))
+	valid := 0 // weight of the piles
+	for _, b := range ballots {
+		sb := &stvBallot{choiceIDs: b.ChoiceIDs, weight: float64(b.weight())}
+		sb.pile, _ = topChoice(b, hopeful)
+		if sb.pile != 0 {
+			piles = append(piles, sb)
+			valid += b.weight()
+		}
+	}
+
+	if len(piles) == 0 || seats < 1 {
+		return result
+	}
+
+	result.Quota = valid/(seats+1) + 1
+	quota := float64(result.Quota)
+
+	var history []map[int]float64
+	var pending []int // elected choices whose surplus is not transferred yet
+
+	// move the ballots held by from to their next hopeful choice, weight is multiplied by value
+	move := func(from int, value float64) []Transfer {
+		moved := make(map[int]float64)
+		for _, b := range piles {
+			if b.pile != from {
+				continue
+			}
+			b.weight *= value
+			b.pile, _ = topChoice(Ballot{ChoiceIDs: b.choiceIDs}, hopeful)
+			moved[b.pile] += b.weight
+		}
+
+		transfers := make([]Transfer, 0, len(moved))
+		for _, id := range append(slices.Clone(choiceIDs), 0) {
+			if v, ok := moved[id]; ok {
+				transfers = append(transfers, Transfer{To: id, Votes: round4(v)})
+			}
+		}
+		return transfers
+	}
+
+	for round := 1; len(result.Elected) < seats; round++ {
+		votes := make(map[int]float64, len(choiceIDs))
+		for id, v := range kept {
+			votes[id] = v
+		}
+		exhausted := 0.0
+		for _, b := range piles {
+			if b.pile == 0 {
+				exhausted += b.weight
+			} else {
+				votes[b.pile] += b.weight
+			}
+		}
+		history = append(history, votes)
+
+		r := STVRound{
+			Round:     round,
+			Counts:    []STVCount{},
+			Exhausted: round4(exhausted),
+		}
+
+		var remaining []int
+		for _, id := range choiceIDs {
+			if hopeful[id] || elected[id] {
+				r.Counts = append(r.Counts, STVCount{ChoiceID: id, Votes: round4(votes[id])})
+			}
+			if hopeful[id] {
+				remaining = append(remaining, id)
+			}
+		}
+
+		// the quota is reached with a small tolerance for the float weights
+		var reached []int
+		for _, id := range remaining {
+			if votes[id] >= quota-1e-9 {
+				reached = append(reached, id)
+			}
+		}
+		slices.SortStableFunc(reached, func(a, b int) int { return cmp.Compare(votes[b], votes[a]) })
+
+		for _, id := range reached {
+			if len(result.Elected) == seats {
+				break
+			}
+			delete(hopeful, id)
+			elected[id] = true
+			result.Elected = append(result.Elected, id)
+			r.Elected = append(r.Elected, id)
+
+			if votes[id]-quota > 1e-9 {
+				pending = append(pending, id)
+			} else {
+				// nothing to transfer, its ballots stop here
+				move(id, 0)
+				kept[id] = votes[id]
+			}
+		}
+
+		remaining = slices.DeleteFunc(remaining, func(id int) bool { return elected[id] })
+
+		switch {
+		case len(result.Elected) == seats:
+		case len(result.Elected)+len(remaining) <= seats:
+			r.Action = ActionFill
+			for _, id := range remaining {
+				delete(hopeful, id)
+				elected[id] = true
+				result.Elected = append(result.Elected, id)
+				r.Elected = append(r.Elected, id)
+			}
+		case len(pending) > 0:
+			from := pending[0]
+			pending = pending[1:]
+
+			value := (votes[from] - quota) / votes[from]
+			r.Action = ActionSurplus
+			r.From = from
+			r.TransferValue = round4(value)
+			r.Transfers = move(from, value)
+			kept[from] = quota
+		default:
+			last := lowest(remaining, history)
+			r.Action = ActionElimination
+			r.From = last[len(last)-1]
+			delete(hopeful, r.From)
+			r.Transfers = move(r.From, 1)
+		}
+
+		result.Rounds = append(result.Rounds, r)
+
+		// fewer choices than seats
+		if r.Action == ActionFill {
+			break
+		}
+	}
+
+	return result
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
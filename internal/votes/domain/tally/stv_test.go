@@ -0,0 +1,104 @@
+package tally_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/tally"
+)
+
+func TestSTV(t *testing.T) {
+	// GIVEN : 20 électeurs, 3 sièges
+	// oranges=1 poires=2 chocolat=3 fraises=4 hamburgers=5
+	choices := []int{1, 2, 3, 4, 5}
+	b := slices.Concat(
+		repeat(4, 1),
+		repeat(2, 2, 1),
+		repeat(8, 3, 4),
+		repeat(4, 3, 5),
+		repeat(1, 4),
+		repeat(1, 5),
+	)
+
+	// WHEN
+	got := tally.STV(choices, b, 3)
+
+	// THEN
+	if got.Quota != 6 {
+		t.Errorf("Quota = %d, want 6", got.Quota)
+	}
+
+	if !slices.Equal(got.Elected, []int{3, 1, 4}) {
+		t.Errorf("Elected = %v, want [3 1 4]", got.Elected)
+	}
+
+	wantActions := []tally.STVAction{tally.ActionSurplus, tally.ActionElimination, tally.ActionElimination, tally.ActionFill}
+	wantFrom := []int{3, 2, 5, 0}
+
+	if len(got.Rounds) != len(wantActions) {
+		t.Fatalf("got %d rounds, want %d", len(got.Rounds), len(wantActions))
+	}
+
+	for i, r := range got.Rounds {
+		if r.Action != wantActions[i] || r.From != wantFrom[i] {
+			t.Errorf("round %d: got %s from %d, want %s from %d", i+1, r.Action, r.From, wantActions[i], wantFrom[i])
+		}
+	}
+
+	// le surplus du chocolat (12 - 6) part avec une valeur de 0.5
+	first := got.Rounds[0]
+	if first.TransferValue != 0.5 {
+		t.Errorf("transfer value = %v, want 0.5", first.TransferValue)
+	}
+
+	wantTransfers := []tally.Transfer{{To: 4, Votes: 4}, {To: 5, Votes: 2}}
+	if !slices.Equal(first.Transfers, wantTransfers) {
+		t.Errorf("transfers = %v, want %v", first.Transfers, wantTransfers)
+	}
+
+	// les bulletins des hamburgers n'ont plus de préférence
+	if got.Rounds[3].Exhausted != 3 {
+		t.Errorf("exhausted = %v, want 3", got.Rounds[3].Exhausted)
+	}
+}
+
+func TestSTV_EdgeCases(t *testing.T) {
+	tests := []struct {
+		name    string
+		choices []int
+		ballots []tally.Ballot
+		seats   int
+		want    []int
+	}{
+		{
+			name:    "aucun bulletin",
+			choices: []int{1, 2, 3},
+			ballots: nil,
+			seats:   2,
+			want:    []int{},
+		},
+		{
+			name:    "moins de choix que de sieges",
+			choices: []int{1, 2},
+			ballots: slices.Concat(repeat(2, 1), repeat(1, 2)),
+			seats:   3,
+			want:    []int{1, 2},
+		},
+		{
+			name:    "un siege comme un vote alternatif",
+			choices: []int{1, 2, 3},
+			ballots: slices.Concat(repeat(2, 1), repeat(2, 2), repeat(1, 3, 2)),
+			seats:   1,
+			want:    []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tally.STV(tt.choices, tt.ballots, tt.seats)
+			if !slices.Equal(got.Elected, tt.want) {
+				t.Errorf("Elected = %v, want %v", got.Elected, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package tally
+
+type Score struct {
+	ChoiceID   int     `json:"choice_id"`
+	Points     int     `json:"points"`
+	Average    float64 `json:"average"`    // points per unit of weight, a ballot not scoring the choice gives 0
+	Percentage float64 `json:"percentage"` // share of all the points given
+}
+
+// SumResult : score and cumulative ballots, the choices with the most points win.
+// The points of a ballot are multiplied by its weight.
+type SumResult struct {
+	Ballots int     `json:"ballots"`
+	Weight  int     `json:"weight"`
+	Scores  []Score `json:"scores"`
+	Winners []int   `json:"winners"` // several winners means a tie
+}
+
+// Sum adds the points of the ballots for choiceIDs, scores keep the order of choiceIDs.
+// Points of unknown choices are ignored.
+func Sum(choiceIDs []int, ballots []Ballot) SumResult {
+	points := make(map[int]int, len(choiceIDs))
+	for _, id := range choiceIDs {
+		points[id] = 0
+	}
+
+	total := 0
+	for _, b := range ballots {
+		for i, id := range b.ChoiceIDs {
+			if _, ok := points[id]; !ok || i >= len(b.Points) {
+				continue
+			}
+			points[id] += b.Points[i] * b.weight()
+			total += b.Points[i] * b.weight()
+		}
+	}
+
+	weight := Weigh(ballots)
+
+	result := SumResult{
+		Ballots: len(ballots),
+		Weight:  weight,
+		Scores:  make([]Score, 0, len(choiceIDs)),
+		Winners: []int{},
+	}
+
+	best := 0
+	for _, id := range choiceIDs {
+		result.Scores = append(result.Scores, Score{
+			ChoiceID:   id,
+			Points:     points[id],
+			Average:    ratio(points[id], weight),
+			Percentage: percentage(points[id], total),
+		})
+
+		switch {
+		case points[id] == 0:
+		case points[id] > best:
+			best = points[id]
+			result.Winners = []int{id}
+		case points[id] == best:
+			result.Winners = append(result.Winners, id)
+		}
+	}
+
+	return result
+}
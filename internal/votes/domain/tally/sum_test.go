@@ -0,0 +1,39 @@
+package tally_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/tally"
+)
+
+func TestSum(t *testing.T) {
+	// GIVEN : deux bulletins notés, le second ne note pas le choix 3
+	choices := []int{1, 2, 3}
+	b := []tally.Ballot{
+		{ChoiceIDs: []int{1, 2, 3}, Points: []int{5, 3, 2}},
+		{ChoiceIDs: []int{2, 1, 99}, Points: []int{5, 1, 4}},
+	}
+
+	// WHEN
+	got := tally.Sum(choices, b)
+
+	// THEN
+	want := []tally.Score{
+		{ChoiceID: 1, Points: 6, Average: 3, Percentage: 37.5},
+		{ChoiceID: 2, Points: 8, Average: 4, Percentage: 50},
+		{ChoiceID: 3, Points: 2, Average: 1, Percentage: 12.5},
+	}
+
+	if !slices.Equal(got.Scores, want) {
+		t.Errorf("Scores = %v, want %v", got.Scores, want)
+	}
+
+	if !slices.Equal(got.Winners, []int{2}) {
+		t.Errorf("Winners = %v, want [2]", got.Winners)
+	}
+
+	if tie := tally.Sum([]int{1, 2}, []tally.Ballot{{ChoiceIDs: []int{1, 2}, Points: []int{2, 2}}}); !slices.Equal(tie.Winners, []int{1, 2}) {
+		t.Errorf("tie Winners = %v, want [1 2]", tie.Winners)
+	}
+}
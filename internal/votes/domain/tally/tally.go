@@ -0,0 +1,67 @@
+package tally
+
+import "math"
+
+// Ballot is what a tally method needs to know about a vote.
+// Every method counts a ballot Weight times, the shares of its voter.
+type Ballot struct {
+	ChoiceIDs []int
+	Points    []int // score and cumulative ballots, Points[i] goes to ChoiceIDs[i]
+	Weight    int   // 1 when 0
+}
+
+func (b Ballot) weight() int {
+	return max(b.Weight, 1)
+}
+
+// Weigh is the total weight of the ballots
+func Weigh(ballots []Ballot) int {
+	total := 0
+	for _, b := range ballots {
+		total += b.weight()
+	}
+	return total
+}
+
+// Count : Votes is the weight of the ballots, Headcount their number
+type Count struct {
+	ChoiceID   int     `json:"choice_id"`
+	Votes      int     `json:"votes"`
+	Headcount  int     `json:"headcount"`
+	Percentage float64 `json:"percentage"` // of the weight
+}
+
+// percentage rounded to 2 decimals, 0 when there is no ballot
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(total)) / 100
+}
+
+// ratio rounded to 2 decimals, 0 when there is no ballot
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*100/float64(total)) / 100
+}
+
+// FirstPreferences counts the highest known choice of each ranked ballot, counts keep the order of choiceIDs
+func FirstPreferences(choiceIDs []int, ballots []Ballot) []Count {
+	known := make(map[int]bool, len(choiceIDs))
+	for _, id := range choiceIDs {
+		known[id] = true
+	}
+
+	firsts := make([]Ballot, 0, len(ballots))
+	for _, b := range ballots {
+		if id, ok := topChoice(b, known); ok {
+			firsts = append(firsts, Ballot{ChoiceIDs: []int{id}, Weight: b.Weight})
+		} else {
+			firsts = append(firsts, Ballot{Weight: b.Weight})
+		}
+	}
+
+	return Plurality(choiceIDs, firsts).Counts
+}
@@ -0,0 +1,107 @@
+package tally_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/tally"
+)
+
+// weighted builds one ballot of weight n
+func weighted(n int, ranking ...int) tally.Ballot {
+	return tally.Ballot{ChoiceIDs: ranking, Weight: n}
+}
+
+func TestWeight_CountsLikeDuplicatedBallots(t *testing.T) {
+	choices := []int{1, 2, 3}
+
+	// GIVEN : un votant de poids 4 face à trois votants de poids 1
+	heavy := []tally.Ballot{weighted(4, 3, 2, 1), weighted(1, 1, 2, 3), weighted(1, 1, 3, 2), weighted(1, 2, 1, 3)}
+	// AND : les mêmes voix en bulletins de poids 1
+	flat := slices.Concat(repeat(4, 3, 2, 1), repeat(1, 1, 2, 3), repeat(1, 1, 3, 2), repeat(1, 2, 1, 3))
+
+	tests := []struct {
+		name  string
+		count func([]tally.Ballot) (weight int, winners []int)
+	}{
+		{"plurality", func(b []tally.Ballot) (int, []int) {
+			r := tally.Plurality(choices, b)
+			return r.Weight, r.Winners
+		}},
+		{"irv", func(b []tally.Ballot) (int, []int) {
+			r := tally.InstantRunoff(choices, b)
+			return r.Weight, r.Winners
+		}},
+		{"schulze", func(b []tally.Ballot) (int, []int) {
+			r := tally.Schulze(choices, b)
+			return r.Weight, r.Winners
+		}},
+		{"ranked pairs", func(b []tally.Ballot) (int, []int) {
+			r := tally.RankedPairs(choices, b)
+			return r.Weight, r.Winners
+		}},
+		{"stv", func(b []tally.Ballot) (int, []int) {
+			r := tally.STV(choices, b, 2)
+			return r.Weight, r.Elected
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			weight, winners := tt.count(heavy)
+			wantWeight, wantWinners := tt.count(flat)
+
+			// THEN : le bulletin pondéré compte comme 4 bulletins identiques
+			if weight != wantWeight || weight != 7 {
+				t.Errorf("weight: got %d, want %d", weight, wantWeight)
+			}
+			if !slices.Equal(winners, wantWinners) {
+				t.Errorf("winners: got %v, want %v", winners, wantWinners)
+			}
+		})
+	}
+}
+
+func TestWeight_Headcount(t *testing.T) {
+	// GIVEN : un votant de poids 3 pour 1, deux votants de poids 1 pour 2
+	b := []tally.Ballot{weighted(3, 1), weighted(1, 2), weighted(1, 2)}
+
+	// WHEN
+	r := tally.Plurality([]int{1, 2}, b)
+
+	// THEN : 1 l'emporte en voix pondérées, 2 en nombre de votants
+	if r.Ballots != 3 || r.Weight != 5 {
+		t.Errorf("got ballots=%d weight=%d, want 3 and 5", r.Ballots, r.Weight)
+	}
+	if !slices.Equal(r.Winners, []int{1}) {
+		t.Errorf("winners: got %v, want [1]", r.Winners)
+	}
+
+	want := []tally.Count{
+		{ChoiceID: 1, Votes: 3, Headcount: 1, Percentage: 60},
+		{ChoiceID: 2, Votes: 2, Headcount: 2, Percentage: 40},
+	}
+	if !slices.Equal(r.Counts, want) {
+		t.Errorf("counts: got %+v, want %+v", r.Counts, want)
+	}
+}
+
+func TestWeight_Sum(t *testing.T) {
+	// GIVEN : un votant de poids 2 donne 5 points à 1, un votant de poids 1 donne 5 points à 2
+	b := []tally.Ballot{
+		{ChoiceIDs: []int{1}, Points: []int{5}, Weight: 2},
+		{ChoiceIDs: []int{2}, Points: []int{5}},
+	}
+
+	// WHEN
+	r := tally.Sum([]int{1, 2}, b)
+
+	// THEN : les points sont multipliés par le poids, la moyenne est par part
+	if !slices.Equal(r.Winners, []int{1}) {
+		t.Errorf("winners: got %v, want [1]", r.Winners)
+	}
+	if r.Scores[0].Points != 10 || r.Scores[0].Average != 3.33 {
+		t.Errorf("got %+v", r.Scores[0])
+	}
+}
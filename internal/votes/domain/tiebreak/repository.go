@@ -0,0 +1,16 @@
+package tiebreak
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// CommitSeed stores the seed of a question unless it already has one, the first commitment is kept
+	CommitSeed(context.Context, Seed) error
+	GetSeeds(context.Context, uuid.UUID /* session id */) ([]Seed, error)
+	// SaveDecision returns ErrAlreadyDecided when the question already has one
+	SaveDecision(context.Context, Decision) error
+	GetDecisions(context.Context, uuid.UUID /* session id */) ([]Decision, error)
+}
@@ -0,0 +1,194 @@
+package tiebreak
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+// A tie between the winners of a single seat question is broken by the rule of the question:
+//   - order: the tied choice with the lowest order_num wins
+//   - random: a seed is drawn and its commitment (sha256 of the seed) stored before the first
+//     ballot of the question, the seed is revealed once the session is closed and anyone can
+//     check the draw with Draw
+//   - organizer: an organizer picks one of the tied choices once the session is closed
+//
+// The Resolution is published with the result as the evidence of the tie-break.
+
+var (
+	ErrNotTied         = errors.New("choice is not one of the tied choices")
+	ErrNoTie           = errors.New("question is not tied")
+	ErrNotOrganizerTie = errors.New("tie of this question is not decided by an organizer")
+	ErrAlreadyDecided  = errors.New("tie is already decided")
+	ErrInvalidSeed     = errors.New("seed does not match its commitment")
+)
+
+// Resolution is the evidence of a tie-break. Winner is 0 while the tie is Pending.
+type Resolution struct {
+	Rule       vote.TieBreak `json:"rule"`
+	Tied       []int         `json:"tied"`
+	Winner     int           `json:"winner,omitempty"`
+	Pending    bool          `json:"pending,omitempty"`
+	Commitment string        `json:"commitment,omitempty"` // random
+	Seed       string        `json:"seed,omitempty"`       // random, once the session is closed
+	Decision   *Decision     `json:"decision,omitempty"`   // organizer
+}
+
+// Seed is drawn for a question with the random rule
+type Seed struct {
+	questionID int
+	sessionID  uuid.UUID
+	value      string // hex
+	createdAt  time.Time
+}
+
+// Getters
+func (s Seed) QuestionID() int      { return s.questionID }
+func (s Seed) SessionID() uuid.UUID { return s.sessionID }
+func (s Seed) Value() string        { return s.value }
+func (s Seed) CreatedAt() time.Time { return s.createdAt }
+
+// Commitment is published before the seed is used
+func (s Seed) Commitment() string {
+	return Commit(s.value)
+}
+
+func NewSeed(sessionID uuid.UUID, questionID int) (Seed, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return Seed{}, fmt.Errorf("draw seed: %w", err)
+	}
+
+	return Seed{
+		questionID: questionID,
+		sessionID:  sessionID,
+		value:      hex.EncodeToString(b),
+		createdAt:  time.Now().UTC(),
+	}, nil
+}
+
+// Rehydrate checks the stored seed against its stored commitment
+func Rehydrate(sessionID uuid.UUID, questionID int, value, commitment string, createdAt time.Time) (Seed, error) {
+	if Commit(value) != commitment {
+		return Seed{}, ErrInvalidSeed
+	}
+
+	return Seed{
+		questionID: questionID,
+		sessionID:  sessionID,
+		value:      value,
+		createdAt:  createdAt,
+	}, nil
+}
+
+// Commit is the hex sha256 of the hex seed
+func Commit(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Draw picks one of the tied choices from the seed: the first 8 bytes of
+// sha256("<seed>|<question id>|<tied ids sorted, comma separated>") modulo the number of tied choices,
+// as an index in the sorted tied ids
+func Draw(seed string, questionID int, tied []int) int {
+	sorted := slices.Sorted(slices.Values(tied))
+
+	ids := make([]string, 0, len(sorted))
+	for _, id := range sorted {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	sum := sha256.Sum256([]byte(seed + "|" + strconv.Itoa(questionID) + "|" + strings.Join(ids, ",")))
+	return sorted[binary.BigEndian.Uint64(sum[:8])%uint64(len(sorted))]
+}
+
+// ByOrder picks the tied choice with the lowest order_num
+func ByOrder(tied []int, choices []vote.Choice) int {
+	winner, first := 0, 0
+	for _, c := range choices {
+		if slices.Contains(tied, c.ID) && (winner == 0 || c.OrderNum < first) {
+			winner, first = c.ID, c.OrderNum
+		}
+	}
+	return winner
+}
+
+// Decision is the choice of an organizer for a question with the organizer rule
+type Decision struct {
+	QuestionID int       `json:"question_id"`
+	SessionID  uuid.UUID `json:"session_id"`
+	ChoiceID   int       `json:"choice_id"`
+	DecidedBy  uuid.UUID `json:"decided_by"`
+	DecidedAt  time.Time `json:"decided_at"`
+}
+
+// NewDecision : the choice must be one of the tied choices of the question
+func NewDecision(q vote.Question, tied []int, choiceID int, organizerID uuid.UUID) (Decision, error) {
+	if q.TieBreak != vote.TieBreakOrganizer {
+		return Decision{}, ErrNotOrganizerTie
+	}
+
+	if len(tied) < 2 {
+		return Decision{}, ErrNoTie
+	}
+
+	if !slices.Contains(tied, choiceID) {
+		return Decision{}, ErrNotTied
+	}
+
+	return Decision{
+		QuestionID: q.ID,
+		SessionID:  q.SessionID,
+		ChoiceID:   choiceID,
+		DecidedBy:  organizerID,
+		DecidedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// Evidence is what is known of a tie-break when the result is tallied.
+// Seed is only revealed, and used, once the session is closed.
+type Evidence struct {
+	Seed     *Seed
+	Revealed bool
+	Decision *Decision
+}
+
+// Resolve breaks the tie between the winners of q with its rule.
+// A decision which is not among the tied choices is ignored.
+func Resolve(q vote.Question, tied []int, evidence Evidence) Resolution {
+	r := Resolution{
+		Rule: q.TieBreak,
+		Tied: slices.Sorted(slices.Values(tied)),
+	}
+
+	switch q.TieBreak {
+	case vote.TieBreakOrder:
+		r.Winner = ByOrder(tied, q.Choices)
+	case vote.TieBreakRandom:
+		if evidence.Seed != nil {
+			r.Commitment = evidence.Seed.Commitment()
+		}
+		if evidence.Seed != nil && evidence.Revealed {
+			r.Seed = evidence.Seed.Value()
+			r.Winner = Draw(r.Seed, q.ID, tied)
+		}
+	case vote.TieBreakOrganizer:
+		if d := evidence.Decision; d != nil && slices.Contains(tied, d.ChoiceID) {
+			r.Winner = d.ChoiceID
+			r.Decision = d
+		}
+	}
+
+	r.Pending = r.Winner == 0
+	return r
+}
@@ -0,0 +1,158 @@
+package tiebreak_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/tiebreak"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+func tiedQuestion(rule vote.TieBreak) vote.Question {
+	return vote.Question{
+		ID:        7,
+		SessionID: uuid.New(),
+		Kind:      vote.KindChoice,
+		Method:    vote.MethodPlurality,
+		TieBreak:  rule,
+		Choices: []vote.Choice{
+			{ID: 10, Text: "A", OrderNum: 3},
+			{ID: 11, Text: "B", OrderNum: 1},
+			{ID: 12, Text: "C", OrderNum: 2},
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	seed, err := tiebreak.NewSeed(uuid.New(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		rule        vote.TieBreak
+		evidence    tiebreak.Evidence
+		wantWinner  int
+		wantPending bool
+	}{
+		{
+			name:       "order : le plus petit order_num",
+			rule:       vote.TieBreakOrder,
+			wantWinner: 12,
+		},
+		{
+			name:        "random : en attente tant que la graine n'est pas révélée",
+			rule:        vote.TieBreakRandom,
+			evidence:    tiebreak.Evidence{Seed: &seed},
+			wantPending: true,
+		},
+		{
+			name:       "random : tirage une fois la graine révélée",
+			rule:       vote.TieBreakRandom,
+			evidence:   tiebreak.Evidence{Seed: &seed, Revealed: true},
+			wantWinner: tiebreak.Draw(seed.Value(), 7, []int{10, 12}),
+		},
+		{
+			name:        "organizer : en attente sans décision",
+			rule:        vote.TieBreakOrganizer,
+			wantPending: true,
+		},
+		{
+			name:       "organizer : la décision départage",
+			rule:       vote.TieBreakOrganizer,
+			evidence:   tiebreak.Evidence{Decision: &tiebreak.Decision{QuestionID: 7, ChoiceID: 10}},
+			wantWinner: 10,
+		},
+		{
+			name:        "organizer : une décision hors des ex aequo est ignorée",
+			rule:        vote.TieBreakOrganizer,
+			evidence:    tiebreak.Evidence{Decision: &tiebreak.Decision{QuestionID: 7, ChoiceID: 11}},
+			wantPending: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN une question où A et C sont à égalité
+			q := tiedQuestion(tt.rule)
+
+			// WHEN on applique la règle de départage
+			r := tiebreak.Resolve(q, []int{12, 10}, tt.evidence)
+
+			// THEN le gagnant et les ex aequo sont publiés
+			if r.Winner != tt.wantWinner || r.Pending != tt.wantPending {
+				t.Errorf("got winner=%d pending=%v, want winner=%d pending=%v", r.Winner, r.Pending, tt.wantWinner, tt.wantPending)
+			}
+			if !slices.Equal(r.Tied, []int{10, 12}) {
+				t.Errorf("tied: got %v", r.Tied)
+			}
+			if tt.rule == vote.TieBreakRandom && r.Commitment != seed.Commitment() {
+				t.Errorf("commitment: got %q, want %q", r.Commitment, seed.Commitment())
+			}
+			if tt.rule == vote.TieBreakRandom && tt.evidence.Revealed != (r.Seed != "") {
+				t.Errorf("seed revealed=%v, got %q", tt.evidence.Revealed, r.Seed)
+			}
+		})
+	}
+}
+
+func TestDraw_IsVerifiable(t *testing.T) {
+	// GIVEN une graine révélée et son engagement publié avant le vote
+	seed, err := tiebreak.NewSeed(uuid.New(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN on refait le tirage, quel que soit l'ordre des ex aequo
+	first := tiebreak.Draw(seed.Value(), 3, []int{4, 5, 6})
+	again := tiebreak.Draw(seed.Value(), 3, []int{6, 4, 5})
+
+	// THEN on retrouve le même gagnant, parmi les ex aequo
+	if first != again || !slices.Contains([]int{4, 5, 6}, first) {
+		t.Errorf("draw is not reproducible: %d then %d", first, again)
+	}
+
+	// AND la graine correspond à son engagement
+	if _, err := tiebreak.Rehydrate(seed.SessionID(), 3, seed.Value(), seed.Commitment(), seed.CreatedAt()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := tiebreak.Rehydrate(seed.SessionID(), 3, seed.Value(), tiebreak.Commit("other"), seed.CreatedAt()); !errors.Is(err, tiebreak.ErrInvalidSeed) {
+		t.Errorf("expected %v, got %v", tiebreak.ErrInvalidSeed, err)
+	}
+}
+
+func TestNewDecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     vote.TieBreak
+		tied     []int
+		choiceID int
+		wantErr  error
+	}{
+		{"un des ex aequo", vote.TieBreakOrganizer, []int{10, 12}, 12, nil},
+		{"hors des ex aequo", vote.TieBreakOrganizer, []int{10, 12}, 11, tiebreak.ErrNotTied},
+		{"sans égalité", vote.TieBreakOrganizer, []int{10}, 10, tiebreak.ErrNoTie},
+		{"autre règle", vote.TieBreakOrder, []int{10, 12}, 10, tiebreak.ErrNotOrganizerTie},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN une question à égalité
+			q := tiedQuestion(tt.rule)
+
+			// WHEN un organisateur choisit
+			d, err := tiebreak.NewDecision(q, tt.tied, tt.choiceID, uuid.New())
+
+			// THEN seule une décision parmi les ex aequo d'une question organizer est acceptée
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (d.ChoiceID != tt.choiceID || d.QuestionID != q.ID) {
+				t.Errorf("got %+v", d)
+			}
+		})
+	}
+}
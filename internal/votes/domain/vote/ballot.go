@@ -0,0 +1,119 @@
+package vote
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// A ballot is the set of votes of one participant for every question of a session.
+
+var (
+	ErrEmptyBallot        = errors.New("vote session has no question")
+	ErrMissingAnswer      = errors.New("every question of the session must be answered")
+	ErrDuplicateAnswer    = errors.New("a question cannot be answered twice")
+	ErrUnexpectedQuestion = errors.New("question does not belong to the vote session")
+	ErrNoBallot           = errors.New("no ballot to change for this vote session")
+)
+
+type Answer struct {
+	QuestionID int
+	ChoiceIDs  []int
+	Points     []int // score and cumulative questions, Points[i] goes to ChoiceIDs[i]
+}
+
+// NewBallot checks answers against the session questions and builds one vote per question.
+func NewBallot(userID, sessionID uuid.UUID, questions []Question, answers []Answer) ([]Vote, error) {
+	if len(questions) == 0 {
+		return nil, ErrEmptyBallot
+	}
+
+	byID := make(map[int]Question, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+
+	answered := make(map[int]struct{}, len(answers))
+	votes := make([]Vote, 0, len(answers))
+
+	for _, a := range answers {
+		q, ok := byID[a.QuestionID]
+		if !ok {
+			return nil, ErrUnexpectedQuestion
+		}
+
+		if _, ok := answered[a.QuestionID]; ok {
+			return nil, ErrDuplicateAnswer
+		}
+		answered[a.QuestionID] = struct{}{}
+
+		v, err := newVoteFor(userID, sessionID, q, a)
+		if err != nil {
+			return nil, err
+		}
+
+		votes = append(votes, v)
+	}
+
+	if len(answered) != len(questions) {
+		return nil, ErrMissingAnswer
+	}
+
+	return votes, nil
+}
+
+// ReplaceBallot supersedes the current votes with a new ballot.
+// Votes of questions that were not answered before are simply cast.
+func ReplaceBallot(current []Vote, userID, sessionID uuid.UUID, questions []Question, answers []Answer) ([]Vote, []Superseded, error) {
+	if len(current) == 0 {
+		return nil, nil, ErrNoBallot
+	}
+
+	next, err := NewBallot(userID, sessionID, questions, answers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byQuestion := make(map[int]Vote, len(current))
+	for _, v := range current {
+		byQuestion[v.questionID] = v
+	}
+
+	superseded := make([]Superseded, 0, len(current))
+	for i, n := range next {
+		old, ok := byQuestion[n.questionID]
+		if !ok {
+			continue
+		}
+
+		// the superseded vote points to its replacement
+		replacement, s, err := old.ReplaceWith(n.choiceIDs, n.points)
+		if err != nil {
+			return nil, nil, err
+		}
+		next[i] = replacement
+		superseded = append(superseded, s)
+		delete(byQuestion, n.questionID)
+	}
+
+	// votes of questions removed from the session since the first ballot
+	for _, old := range byQuestion {
+		superseded = append(superseded, old.Revoke())
+	}
+
+	return next, superseded, nil
+}
+
+// RevokeBallot withdraws every current vote
+func RevokeBallot(current []Vote) ([]Superseded, error) {
+	if len(current) == 0 {
+		return nil, ErrNoBallot
+	}
+
+	superseded := make([]Superseded, 0, len(current))
+	for _, v := range current {
+		superseded = append(superseded, v.Revoke())
+	}
+
+	return superseded, nil
+}
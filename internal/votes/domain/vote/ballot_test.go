@@ -0,0 +1,92 @@
+package vote_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+func TestNewBallot(t *testing.T) {
+	sessionID := uuid.New()
+
+	questions := []vote.Question{
+		{ID: 1, SessionID: sessionID, MaxChoices: 1, Choices: []vote.Choice{{ID: 10}, {ID: 11}}},
+		{ID: 2, SessionID: sessionID, MaxChoices: 2, AllowMultiple: true, Choices: []vote.Choice{{ID: 20}, {ID: 21}}},
+	}
+
+	tests := []struct {
+		name      string
+		questions []vote.Question
+		answers   []vote.Answer
+		wantErr   error
+	}{
+		{"session sans question", nil, []vote.Answer{{QuestionID: 1, ChoiceIDs: []int{10}}}, vote.ErrEmptyBallot},
+		{"question manquante", questions, []vote.Answer{{QuestionID: 1, ChoiceIDs: []int{10}}}, vote.ErrMissingAnswer},
+		{"question inconnue", questions, []vote.Answer{{QuestionID: 3, ChoiceIDs: []int{10}}}, vote.ErrUnexpectedQuestion},
+		{"question en double", questions, []vote.Answer{
+			{QuestionID: 1, ChoiceIDs: []int{10}},
+			{QuestionID: 1, ChoiceIDs: []int{11}},
+		}, vote.ErrDuplicateAnswer},
+		{"regle de la question", questions, []vote.Answer{
+			{QuestionID: 1, ChoiceIDs: []int{10, 11}},
+			{QuestionID: 2, ChoiceIDs: []int{20}},
+		}, vote.ErrMultipleForbidden},
+		{"happy", questions, []vote.Answer{
+			{QuestionID: 1, ChoiceIDs: []int{10}},
+			{QuestionID: 2, ChoiceIDs: []int{20, 21}},
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			votes, err := vote.NewBallot(uuid.New(), sessionID, tt.questions, tt.answers)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if err == nil && len(votes) != len(tt.questions) {
+				t.Errorf("expected %d votes, got %d", len(tt.questions), len(votes))
+			}
+		})
+	}
+}
+
+func TestReplaceBallot(t *testing.T) {
+	userID, sessionID := uuid.New(), uuid.New()
+
+	questions := []vote.Question{
+		{ID: 1, SessionID: sessionID, MaxChoices: 1, Choices: []vote.Choice{{ID: 10}, {ID: 11}}},
+	}
+
+	// GIVEN: Un bulletin déjà envoyé
+	current, err := vote.NewBallot(userID, sessionID, questions, []vote.Answer{{QuestionID: 1, ChoiceIDs: []int{10}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN: Le votant change d'avis
+	next, superseded, err := vote.ReplaceBallot(current, userID, sessionID, questions, []vote.Answer{{QuestionID: 1, ChoiceIDs: []int{11}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN: L'ancien vote pointe vers le nouveau
+	if len(next) != 1 || len(superseded) != 1 {
+		t.Fatalf("expected 1 new vote and 1 superseded, got %d and %d", len(next), len(superseded))
+	}
+
+	if superseded[0].Vote().ID() != current[0].ID() {
+		t.Error("superseded vote should be the previous one")
+	}
+
+	if superseded[0].Reason() != vote.ReasonChanged || superseded[0].ReplacedBy() != next[0].ID() {
+		t.Errorf("superseded vote should be replaced by %v, got %v (%s)", next[0].ID(), superseded[0].ReplacedBy(), superseded[0].Reason())
+	}
+
+	// Sans bulletin préalable
+	if _, _, err := vote.ReplaceBallot(nil, userID, sessionID, questions, nil); !errors.Is(err, vote.ErrNoBallot) {
+		t.Errorf("expected %v, got %v", vote.ErrNoBallot, err)
+	}
+}
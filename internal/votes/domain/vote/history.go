@@ -0,0 +1,69 @@
+package vote
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reason explains why a vote left the "vote" table
+type Reason string
+
+const (
+	ReasonChanged Reason = "changed"
+	ReasonRevoked Reason = "revoked"
+)
+
+var ErrInvalidReason = errors.New("invalid supersede reason")
+
+// Superseded is a vote replaced or withdrawn by its voter.
+// Only the current vote is counted, superseded ones are kept as history.
+type Superseded struct {
+	vote         Vote
+	reason       Reason
+	replacedBy   uuid.UUID // uuid.Nil when revoked
+	supersededAt time.Time
+}
+
+// Getters
+func (s Superseded) Vote() Vote              { return s.vote }
+func (s Superseded) Reason() Reason          { return s.reason }
+func (s Superseded) ReplacedBy() uuid.UUID   { return s.replacedBy }
+func (s Superseded) SupersededAt() time.Time { return s.supersededAt }
+
+// ReplaceWith returns the new vote and the superseded record of v, points are nil unless scored
+func (v Vote) ReplaceWith(choiceIDs, points []int) (Vote, Superseded, error) {
+	next, err := newVote(v.userID, v.sessionID, v.questionID, choiceIDs, v.ranked, points)
+	if err != nil {
+		return Vote{}, Superseded{}, err
+	}
+
+	return next, Superseded{
+		vote:         v,
+		reason:       ReasonChanged,
+		replacedBy:   next.id,
+		supersededAt: next.createdAt,
+	}, nil
+}
+
+func (v Vote) Revoke() Superseded {
+	return Superseded{
+		vote:         v,
+		reason:       ReasonRevoked,
+		supersededAt: time.Now().UTC(),
+	}
+}
+
+func RehydrateSuperseded(v Vote, reason Reason, replacedBy uuid.UUID, supersededAt time.Time) (Superseded, error) {
+	if reason != ReasonChanged && reason != ReasonRevoked {
+		return Superseded{}, ErrInvalidReason
+	}
+
+	return Superseded{
+		vote:         v,
+		reason:       reason,
+		replacedBy:   replacedBy,
+		supersededAt: supersededAt,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package vote
+
+// Threshold mirrors the pass threshold of a question of the questions context
+type Threshold string
+
+const (
+	ThresholdNone          Threshold = "none"
+	ThresholdSimple        Threshold = "simple"
+	ThresholdAbsolute      Threshold = "absolute"
+	ThresholdSupermajority Threshold = "supermajority"
+)
+
+// Passes tells if a choice with votes passes the threshold, ballots are the votes cast
+// on the question and participants the voters of the session, all of them weighted
+func (t Threshold) Passes(votes, ballots, participants int) bool {
+	switch t {
+	case ThresholdSimple:
+		return votes*2 > ballots
+	case ThresholdAbsolute:
+		return votes*2 > participants
+	case ThresholdSupermajority:
+		return ballots > 0 && votes*3 >= ballots*2
+	default:
+		return votes > 0
+	}
+}
+
+// TieBreak mirrors the tie-break rule of a question of the questions context
+type TieBreak string
+
+const (
+	TieBreakNone      TieBreak = "none"
+	TieBreakOrganizer TieBreak = "organizer"
+	TieBreakOrder     TieBreak = "order"
+	TieBreakRandom    TieBreak = "random"
+)
+
+// QuorumKind mirrors the quorum kind of the sessions context
+type QuorumKind string
+
+const (
+	QuorumNone     QuorumKind = "none"
+	QuorumAbsolute QuorumKind = "absolute"
+	QuorumPercent  QuorumKind = "percent"
+)
+
+// Quorum is the read model of the quorum of a session
+type Quorum struct {
+	Kind  QuorumKind
+	Value int
+}
+
+// Required is the number of voters needed among participants
+func (q Quorum) Required(participants int) int {
+	switch q.Kind {
+	case QuorumAbsolute:
+		return q.Value
+	case QuorumPercent:
+		// rounded up: 50% of 5 participants needs 3 voters
+		return (q.Value*participants + 99) / 100
+	default:
+		return 0
+	}
+}
+
+func (q Quorum) Reached(voters, participants int) bool {
+	return voters >= q.Required(participants)
+}
+
+// Status is the outcome of a question
+type Status string
+
+const (
+	StatusPassed           Status = "passed"
+	StatusFailed           Status = "failed"
+	StatusQuorumNotReached Status = "quorum_not_reached"
+	StatusTied             Status = "tied" // waiting for the organizer or a new vote to break the tie
+)
@@ -0,0 +1,63 @@
+package vote_test
+
+import (
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+)
+
+func TestThreshold_Passes(t *testing.T) {
+	tests := []struct {
+		name         string
+		threshold    vote.Threshold
+		votes        int
+		ballots      int
+		participants int
+		want         bool
+	}{
+		{"aucun seuil", vote.ThresholdNone, 1, 10, 20, true},
+		{"aucun seuil sans voix", vote.ThresholdNone, 0, 10, 20, false},
+		{"simple moitie pile", vote.ThresholdSimple, 5, 10, 20, false},
+		{"simple", vote.ThresholdSimple, 6, 10, 20, true},
+		{"absolue sur les participants", vote.ThresholdAbsolute, 6, 10, 20, false},
+		{"absolue", vote.ThresholdAbsolute, 11, 12, 20, true},
+		{"deux tiers pile", vote.ThresholdSupermajority, 6, 9, 20, true},
+		{"sous les deux tiers", vote.ThresholdSupermajority, 5, 8, 20, false},
+		{"deux tiers sans bulletin", vote.ThresholdSupermajority, 0, 0, 20, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.threshold.Passes(tt.votes, tt.ballots, tt.participants); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuorum_Required(t *testing.T) {
+	tests := []struct {
+		name         string
+		quorum       vote.Quorum
+		participants int
+		want         int
+	}{
+		{"aucun", vote.Quorum{Kind: vote.QuorumNone}, 10, 0},
+		{"absolu", vote.Quorum{Kind: vote.QuorumAbsolute, Value: 4}, 10, 4},
+		{"pourcentage arrondi au dessus", vote.Quorum{Kind: vote.QuorumPercent, Value: 50}, 5, 3},
+		{"pourcentage exact", vote.Quorum{Kind: vote.QuorumPercent, Value: 25}, 8, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quorum.Required(tt.participants); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// GIVEN 50% de 5 participants, WHEN 2 votants THEN le quorum n'est pas atteint
+	if (vote.Quorum{Kind: vote.QuorumPercent, Value: 50}).Reached(2, 5) {
+		t.Error("quorum reached with 2 voters out of 5")
+	}
+}
@@ -0,0 +1,41 @@
+package vote
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Counter
+	// CastVote returns ErrAlreadyVoted when the user already voted for the question
+	CastVote(context.Context, Vote) error
+	// CastBallot stores all the votes or none of them
+	CastBallot(context.Context, []Vote) error
+	// CastSecretBallot records that userID voted for the questions of the votes and stores
+	// the votes without voter, it returns ErrAlreadyVoted when userID already took part
+	CastSecretBallot(context.Context, uuid.UUID /* user id */, []Vote) error
+	GetVoteByID(context.Context, uuid.UUID /* vote id */) (Vote, error)
+	GetUserVote(context.Context, uuid.UUID /* user id */, int /* question id */) (Vote, error)
+	GetUserVotesBySessionID(context.Context, uuid.UUID /* user id */, uuid.UUID /* session id */) ([]Vote, error)
+	// HasVoted also counts the participation in a secret ballot
+	HasVoted(context.Context, uuid.UUID /* user id */, int /* question id */) (bool, error)
+	// HasVotedInSession tells if the user has a current vote or a secret participation in the session
+	HasVotedInSession(context.Context, uuid.UUID /* user id */, uuid.UUID /* session id */) (bool, error)
+
+	// ReplaceVotes moves the superseded votes to the history and casts the new ones atomically
+	ReplaceVotes(context.Context, []Superseded, []Vote) error
+	RevokeVotes(context.Context, []Superseded) error
+	GetVoteHistory(context.Context, uuid.UUID /* user id */, uuid.UUID /* session id */) ([]Superseded, error)
+}
+
+// Counter reads the counted votes of a session, what the tally is made of
+type Counter interface {
+	GetVotesByQuestionID(context.Context, int /* question id */) ([]Vote, error)
+	// GetVotesBySessionID returns the counted votes of every voter, ordered by id
+	GetVotesBySessionID(context.Context, uuid.UUID /* session id */) ([]Vote, error)
+	// CountVoters counts users with at least one current vote or secret participation in the session
+	CountVoters(context.Context, uuid.UUID /* session id */) (int, error)
+	// WeighVoters sums the weights of the voters counted by CountVoters
+	WeighVoters(context.Context, uuid.UUID /* session id */) (int, error)
+}
@@ -0,0 +1,36 @@
+package vote
+
+// Visibility mirrors the results visibility of the sessions context
+type Visibility string
+
+const (
+	VisibilityPublic     Visibility = "public"
+	VisibilityAfterClose Visibility = "after_close"
+	VisibilityOrganizers Visibility = "organizers"
+	VisibilityVoters     Visibility = "voters"
+)
+
+// Viewer is the caller asking for the results, the zero Viewer is anonymous
+type Viewer struct {
+	Organizer   bool
+	Participant bool
+	Voted       bool
+}
+
+// Allows tells if viewer sees the results, open is true while the session takes ballots.
+// after_close hides the results from everyone, organizers included, until the session closes.
+// An unknown visibility shows nothing.
+func (v Visibility) Allows(viewer Viewer, open bool) bool {
+	switch v {
+	case VisibilityPublic:
+		return true
+	case VisibilityAfterClose:
+		return !open
+	case VisibilityOrganizers:
+		return viewer.Organizer
+	case VisibilityVoters:
+		return viewer.Organizer || (viewer.Participant && viewer.Voted)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,42 @@
+package vote_test
+
+import (
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+)
+
+func TestVisibility_Allows(t *testing.T) {
+	anonymous := vote.Viewer{}
+	participant := vote.Viewer{Participant: true}
+	voter := vote.Viewer{Participant: true, Voted: true}
+	organizer := vote.Viewer{Organizer: true, Participant: true}
+
+	tests := []struct {
+		name       string
+		visibility vote.Visibility
+		viewer     vote.Viewer
+		open       bool
+		want       bool
+	}{
+		{"public anonyme", vote.VisibilityPublic, anonymous, true, true},
+		{"apres cloture ouverte", vote.VisibilityAfterClose, organizer, true, false},
+		{"apres cloture fermee", vote.VisibilityAfterClose, anonymous, false, true},
+		{"organisateurs anonyme", vote.VisibilityOrganizers, anonymous, false, false},
+		{"organisateurs votant", vote.VisibilityOrganizers, voter, false, false},
+		{"organisateurs organisateur", vote.VisibilityOrganizers, organizer, true, true},
+		{"votants sans vote", vote.VisibilityVoters, participant, true, false},
+		{"votants apres vote", vote.VisibilityVoters, voter, true, true},
+		{"votants organisateur", vote.VisibilityVoters, organizer, true, true},
+		{"votants anonyme apres cloture", vote.VisibilityVoters, anonymous, false, false},
+		{"inconnue", vote.Visibility("everyone"), organizer, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.visibility.Allows(tt.viewer, tt.open); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package vote
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Vote est le bulletin d'un participant pour une question
+type Vote struct {
+	id         uuid.UUID
+	userID     uuid.UUID // uuid.Nil for a secret ballot
+	sessionID  uuid.UUID
+	questionID int
+	choiceIDs  []int // preference order when ranked
+	ranked     bool
+	points     []int     // points of each choice, nil unless scored
+	weight     int       // shares of the voter in the session
+	createdAt  time.Time // zero for a secret ballot
+}
+
+// Kind mirrors the kind of question of the questions context
+type Kind string
+
+const (
+	KindChoice     Kind = "choice"
+	KindRanked     Kind = "ranked"
+	KindApproval   Kind = "approval"
+	KindScore      Kind = "score"
+	KindCumulative Kind = "cumulative"
+)
+
+// Scored tells if the votes of this kind give points to the choices
+func (k Kind) Scored() bool {
+	return k == KindScore || k == KindCumulative
+}
+
+// Method mirrors the tally method of the questions context
+type Method string
+
+const (
+	MethodPlurality   Method = "plurality"
+	MethodIRV         Method = "irv"
+	MethodSchulze     Method = "schulze"
+	MethodRankedPairs Method = "ranked_pairs"
+	MethodSTV         Method = "stv"
+	MethodSum         Method = "sum"
+)
+
+// Question is the read model of a question as the votes context sees it.
+// It is filled by an in-process adapter from the questions context.
+type Question struct {
+	ID            int
+	SessionID     uuid.UUID
+	Text          string
+	Kind          Kind
+	Method        Method
+	AllowMultiple bool
+	MaxChoices    int // 0 for an approval question without limit
+	MaxPoints     int // score: per choice, cumulative: in total
+	Seats         int
+	Threshold     Threshold
+	TieBreak      TieBreak
+	Choices       []Choice
+}
+
+type Choice struct {
+	ID       int
+	Text     string
+	OrderNum int
+}
+
+var (
+	ErrNotFound          = errors.New("vote not found")
+	ErrAlreadyVoted      = errors.New("user already voted for this question")
+	ErrInvalidVoteID     = errors.New("invalid vote id")
+	ErrInvalidUserID     = errors.New("invalid user id")
+	ErrInvalidSessionID  = errors.New("invalid session id")
+	ErrInvalidQuestionID = errors.New("invalid question id")
+	ErrNoChoice          = errors.New("a vote needs at least one choice")
+	ErrDuplicateChoice   = errors.New("a choice cannot be selected twice")
+	ErrMultipleForbidden = errors.New("question does not allow multiple choices")
+	ErrTooManyChoices    = errors.New("too many choices selected")
+	ErrUnknownChoice     = errors.New("choice does not belong to the question")
+	ErrWrongSession      = errors.New("question does not belong to the vote session")
+	ErrWrongQuestion     = errors.New("vote does not target this question")
+	ErrWrongKind         = errors.New("vote does not match the kind of question")
+	ErrPointsMismatch    = errors.New("points must be given for each choice")
+	ErrInvalidPoints     = errors.New("points out of the question range")
+	ErrTooManyPoints     = errors.New("more points than the question allows")
+	ErrInvalidWeight     = errors.New("weight must be >= 1")
+)
+
+// Getters
+func (v Vote) ID() uuid.UUID        { return v.id }
+func (v Vote) UserID() uuid.UUID    { return v.userID }
+func (v Vote) SessionID() uuid.UUID { return v.sessionID }
+func (v Vote) QuestionID() int      { return v.questionID }
+func (v Vote) ChoiceIDs() []int     { return slices.Clone(v.choiceIDs) }
+func (v Vote) Ranked() bool         { return v.ranked }
+func (v Vote) Points() []int        { return slices.Clone(v.points) }
+func (v Vote) Weight() int          { return v.weight }
+func (v Vote) CreatedAt() time.Time { return v.createdAt }
+
+// Secret tells if the vote was stored without its voter
+func (v Vote) Secret() bool { return v.userID == uuid.Nil }
+
+// Anonymous returns the vote without its voter nor the time it was cast,
+// as stored in a secret ballot session
+func (v Vote) Anonymous() Vote {
+	v.userID = uuid.Nil
+	v.createdAt = time.Time{}
+	return v
+}
+
+// WithWeight returns the vote counted weight times, the shares of its voter
+func (v Vote) WithWeight(weight int) (Vote, error) {
+	if weight < 1 {
+		return Vote{}, ErrInvalidWeight
+	}
+	v.weight = weight
+	return v, nil
+}
+
+// Constructeurs
+func NewVote(userID, sessionID uuid.UUID, questionID int, choiceIDs []int) (Vote, error) {
+	return newVote(userID, sessionID, questionID, choiceIDs, false, nil)
+}
+
+// NewRankedVote : choiceIDs are given by order of preference
+func NewRankedVote(userID, sessionID uuid.UUID, questionID int, choiceIDs []int) (Vote, error) {
+	return newVote(userID, sessionID, questionID, choiceIDs, true, nil)
+}
+
+// NewScoredVote : points[i] goes to choiceIDs[i]
+func NewScoredVote(userID, sessionID uuid.UUID, questionID int, choiceIDs, points []int) (Vote, error) {
+	if points == nil {
+		return Vote{}, ErrPointsMismatch
+	}
+	return newVote(userID, sessionID, questionID, choiceIDs, false, points)
+}
+
+// NewVoteFor builds the vote matching the question kind and checks it against the question rules
+func NewVoteFor(userID uuid.UUID, q Question, a Answer) (Vote, error) {
+	return newVoteFor(userID, q.SessionID, q, a)
+}
+
+func newVoteFor(userID, sessionID uuid.UUID, q Question, a Answer) (Vote, error) {
+	v, err := newVote(userID, sessionID, a.QuestionID, a.ChoiceIDs, q.Kind == KindRanked, a.Points)
+	if err != nil {
+		return Vote{}, err
+	}
+
+	if err := v.CheckAgainst(q); err != nil {
+		return Vote{}, err
+	}
+
+	return v, nil
+}
+
+func newVote(userID, sessionID uuid.UUID, questionID int, choiceIDs []int, ranked bool, points []int) (Vote, error) {
+	if userID == uuid.Nil {
+		return Vote{}, ErrInvalidUserID
+	}
+	if sessionID == uuid.Nil {
+		return Vote{}, ErrInvalidSessionID
+	}
+	if questionID <= 0 {
+		return Vote{}, ErrInvalidQuestionID
+	}
+	if err := checkChoiceIDs(choiceIDs); err != nil {
+		return Vote{}, err
+	}
+	if err := checkPoints(choiceIDs, points); err != nil {
+		return Vote{}, err
+	}
+
+	return Vote{
+		id:         uuid.New(),
+		userID:     userID,
+		sessionID:  sessionID,
+		questionID: questionID,
+		choiceIDs:  slices.Clone(choiceIDs),
+		ranked:     ranked,
+		points:     slices.Clone(points),
+		weight:     1,
+		createdAt:  time.Now().UTC(),
+	}, nil
+}
+
+func Rehydrate(
+	id uuid.UUID,
+	userID uuid.UUID,
+	sessionID uuid.UUID,
+	questionID int,
+	choiceIDs []int,
+	ranked bool,
+	points []int,
+	weight int,
+	createdAt time.Time,
+) (Vote, error) {
+	if id == uuid.Nil {
+		return Vote{}, ErrInvalidVoteID
+	}
+	if questionID <= 0 {
+		return Vote{}, ErrInvalidQuestionID
+	}
+	if weight < 1 {
+		return Vote{}, ErrInvalidWeight
+	}
+
+	return Vote{
+		id:         id,
+		userID:     userID,
+		sessionID:  sessionID,
+		questionID: questionID,
+		choiceIDs:  choiceIDs,
+		ranked:     ranked,
+		points:     points,
+		weight:     weight,
+		createdAt:  createdAt,
+	}, nil
+}
+
+// CheckAgainst enforces the question rules (session, kind, limits of the kind, known choices).
+// For a ranked question max_choices is the maximum number of ranks.
+func (v Vote) CheckAgainst(q Question) error {
+	if v.questionID != q.ID {
+		return ErrWrongQuestion
+	}
+	if v.sessionID != q.SessionID {
+		return ErrWrongSession
+	}
+	if v.ranked != (q.Kind == KindRanked) || (v.points != nil) != q.Kind.Scored() {
+		return ErrWrongKind
+	}
+
+	switch q.Kind {
+	case KindRanked:
+		if len(v.choiceIDs) > q.MaxChoices {
+			return ErrTooManyChoices
+		}
+	case KindApproval:
+		if q.MaxChoices > 0 && len(v.choiceIDs) > q.MaxChoices {
+			return ErrTooManyChoices
+		}
+	case KindScore:
+		for _, p := range v.points {
+			if p > q.MaxPoints {
+				return ErrInvalidPoints
+			}
+		}
+	case KindCumulative:
+		total := 0
+		for _, p := range v.points {
+			total += p
+		}
+		if total > q.MaxPoints {
+			return ErrTooManyPoints
+		}
+	default:
+		if !q.AllowMultiple && len(v.choiceIDs) > 1 {
+			return ErrMultipleForbidden
+		}
+		if len(v.choiceIDs) > q.MaxChoices {
+			return ErrTooManyChoices
+		}
+	}
+
+	for _, id := range v.choiceIDs {
+		if !q.HasChoice(id) {
+			return ErrUnknownChoice
+		}
+	}
+
+	return nil
+}
+
+func (q Question) HasChoice(choiceID int) bool {
+	return slices.ContainsFunc(q.Choices, func(c Choice) bool { return c.ID == choiceID })
+}
+
+func checkChoiceIDs(choiceIDs []int) error {
+	if len(choiceIDs) == 0 {
+		return ErrNoChoice
+	}
+
+	seen := make(map[int]struct{}, len(choiceIDs))
+	for _, id := range choiceIDs {
+		if _, ok := seen[id]; ok {
+			return ErrDuplicateChoice
+		}
+		seen[id] = struct{}{}
+	}
+
+	return nil
+}
+
+func checkPoints(choiceIDs, points []int) error {
+	if points == nil {
+		return nil
+	}
+	if len(points) != len(choiceIDs) {
+		return ErrPointsMismatch
+	}
+
+	for _, p := range points {
+		if p < 0 {
+			return ErrInvalidPoints
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package vote_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+func TestNewVote_Validations(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name       string
+		userID     uuid.UUID
+		sessionID  uuid.UUID
+		questionID int
+		choiceIDs  []int
+		wantErr    error
+	}{
+		{"user vide", uuid.Nil, sessionID, 1, []int{1}, vote.ErrInvalidUserID},
+		{"session vide", userID, uuid.Nil, 1, []int{1}, vote.ErrInvalidSessionID},
+		{"question 0", userID, sessionID, 0, []int{1}, vote.ErrInvalidQuestionID},
+		{"aucun choix", userID, sessionID, 1, nil, vote.ErrNoChoice},
+		{"choix en double", userID, sessionID, 1, []int{1, 1}, vote.ErrDuplicateChoice},
+		{"happy", userID, sessionID, 1, []int{1, 2}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vote.NewVote(tt.userID, tt.sessionID, tt.questionID, tt.choiceIDs)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVote_CheckAgainst(t *testing.T) {
+	sessionID := uuid.New()
+
+	single := vote.Question{
+		ID:         1,
+		SessionID:  sessionID,
+		MaxChoices: 1,
+		Choices:    []vote.Choice{{ID: 10}, {ID: 11}, {ID: 12}},
+	}
+
+	multiple := single
+	multiple.AllowMultiple = true
+	multiple.MaxChoices = 2
+
+	otherSession := single
+	otherSession.SessionID = uuid.New()
+
+	tests := []struct {
+		name      string
+		question  vote.Question
+		choiceIDs []int
+		wantErr   error
+	}{
+		{"single ok", single, []int{10}, nil},
+		{"single avec deux choix", single, []int{10, 11}, vote.ErrMultipleForbidden},
+		{"multiple ok", multiple, []int{10, 12}, nil},
+		{"multiple au dela de max_choices", multiple, []int{10, 11, 12}, vote.ErrTooManyChoices},
+		{"choix inconnu", single, []int{99}, vote.ErrUnknownChoice},
+		{"autre session", otherSession, []int{10}, vote.ErrWrongSession},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := vote.NewVote(uuid.New(), sessionID, 1, tt.choiceIDs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = v.CheckAgainst(tt.question)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewVoteFor_Kinds(t *testing.T) {
+	sessionID := uuid.New()
+	choices := []vote.Choice{{ID: 10}, {ID: 11}, {ID: 12}}
+
+	approval := vote.Question{ID: 1, SessionID: sessionID, Kind: vote.KindApproval, Choices: choices}
+
+	limited := approval
+	limited.MaxChoices = 2
+
+	score := vote.Question{ID: 1, SessionID: sessionID, Kind: vote.KindScore, MaxPoints: 5, Choices: choices}
+
+	cumulative := score
+	cumulative.Kind = vote.KindCumulative
+
+	tests := []struct {
+		name     string
+		question vote.Question
+		answer   vote.Answer
+		wantErr  error
+	}{
+		{"approbation de tous les choix", approval, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10, 11, 12}}, nil},
+		{"approbation au dela de max_choices", limited, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10, 11, 12}}, vote.ErrTooManyChoices},
+		{"approbation avec des points", approval, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10}, Points: []int{1}}, vote.ErrWrongKind},
+		{"score ok", score, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10, 11, 12}, Points: []int{5, 0, 3}}, nil},
+		{"score sans points", score, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10}}, vote.ErrWrongKind},
+		{"score au dela du maximum", score, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10}, Points: []int{6}}, vote.ErrInvalidPoints},
+		{"score negatif", score, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10}, Points: []int{-1}}, vote.ErrInvalidPoints},
+		{"points sans choix correspondant", score, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10, 11}, Points: []int{1}}, vote.ErrPointsMismatch},
+		{"cumulatif ok", cumulative, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10, 12}, Points: []int{4, 1}}, nil},
+		{"cumulatif au dela du total", cumulative, vote.Answer{QuestionID: 1, ChoiceIDs: []int{10, 12}, Points: []int{4, 2}}, vote.ErrTooManyPoints},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vote.NewVoteFor(uuid.New(), tt.question, tt.answer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVote_WithWeight(t *testing.T) {
+	// GIVEN un vote d'un participant
+	v, err := vote.NewVote(uuid.New(), uuid.New(), 1, []int{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Weight() != 1 {
+		t.Fatalf("default weight: got %d, want 1", v.Weight())
+	}
+
+	// WHEN on lui donne les parts de son votant
+	weighted, err := v.WithWeight(4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN le vote compte 4 fois, anonyme ou non
+	if weighted.Weight() != 4 || weighted.Anonymous().Weight() != 4 {
+		t.Errorf("got weight %d", weighted.Weight())
+	}
+
+	// AND un poids nul est refusé
+	if _, err := v.WithWeight(0); !errors.Is(err, vote.ErrInvalidWeight) {
+		t.Errorf("expected %v, got %v", vote.ErrInvalidWeight, err)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/73NN0/voting-app/internal/votes/domain/export"
+	"github.com/google/uuid"
+)
+
+// runExport : votes export -session <id> [-what results|ballots] [-format csv|json|blt] [-question <id>] [-o file]
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dsn := fs.String("dsn", "voting.db", "sqlite data source name")
+	sessionStr := fs.String("session", "", "id of the vote session")
+	what := fs.String("what", "results", "results or ballots")
+	formatStr := fs.String("format", "csv", "csv, json or blt (ballots of a ranked question)")
+	questionID := fs.Int("question", 0, "question exported as blt")
+	out := fs.String("o", "", "output file, stdout when empty")
+	fs.Parse(args)
+
+	sessionID, err := uuid.Parse(*sessionStr)
+	if err != nil {
+		return fmt.Errorf("invalid session id %q", *sessionStr)
+	}
+
+	format, err := export.ParseFormat(*formatStr)
+	if err != nil {
+		return err
+	}
+
+	results, cleanup, err := openResults(*dsn)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	ctx := context.Background()
+	switch *what {
+	case "results":
+		return results.ExportResults(ctx, sessionID, format, w)
+	case "ballots":
+		return results.ExportBallots(ctx, sessionID, format, *questionID, w)
+	default:
+		return errors.New("-what must be results or ballots")
+	}
+}
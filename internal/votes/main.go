@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/common/server"
+	questions "github.com/73NN0/voting-app/internal/questions/adapters"
+	sessions "github.com/73NN0/voting-app/internal/sessions/adapters"
+	"github.com/73NN0/voting-app/internal/votes/adapters"
+	"github.com/73NN0/voting-app/internal/votes/app"
+	"github.com/73NN0/voting-app/internal/votes/ports"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			if err := runExport(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "recount":
+			if err := runRecount(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	addr := flag.String("addr", ":4001", "HTTP network address")
+	dsn := flag.String("dsn", "voting.db", "sqlite data source name")
+	devUser := flag.Bool("dev-user-header", false, "trust the "+server.UserIDHeader+" header as the caller id, anyone can forge it: development only")
+	keyFile := flag.String("receipt-key", "receipt.key", "ed25519 seed signing the voter receipts, created when missing")
+	flag.Parse()
+
+	if *devUser {
+		log.Println("warning: " + server.UserIDHeader + " is trusted, do not run in production")
+		server.TrustUserIDHeader(true)
+	}
+
+	database, cleanup, err := db.OpenSQLite(*dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer cleanup()
+
+	if err = db.InitializeSchemas(database); err != nil {
+		log.Fatal(err)
+	}
+
+	votesRepo := adapters.NewSqliteVotesRepository(database)
+
+	signingKey, err := adapters.LoadSigningKey(*keyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	questionsReader := adapters.NewQuestionReaderInProcess(
+		questions.NewSqliteQuestionsRepository(database),
+		questions.NewSqliteChoicesRepositoy(database),
+	)
+
+	sessionsChecker := adapters.NewSessionCheckerInProcess(sessions.NewSqliteSessionRepository(database), clock.System{})
+
+	tieBreaksRepo := adapters.NewSqliteTieBreaksRepository(database)
+
+	live := server.NewBroker()
+
+	service := app.NewService(votesRepo, adapters.NewSqliteReceiptsRepository(database), tieBreaksRepo, signingKey, questionsReader, sessionsChecker, adapters.NewLiveBroker(live))
+	results := app.NewResultsService(votesRepo, adapters.NewSqliteSnapshotsRepository(database), tieBreaksRepo, questionsReader, sessionsChecker, sessionsChecker, clock.System{})
+
+	router := server.NewRouter()
+
+	ports.AddRoutes(router, ports.NewHttpHandler(service, results, live))
+
+	http.ListenAndServe(*addr, router.Handler())
+}
@@ -0,0 +1,859 @@
+package ports
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/logger"
+	"github.com/73NN0/voting-app/internal/common/server"
+	"github.com/73NN0/voting-app/internal/common/server/httperr"
+	"github.com/73NN0/voting-app/internal/common/server/httpstat"
+	"github.com/73NN0/voting-app/internal/votes/app"
+	"github.com/73NN0/voting-app/internal/votes/domain/bulletin"
+	"github.com/73NN0/voting-app/internal/votes/domain/export"
+	"github.com/73NN0/voting-app/internal/votes/domain/receipt"
+	"github.com/73NN0/voting-app/internal/votes/domain/snapshot"
+	"github.com/73NN0/voting-app/internal/votes/domain/tiebreak"
+	"github.com/73NN0/voting-app/internal/votes/domain/vote"
+	"github.com/google/uuid"
+)
+
+type HttpHandler struct {
+	service *app.Service
+	results *app.ResultsService
+	live    *server.Broker
+}
+
+// NewHttpHandler : live is the broker the service publishes the ballot changes on
+func NewHttpHandler(service *app.Service, results *app.ResultsService, live *server.Broker) *HttpHandler {
+	return &HttpHandler{
+		service: service,
+		results: results,
+		live:    live,
+	}
+}
+
+// liveHeartbeat keeps an idle live stream open through proxies
+const liveHeartbeat = 15 * time.Second
+
+type answerRequest struct {
+	QuestionID int   `json:"question_id"`
+	ChoiceIDs  []int `json:"choice_ids"`
+	Points     []int `json:"points,omitempty"` // score and cumulative questions, one per choice
+}
+
+type ballotRequest struct {
+	Answers []answerRequest `json:"answers"`
+}
+
+func ValidateBallot(req ballotRequest) error {
+	if len(req.Answers) == 0 {
+		return errors.New("answers are required")
+	}
+
+	for _, a := range req.Answers {
+		if a.QuestionID <= 0 {
+			return errors.New("question_id must be positive")
+		}
+
+		if len(a.ChoiceIDs) == 0 {
+			return errors.New("choice_ids are required for every answer")
+		}
+
+		if a.Points != nil && len(a.Points) != len(a.ChoiceIDs) {
+			return errors.New("points must have one value per choice_id")
+		}
+	}
+
+	return nil
+}
+
+func (req ballotRequest) toAnswers() []vote.Answer {
+	answers := make([]vote.Answer, 0, len(req.Answers))
+	for _, a := range req.Answers {
+		answers = append(answers, vote.Answer{QuestionID: a.QuestionID, ChoiceIDs: a.ChoiceIDs, Points: a.Points})
+	}
+	return answers
+}
+
+type voteResponse struct {
+	ID         uuid.UUID `json:"id"`
+	QuestionID int       `json:"question_id"`
+	ChoiceIDs  []int     `json:"choice_ids"` // preference order when ranked
+	Ranked     bool      `json:"ranked"`
+	Points     []int     `json:"points,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitzero"` // none for a secret ballot
+}
+
+type ballotResponse struct {
+	SessionID uuid.UUID        `json:"session_id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	Votes     []voteResponse   `json:"votes"`
+	Receipt   *receiptResponse `json:"receipt,omitempty"`
+}
+
+// receiptResponse : payload is the exact signed JSON text, it must be sent back as is to be verified
+type receiptResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Version   int       `json:"version"`
+	Payload   string    `json:"payload"`
+	Signature []byte    `json:"signature"`  // base64
+	PublicKey []byte    `json:"public_key"` // base64 ed25519 key of the server
+	Checksum  string    `json:"checksum"`   // hex sha256 of the payload
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (h *HttpHandler) toReceiptResponse(rc receipt.Receipt) *receiptResponse {
+	return &receiptResponse{
+		ID:        rc.ID(),
+		Version:   rc.Version(),
+		Payload:   string(rc.Payload()),
+		Signature: rc.Signature(),
+		PublicKey: h.service.PublicKey(),
+		Checksum:  rc.Checksum(),
+		CreatedAt: rc.CreatedAt(),
+	}
+}
+
+// snapshotResponse : document is the stored JSON as is, checksum is its hex sha256
+type snapshotResponse struct {
+	ID        uuid.UUID       `json:"id"`
+	Version   int             `json:"version"`
+	Checksum  string          `json:"checksum"`
+	CreatedAt time.Time       `json:"created_at"`
+	Document  json.RawMessage `json:"document"`
+}
+
+func toSnapshotResponse(s snapshot.Snapshot) snapshotResponse {
+	return snapshotResponse{
+		ID:        s.ID(),
+		Version:   s.Version(),
+		Checksum:  s.Checksum(),
+		CreatedAt: s.CreatedAt(),
+		Document:  s.Document(),
+	}
+}
+
+type verifyReceiptRequest struct {
+	Payload   string `json:"payload"`
+	Signature []byte `json:"signature"` // base64
+}
+
+type verifyReceiptResponse struct {
+	Valid   bool            `json:"valid"`
+	Receipt receipt.Payload `json:"receipt"`
+}
+
+func toVoteResponse(v vote.Vote) voteResponse {
+	return voteResponse{
+		ID:         v.ID(),
+		QuestionID: v.QuestionID(),
+		ChoiceIDs:  v.ChoiceIDs(),
+		Ranked:     v.Ranked(),
+		Points:     v.Points(),
+		CreatedAt:  v.CreatedAt(),
+	}
+}
+
+func toBallotResponse(userID, sessionID uuid.UUID, votes []vote.Vote) ballotResponse {
+	resp := ballotResponse{
+		SessionID: sessionID,
+		UserID:    userID,
+		Votes:     make([]voteResponse, 0, len(votes)),
+	}
+
+	for _, v := range votes {
+		resp.Votes = append(resp.Votes, toVoteResponse(v))
+	}
+
+	return resp
+}
+
+// writeVoteError maps domain and app errors to HTTP status codes
+func writeVoteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, app.ErrNotParticipant),
+		errors.Is(err, app.ErrNotOrganizer),
+		errors.Is(err, app.ErrResultsHidden):
+		httperr.Forbidden(w, err.Error())
+	case errors.Is(err, app.ErrQuestionNotFound),
+		errors.Is(err, app.ErrSessionNotFound),
+		errors.Is(err, vote.ErrNotFound),
+		errors.Is(err, vote.ErrNoBallot),
+		errors.Is(err, receipt.ErrNotFound),
+		errors.Is(err, snapshot.ErrNotFound),
+		errors.Is(err, bulletin.ErrNotOnBoard):
+		httperr.NotFound(w, err.Error())
+	case errors.Is(err, vote.ErrAlreadyVoted),
+		errors.Is(err, app.ErrSessionClosed),
+		errors.Is(err, app.ErrSessionNotOpen),
+		errors.Is(err, app.ErrSecretBallot),
+		errors.Is(err, app.ErrSessionFinalized),
+		errors.Is(err, app.ErrSessionOpen),
+		errors.Is(err, app.ErrTiePending),
+		errors.Is(err, tiebreak.ErrAlreadyDecided),
+		errors.Is(err, snapshot.ErrAlreadyFinalized):
+		httperr.Conflict(w, err.Error())
+	case errors.Is(err, vote.ErrEmptyBallot),
+		errors.Is(err, vote.ErrMissingAnswer),
+		errors.Is(err, vote.ErrDuplicateAnswer),
+		errors.Is(err, vote.ErrUnexpectedQuestion),
+		errors.Is(err, vote.ErrNoChoice),
+		errors.Is(err, vote.ErrDuplicateChoice),
+		errors.Is(err, vote.ErrMultipleForbidden),
+		errors.Is(err, vote.ErrTooManyChoices),
+		errors.Is(err, vote.ErrUnknownChoice),
+		errors.Is(err, vote.ErrWrongSession),
+		errors.Is(err, vote.ErrWrongKind),
+		errors.Is(err, vote.ErrPointsMismatch),
+		errors.Is(err, vote.ErrInvalidPoints),
+		errors.Is(err, vote.ErrTooManyPoints),
+		errors.Is(err, receipt.ErrInvalidSignature),
+		errors.Is(err, app.ErrQuestionRequired),
+		errors.Is(err, export.ErrUnsupported),
+		errors.Is(err, export.ErrNotRanked),
+		errors.Is(err, tiebreak.ErrNotTied),
+		errors.Is(err, tiebreak.ErrNoTie),
+		errors.Is(err, tiebreak.ErrNotOrganizerTie),
+		errors.Is(err, receipt.ErrUnsupportedVersion):
+		httperr.UnprocessableEntity(w, err.Error())
+	default:
+		httperr.InternalServerError(w, err.Error())
+	}
+}
+
+// sessionAndUser reads {sessionID} and the caller id, it writes the error response itself
+func sessionAndUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	idStr := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Logger.Warn("invalid session ID", "idStr", idStr)
+		httperr.BadRequest(w, "invalid session ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := server.UserID(r)
+	if err != nil {
+		httperr.Unauthorized(w, err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return sessionID, userID, true
+}
+
+// viewer is the caller id when there is one, uuid.Nil for an anonymous caller:
+// without a trusted identity the restricted visibilities hide the results
+func viewer(r *http.Request) uuid.UUID {
+	userID, err := server.UserID(r)
+	if err != nil {
+		return uuid.Nil
+	}
+	return userID
+}
+
+func (h *HttpHandler) SubmitBallot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req ballotRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	if err := ValidateBallot(req); err != nil {
+		logger.Logger.Warn("validation failed", "err", err)
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	votes, rc, err := h.service.SubmitBallot(ctx, userID, sessionID, req.toAnswers())
+	if err != nil {
+		logger.Logger.Error("submit ballot failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	resp := toBallotResponse(userID, sessionID, votes)
+	resp.Receipt = h.toReceiptResponse(rc)
+
+	httpstat.CreatedJSON(w, resp)
+}
+
+func (h *HttpHandler) GetBallot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	votes, err := h.service.GetBallot(ctx, userID, sessionID)
+	if err != nil {
+		logger.Logger.Error("get ballot failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	if len(votes) == 0 {
+		httperr.NotFound(w, "no ballot for this session")
+		return
+	}
+
+	httpstat.OkJSON(w, toBallotResponse(userID, sessionID, votes))
+}
+
+func (h *HttpHandler) ReplaceBallot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req ballotRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	if err := ValidateBallot(req); err != nil {
+		logger.Logger.Warn("validation failed", "err", err)
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	votes, rc, err := h.service.ReplaceBallot(ctx, userID, sessionID, req.toAnswers())
+	if err != nil {
+		logger.Logger.Error("replace ballot failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	resp := toBallotResponse(userID, sessionID, votes)
+	resp.Receipt = h.toReceiptResponse(rc)
+
+	httpstat.OkJSON(w, resp)
+}
+
+func (h *HttpHandler) RevokeBallot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeBallot(ctx, userID, sessionID); err != nil {
+		logger.Logger.Error("revoke ballot failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	httpstat.NoContent(w, "ballot revoked")
+}
+
+func (h *HttpHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	rc, err := h.service.GetReceipt(ctx, userID, sessionID)
+	if err != nil {
+		logger.Logger.Error("get receipt failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, h.toReceiptResponse(rc))
+}
+
+// VerifyReceipt needs no caller id: anyone holding a receipt can check it
+func (h *HttpHandler) VerifyReceipt(w http.ResponseWriter, r *http.Request) {
+	var req verifyReceiptRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	if req.Payload == "" || len(req.Signature) == 0 {
+		httperr.BadRequest(w, "payload and signature are required")
+		return
+	}
+
+	payload, err := h.service.VerifyReceipt([]byte(req.Payload), req.Signature)
+	if err != nil {
+		logger.Logger.Warn("receipt verification failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, verifyReceiptResponse{Valid: true, Receipt: payload})
+}
+
+type supersededResponse struct {
+	voteResponse
+	Reason       string     `json:"reason"`
+	ReplacedBy   *uuid.UUID `json:"replaced_by,omitempty"`
+	SupersededAt time.Time  `json:"superseded_at"`
+}
+
+func (h *HttpHandler) GetBallotHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.service.GetBallotHistory(ctx, userID, sessionID)
+	if err != nil {
+		logger.Logger.Error("get ballot history failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	resp := make([]supersededResponse, 0, len(history))
+	for _, s := range history {
+		item := supersededResponse{
+			voteResponse: toVoteResponse(s.Vote()),
+			Reason:       string(s.Reason()),
+			SupersededAt: s.SupersededAt(),
+		}
+
+		if id := s.ReplacedBy(); id != uuid.Nil {
+			item.ReplacedBy = &id
+		}
+
+		resp = append(resp, item)
+	}
+
+	httpstat.OkJSON(w, resp)
+}
+
+func (h *HttpHandler) GetSessionResults(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	idStr := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Logger.Warn("invalid session ID", "idStr", idStr)
+		httperr.BadRequest(w, "invalid session ID")
+		return
+	}
+
+	if err := h.results.CheckResultsVisible(ctx, sessionID, viewer(r)); err != nil {
+		logger.Logger.Warn("session results refused", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	result, err := h.results.SessionResults(ctx, sessionID)
+	if err != nil {
+		logger.Logger.Error("session results failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, result)
+}
+
+// FinalizeSession closes the session and freezes its result, for an organizer of the session
+func (h *HttpHandler) FinalizeSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	snap, err := h.results.FinalizeSession(ctx, userID, sessionID)
+	if err != nil {
+		logger.Logger.Error("finalize session failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	// live streams send the final update and end
+	h.live.Publish(sessionID.String())
+
+	httpstat.CreatedJSON(w, toSnapshotResponse(snap))
+}
+
+func (h *HttpHandler) GetFinalResult(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	idStr := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Logger.Warn("invalid session ID", "idStr", idStr)
+		httperr.BadRequest(w, "invalid session ID")
+		return
+	}
+
+	if err := h.results.CheckResultsVisible(ctx, sessionID, viewer(r)); err != nil {
+		logger.Logger.Warn("final result refused", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	snap, err := h.results.GetSnapshot(ctx, sessionID)
+	if err != nil {
+		logger.Logger.Error("get final result failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, toSnapshotResponse(snap))
+}
+
+// bulletinResponse : with ?vote_id= the inclusion proof of the ballot comes with the board
+type bulletinResponse struct {
+	app.BulletinBoard
+	Inclusion *app.InclusionProof `json:"inclusion,omitempty"`
+}
+
+// GetBulletin is public: anyone can download the board and check the root published with the results
+func (h *HttpHandler) GetBulletin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	idStr := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Logger.Warn("invalid session ID", "idStr", idStr)
+		httperr.BadRequest(w, "invalid session ID")
+		return
+	}
+
+	board, err := h.results.Bulletin(ctx, sessionID)
+	if err != nil {
+		logger.Logger.Error("get bulletin failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	resp := bulletinResponse{BulletinBoard: board}
+
+	if voteStr := r.URL.Query().Get("vote_id"); voteStr != "" {
+		voteID, err := uuid.Parse(voteStr)
+		if err != nil {
+			logger.Logger.Warn("invalid vote ID", "voteStr", voteStr)
+			httperr.BadRequest(w, "invalid vote ID")
+			return
+		}
+
+		proof, err := h.results.InclusionProof(ctx, sessionID, voteID)
+		if err != nil {
+			logger.Logger.Error("inclusion proof failed", "err", err)
+			writeVoteError(w, err)
+			return
+		}
+		resp.Inclusion = &proof
+	}
+
+	httpstat.OkJSON(w, resp)
+}
+
+// GetTieBreakSeeds publishes the commitments of the random tie-breaks, with their seed once the session is closed
+func (h *HttpHandler) GetTieBreakSeeds(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	idStr := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Logger.Warn("invalid session ID", "idStr", idStr)
+		httperr.BadRequest(w, "invalid session ID")
+		return
+	}
+
+	seeds, err := h.results.TieBreakSeeds(ctx, sessionID)
+	if err != nil {
+		logger.Logger.Error("get tie-break seeds failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	httpstat.OkJSON(w, seeds)
+}
+
+type tieBreakRequest struct {
+	ChoiceID int `json:"choice_id"`
+}
+
+// DecideTie : an organizer picks the winner of a tied question with the organizer rule
+func (h *HttpHandler) DecideTie(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, userID, ok := sessionAndUser(w, r)
+	if !ok {
+		return
+	}
+
+	qStr := r.PathValue("questionID")
+	questionID, err := strconv.Atoi(qStr)
+	if err != nil {
+		logger.Logger.Warn("invalid question ID", "qStr", qStr)
+		httperr.BadRequest(w, "invalid question ID")
+		return
+	}
+
+	var req tieBreakRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("invalid JSON", "err", err)
+		httperr.BadRequest(w, "invalid JSON")
+		return
+	}
+
+	if req.ChoiceID <= 0 {
+		httperr.BadRequest(w, "choice_id is required")
+		return
+	}
+
+	decision, err := h.results.DecideTie(ctx, sessionID, questionID, userID, req.ChoiceID)
+	if err != nil {
+		logger.Logger.Error("decide tie failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	httpstat.CreatedJSON(w, decision)
+}
+
+// GetExport downloads the results (what=results) or the anonymized ballots (what=ballots)
+// as ?format=csv|json|blt, blt also needs ?question_id= of a ranked question.
+func (h *HttpHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	idStr := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Logger.Warn("invalid session ID", "idStr", idStr)
+		httperr.BadRequest(w, "invalid session ID")
+		return
+	}
+
+	formatStr := r.URL.Query().Get("format")
+	if formatStr == "" {
+		formatStr = string(export.FormatCSV)
+	}
+
+	format, err := export.ParseFormat(formatStr)
+	if err != nil {
+		logger.Logger.Warn("invalid export format", "format", formatStr)
+		httperr.BadRequest(w, err.Error())
+		return
+	}
+
+	questionID := 0
+	if qStr := r.URL.Query().Get("question_id"); qStr != "" {
+		questionID, err = strconv.Atoi(qStr)
+		if err != nil {
+			logger.Logger.Warn("invalid question ID", "qStr", qStr)
+			httperr.BadRequest(w, "invalid question ID")
+			return
+		}
+	}
+
+	// ballots tell the results as much as the results do
+	if err := h.results.CheckResultsVisible(ctx, sessionID, viewer(r)); err != nil {
+		logger.Logger.Warn("export refused", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	// the file is built before answering: an error can still change the status
+	var buf bytes.Buffer
+	what := r.PathValue("what")
+	switch what {
+	case "results":
+		err = h.results.ExportResults(ctx, sessionID, format, &buf)
+	case "ballots":
+		err = h.results.ExportBallots(ctx, sessionID, format, questionID, &buf)
+	default:
+		httperr.NotFound(w, "unknown export")
+		return
+	}
+
+	if err != nil {
+		logger.Logger.Error("export failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", sessionID, what, format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// StreamLive pushes the turnout, and the running tallies when the session shows them to the caller,
+// as an "update" event on connection and after every ballot change.
+// The stream ends after the update of a closed session.
+func (h *HttpHandler) StreamLive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	idStr := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(idStr)
+	if err != nil {
+		logger.Logger.Warn("invalid session ID", "idStr", idStr)
+		httperr.BadRequest(w, "invalid session ID")
+		return
+	}
+
+	viewerID := viewer(r)
+
+	changes, unsubscribe := h.live.Subscribe(sessionID.String())
+	defer unsubscribe()
+
+	// the first update is computed before the stream starts: errors still get a status
+	update, err := h.results.LiveUpdate(ctx, sessionID, viewerID)
+	if err != nil {
+		logger.Logger.Error("live update failed", "err", err)
+		writeVoteError(w, err)
+		return
+	}
+
+	stream := server.NewEventStream(w)
+	if err := stream.Send("update", update); err != nil || !update.Open {
+		return
+	}
+
+	heartbeat := time.NewTicker(liveHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if err := stream.Ping(); err != nil {
+				return
+			}
+		case <-changes:
+			update, err := h.results.LiveUpdate(ctx, sessionID, viewerID)
+			if err != nil {
+				logger.Logger.Error("live update failed", "err", err)
+				stream.Send("error", map[string]string{"error": err.Error()})
+				return
+			}
+
+			if err := stream.Send("update", update); err != nil || !update.Open {
+				return
+			}
+		}
+	}
+}
+
+// Routes are registered with their full path: /sessions/ is also the prefix
+// of the sessions context, a second Group on it would conflict.
+func AddRoutes(r *server.Router, h *HttpHandler) {
+	// URL: POST /sessions/{sessionID}/ballot
+	r.Handle("POST /sessions/{sessionID}/ballot",
+		http.HandlerFunc(h.SubmitBallot),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/ballot
+	r.Handle("GET /sessions/{sessionID}/ballot",
+		http.HandlerFunc(h.GetBallot),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: PUT /sessions/{sessionID}/ballot
+	r.Handle("PUT /sessions/{sessionID}/ballot",
+		http.HandlerFunc(h.ReplaceBallot),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: DELETE /sessions/{sessionID}/ballot
+	r.Handle("DELETE /sessions/{sessionID}/ballot",
+		http.HandlerFunc(h.RevokeBallot),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/results
+	r.Handle("GET /sessions/{sessionID}/results",
+		http.HandlerFunc(h.GetSessionResults),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/bulletin
+	r.Handle("GET /sessions/{sessionID}/bulletin",
+		http.HandlerFunc(h.GetBulletin),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/live
+	r.Handle("GET /sessions/{sessionID}/live",
+		http.HandlerFunc(h.StreamLive),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/export/{what}
+	r.Handle("GET /sessions/{sessionID}/export/{what}",
+		http.HandlerFunc(h.GetExport),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/tiebreak
+	r.Handle("GET /sessions/{sessionID}/tiebreak",
+		http.HandlerFunc(h.GetTieBreakSeeds),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /sessions/{sessionID}/tiebreak/{questionID}
+	r.Handle("POST /sessions/{sessionID}/tiebreak/{questionID}",
+		http.HandlerFunc(h.DecideTie),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /sessions/{sessionID}/finalize
+	r.Handle("POST /sessions/{sessionID}/finalize",
+		http.HandlerFunc(h.FinalizeSession),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/results/final
+	r.Handle("GET /sessions/{sessionID}/results/final",
+		http.HandlerFunc(h.GetFinalResult),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/ballot/receipt
+	r.Handle("GET /sessions/{sessionID}/ballot/receipt",
+		http.HandlerFunc(h.GetReceipt),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: POST /receipts/verify
+	r.Handle("POST /receipts/verify",
+		http.HandlerFunc(h.VerifyReceipt),
+		server.Logging, server.Recovery, server.CORS,
+	)
+
+	// URL: GET /sessions/{sessionID}/ballot/history
+	r.Handle("GET /sessions/{sessionID}/ballot/history",
+		http.HandlerFunc(h.GetBallotHistory),
+		server.Logging, server.Recovery, server.CORS,
+	)
+}
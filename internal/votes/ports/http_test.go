@@ -0,0 +1,204 @@
+package ports_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/73NN0/voting-app/internal/common/clock"
+	"github.com/73NN0/voting-app/internal/common/db"
+	"github.com/73NN0/voting-app/internal/common/server"
+	questions "github.com/73NN0/voting-app/internal/questions/adapters"
+	"github.com/73NN0/voting-app/internal/questions/domain/choice"
+	"github.com/73NN0/voting-app/internal/questions/domain/question"
+	sessions "github.com/73NN0/voting-app/internal/sessions/adapters"
+	"github.com/73NN0/voting-app/internal/sessions/domain/session"
+	"github.com/73NN0/voting-app/internal/votes/adapters"
+	"github.com/73NN0/voting-app/internal/votes/app"
+	"github.com/73NN0/voting-app/internal/votes/domain/receipt"
+	"github.com/73NN0/voting-app/internal/votes/ports"
+	"github.com/google/uuid"
+)
+
+// newRouter builds the votes API on a new database, the caller is read from server.UserIDHeader
+func newRouter(t *testing.T) (http.Handler, *sql.DB) {
+	t.Helper()
+
+	// a file: each connection to :memory: opens its own database
+	database, cleanup, err := db.OpenSQLite(filepath.Join(t.TempDir(), "voting.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanup)
+
+	if err := db.InitializeSchemas(database); err != nil {
+		t.Fatal(err)
+	}
+
+	server.TrustUserIDHeader(true)
+	t.Cleanup(func() { server.TrustUserIDHeader(false) })
+
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	votesRepo := adapters.NewSqliteVotesRepository(database)
+	tieBreaksRepo := adapters.NewSqliteTieBreaksRepository(database)
+	questionsReader := adapters.NewQuestionReaderInProcess(
+		questions.NewSqliteQuestionsRepository(database),
+		questions.NewSqliteChoicesRepositoy(database),
+	)
+	checker := adapters.NewSessionCheckerInProcess(sessions.NewSqliteSessionRepository(database), clock.System{})
+	live := server.NewBroker()
+
+	service := app.NewService(votesRepo, adapters.NewSqliteReceiptsRepository(database), tieBreaksRepo, key, questionsReader, checker, adapters.NewLiveBroker(live))
+	results := app.NewResultsService(votesRepo, adapters.NewSqliteSnapshotsRepository(database), tieBreaksRepo, questionsReader, checker, checker, clock.System{})
+
+	router := server.NewRouter()
+	ports.AddRoutes(router, ports.NewHttpHandler(service, results, live))
+
+	return router.Handler(), database
+}
+
+func call(h http.Handler, method, path string, userID uuid.UUID, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r.Header.Set(server.UserIDHeader, userID.String())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestFinalizeSession_OrganizerOnly(t *testing.T) {
+	ctx := context.Background()
+	router, database := newRouter(t)
+	repo := sessions.NewSqliteSessionRepository(database)
+
+	// GIVEN une session ouverte, son organisateur et un participant
+	vs, err := session.NewSessionNoEnd("AG", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.Open(time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateVoteSession(ctx, vs); err != nil {
+		t.Fatal(err)
+	}
+	organizer, participant := uuid.New(), uuid.New()
+	if err := repo.AddOrganizer(ctx, vs.ID(), organizer); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddParticipant(ctx, vs.ID(), participant); err != nil {
+		t.Fatal(err)
+	}
+	path := "/sessions/" + vs.ID().String() + "/finalize"
+
+	// WHEN le participant la finalise
+	w := call(router, "POST", path, participant, "")
+
+	// THEN il est refusé et la session reste ouverte
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("participant finalize = %d, want %d: %s", w.Code, http.StatusForbidden, w.Body)
+	}
+	got, err := repo.GetVoteSessionByID(ctx, vs.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State() != session.StateOpen {
+		t.Errorf("state = %s, want %s", got.State(), session.StateOpen)
+	}
+
+	// WHEN l'organisateur la finalise
+	w = call(router, "POST", path, organizer, "")
+
+	// THEN le résultat est figé
+	if w.Code != http.StatusCreated {
+		t.Fatalf("organizer finalize = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+}
+
+func TestRevokeBallot_ReceiptClaimsNoChoice(t *testing.T) {
+	ctx := context.Background()
+	router, database := newRouter(t)
+	repo := sessions.NewSqliteSessionRepository(database)
+
+	// GIVEN une session ouverte et le bulletin d'un participant
+	vs, err := session.NewSessionNoEnd("AG", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.Open(time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateVoteSession(ctx, vs); err != nil {
+		t.Fatal(err)
+	}
+	participant := uuid.New()
+	if err := repo.AddParticipant(ctx, vs.ID(), participant); err != nil {
+		t.Fatal(err)
+	}
+	q, err := question.NewQuestion(vs.ID(), "Budget ?", 1, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	questionID, err := questions.NewSqliteQuestionsRepository(database).CreateQuestion(ctx, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	choiceID, err := questions.NewSqliteChoicesRepositoy(database).CreateChoice(ctx, choice.NewChoice(questionID, 1, "Oui"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "/sessions/" + vs.ID().String() + "/ballot"
+	body := fmt.Sprintf(`{"answers":[{"question_id":%d,"choice_ids":[%d]}]}`, questionID, choiceID)
+	if w := call(router, "POST", path, participant, body); w.Code != http.StatusCreated {
+		t.Fatalf("submit ballot = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+
+	// WHEN il retire son bulletin
+	if w := call(router, "DELETE", path, participant, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("revoke ballot = %d, want %d: %s", w.Code, http.StatusNoContent, w.Body)
+	}
+
+	// THEN son reçu, toujours signé, ne prouve plus aucun choix
+	w := call(router, "GET", path+"/receipt", participant, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get receipt = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var rc struct {
+		Payload   string `json:"payload"`
+		Signature []byte `json:"signature"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&rc); err != nil {
+		t.Fatal(err)
+	}
+	verify, err := json.Marshal(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = call(router, "POST", "/receipts/verify", participant, string(verify))
+	if w.Code != http.StatusOK {
+		t.Fatalf("verify receipt = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var verified struct {
+		Valid   bool            `json:"valid"`
+		Receipt receipt.Payload `json:"receipt"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&verified); err != nil {
+		t.Fatal(err)
+	}
+	if !verified.Valid || !verified.Receipt.Revoked() {
+		t.Errorf("receipt after revoke = %+v, want a valid receipt without entries", verified)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+)
+
+// errMismatch makes the command exit with 1 once the report is printed
+var errMismatch = errors.New("recount does not match the stored result")
+
+// runRecount : votes recount -session <id> [-json]
+func runRecount(args []string) error {
+	fs := flag.NewFlagSet("recount", flag.ExitOnError)
+	dsn := fs.String("dsn", "voting.db", "sqlite data source name")
+	sessionStr := fs.String("session", "", "id of the finalized vote session")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	fs.Parse(args)
+
+	sessionID, err := uuid.Parse(*sessionStr)
+	if err != nil {
+		return fmt.Errorf("invalid session id %q", *sessionStr)
+	}
+
+	results, cleanup, err := openResults(*dsn)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	report, err := results.Recount(context.Background(), sessionID)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("session   %s\n", report.SessionID)
+		fmt.Printf("snapshot  %s, finalized at %s\n", report.SnapshotID, report.FinalizedAt.Format("2006-01-02 15:04:05Z07:00"))
+		if report.Integrity == "" {
+			fmt.Printf("checksum  %s ok\n", report.Checksum)
+		} else {
+			fmt.Printf("checksum  %s FAILED: %s\n", report.Checksum, report.Integrity)
+		}
+
+		for _, d := range report.Discrepancies {
+			fmt.Printf("  %s: stored %s, recounted %s\n", d.Path, orMissing(d.Stored), orMissing(d.Recounted))
+		}
+		if len(report.Discrepancies) == 0 && report.Integrity == "" {
+			fmt.Println("recount matches the stored result")
+		}
+	}
+
+	if !report.Matches() {
+		return errMismatch
+	}
+
+	return nil
+}
+
+func orMissing(v json.RawMessage) string {
+	if v == nil {
+		return "(missing)"
+	}
+	return string(v)
+}